@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"satpen-api/internal/config"
+	"satpen-api/internal/dapodik"
 	"satpen-api/internal/database"
 	"satpen-api/internal/handler"
 	"satpen-api/internal/middleware"
@@ -39,23 +40,35 @@ func main() {
 	}
 	logger.Info("Database connected successfully")
 
-	// Auto migrate
-	if err := database.AutoMigrate(db); err != nil {
+	// Apply schema migrations
+	if err := database.AutoMigrate(db, cfg); err != nil {
 		logger.Fatalf("Failed to run auto migration: %v", err)
 	}
 
 	// Initialize repositories
 	satpenRepo := repository.NewSatpenRepository(db)
 	masterRepo := repository.NewMasterRepository(db)
+	syncRepo := repository.NewSyncRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
 
 	// Initialize services
 	satpenService := service.NewSatpenService(satpenRepo, cfg)
 	masterService := service.NewMasterService(masterRepo)
+	syncService := service.NewSyncService(syncRepo, dapodik.NewHTTPClient(cfg), cfg, logger)
+	suggestService := service.NewSuggestService(satpenRepo, cfg, logger)
+	clusterService := service.NewClusterService(satpenRepo, cfg, logger)
+	dashboardService := service.NewDashboardService(satpenRepo, masterRepo, cfg)
+	auditService := service.NewAuditService(auditRepo, cfg)
 
 	// Initialize handlers
 	satpenHandler := handler.NewSatpenHandler(satpenService)
 	masterHandler := handler.NewMasterHandler(masterService, logger)
 	healthHandler := handler.NewHealthHandler(cfg)
+	syncHandler := handler.NewSyncHandler(syncService, logger)
+	suggestHandler := handler.NewSuggestHandler(suggestService)
+	clusterHandler := handler.NewClusterHandler(clusterService)
+	dashboardHandler := handler.NewDashboardHandler(dashboardService)
+	auditHandler := handler.NewAuditHandler(auditService)
 
 	// Setup Gin
 	if cfg.App.Env == "production" {
@@ -65,7 +78,7 @@ func main() {
 	r := gin.New()
 
 	// Setup routes
-	routes.SetupRoutes(r, cfg, logger, satpenHandler, masterHandler, healthHandler)
+	routes.SetupRoutes(r, cfg, logger, satpenHandler, masterHandler, healthHandler, syncHandler, suggestHandler, clusterHandler, dashboardHandler, auditHandler)
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -77,6 +90,24 @@ func main() {
 		logger.Info("Rate limiter cleanup routine started")
 	}
 
+	// Build autocomplete index, then keep it fresh
+	if err := suggestService.Refresh(); err != nil {
+		logger.WithError(err).Error("Failed to build suggest index")
+	}
+	go suggestService.Start(ctx)
+
+	// Build map cluster index, then keep it fresh
+	if err := clusterService.Refresh(); err != nil {
+		logger.WithError(err).Error("Failed to build cluster index")
+	}
+	go clusterService.Start(ctx)
+
+	// Start scheduled Dapodik synchronisation
+	if cfg.Dapodik.Enabled {
+		go syncService.Start(ctx)
+		logger.Infof("Dapodik sync scheduler started (every %ds)", cfg.Dapodik.Interval)
+	}
+
 	// Create HTTP server
 	addr := fmt.Sprintf(":%d", cfg.App.Port)
 	srv := &http.Server{
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"satpen-api/internal/dapodik"
+	"satpen-api/internal/dapodik/dapodiktest"
+)
+
+// dapodik-stub serves a fake Dapodik upstream for local development:
+//
+//	go run ./cmd/dapodik-stub -addr :9090 -data fixtures.json
+//
+// The data file is a JSON array of dapodik.PDPTK records.
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	token := flag.String("token", "", "bearer token required from clients (empty: no auth)")
+	dataPath := flag.String("data", "", "JSON file with PDPTK records to serve")
+	flag.Parse()
+
+	server := dapodiktest.New(*token)
+
+	if *dataPath != "" {
+		raw, err := os.ReadFile(*dataPath)
+		if err != nil {
+			log.Fatalf("Failed to read data file: %v", err)
+		}
+		var records []dapodik.PDPTK
+		if err := json.Unmarshal(raw, &records); err != nil {
+			log.Fatalf("Failed to parse data file: %v", err)
+		}
+		for _, record := range records {
+			server.Put(record)
+		}
+		log.Printf("Loaded %d PDPTK records", len(records))
+	}
+
+	log.Printf("Dapodik stub listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, server.Handler()); err != nil {
+		log.Fatalf("Stub server stopped: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"satpen-api/internal/config"
+	"satpen-api/internal/database"
+	"satpen-api/internal/duplicate"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+	"satpen-api/internal/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// duplicates prints candidate duplicate satpen, as GET
+// /api/v1/admin/satpen/duplicates does:
+//
+//	go run ./cmd/duplicates -kabupaten "Kab. Blitar" -min-score 0.75 -format csv > pairs.csv
+//
+// List flags take comma separated values like the query parameters.
+func main() {
+	configPath := flag.String("config", "config.yaml", "config file")
+	provinsi := flag.String("provinsi", "", "provinsi IDs or names")
+	kabupaten := flag.String("kabupaten", "", "kabupaten IDs or names")
+	jenjang := flag.String("jenjang", "", "jenjang names")
+	status := flag.String("status", "", "statuses (empty: all)")
+	minScore := flag.Float64("min-score", 0, "minimum pair score 0-1 (0: duplicates.min_score)")
+	limit := flag.Int("limit", 0, "maximum pairs (0: duplicates.max_pairs)")
+	format := flag.String("format", "text", "output format: text, csv or json")
+	flag.Parse()
+
+	if *format != "text" && *format != "csv" && *format != "json" {
+		log.Fatalf("Unknown format %q: must be text, csv or json", *format)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+	// Keep SQL logging off stdout, which carries the report
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+	satpenService := service.NewSatpenService(repository.NewSatpenRepository(db), cfg)
+	filter := &models.SatpenFilter{
+		Provinsi:  splitList(*provinsi),
+		Kabupaten: splitList(*kabupaten),
+		Jenjang:   splitList(*jenjang),
+		Status:    splitList(*status),
+	}
+	report, err := satpenService.FindDuplicates(filter, *minScore, *limit)
+	if err != nil {
+		log.Fatalf("Failed to find duplicates: %v", err)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	case "csv":
+		err = writeCSV(report.Pairs)
+	default:
+		err = writeText(report)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+func writeCSV(pairs []duplicate.Pair) error {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"score", "kabupaten", "id_a", "no_registrasi_a", "nama_a", "alamat_a", "id_b", "no_registrasi_b", "nama_b", "alamat_b"})
+	for _, p := range pairs {
+		w.Write([]string{
+			fmt.Sprintf("%.3f", p.Score), p.Kabupaten,
+			fmt.Sprint(p.A.ID), p.A.NoRegistrasi, p.A.Nama, p.A.Alamat,
+			fmt.Sprint(p.B.ID), p.B.NoRegistrasi, p.B.Nama, p.B.Alamat,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeText(report *service.DuplicateReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCORE\tKABUPATEN\tA\tB")
+	for _, p := range report.Pairs {
+		fmt.Fprintf(w, "%.3f\t%s\t%d %s\t%d %s\n", p.Score, p.Kabupaten, p.A.ID, p.A.Nama, p.B.ID, p.B.Nama)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(os.Stdout, "\n%d of %d pairs with score >= %.2f among %d satpen\n",
+		len(report.Pairs), report.Total, report.MinScore, report.Checked)
+	return err
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"satpen-api/internal/config"
+	"satpen-api/internal/database"
+	"satpen-api/internal/seed"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// seed fills an empty development database with synthetic data:
+//
+//	go run ./cmd/seed -satpen 15000 -seed 1 -until 2025-01-16
+//
+// The same -seed, -satpen, -ptk, -semesters and -until always give the same
+// rows. Apply the migrations first (make migrate).
+func main() {
+	defaults := seed.DefaultOptions(time.Now())
+
+	configPath := flag.String("config", "config.yaml", "config file")
+	seedValue := flag.Int64("seed", defaults.Seed, "random seed")
+	satpen := flag.Int("satpen", defaults.Satpen, "number of satpen")
+	ptk := flag.Int("ptk", defaults.PTKPerSatpen, "average PTK per satpen")
+	semesters := flag.Int("semesters", defaults.Semesters, "semesters of PDPTK")
+	until := flag.String("until", time.Now().Format("2006-01-02"), "last date of the data (YYYY-MM-DD)")
+	dirty := flag.Float64("dirty-rate", defaults.DirtyRate, "share of satpen with a data quality issue")
+	duplicates := flag.Float64("duplicate-rate", defaults.DuplicateRate, "share of satpen registered twice")
+	reset := flag.Bool("reset", false, "empty the seeded tables first")
+	batch := flag.Int("batch", defaults.BatchSize, "rows per INSERT")
+	flag.Parse()
+
+	untilDate, err := time.ParseInLocation("2006-01-02", *until, time.Local)
+	if err != nil {
+		log.Fatalf("Invalid -until %q: must be YYYY-MM-DD", *until)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.IsProduction() {
+		log.Fatalf("Refusing to seed a production database (app.env = %s)", cfg.App.Env)
+	}
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Warn)})
+
+	if *reset {
+		if err := seed.Reset(db); err != nil {
+			log.Fatalf("Failed to reset tables: %v", err)
+		}
+		fmt.Println("reset", len(seed.Tables), "tables")
+	}
+
+	opts := seed.Options{
+		Seed:          *seedValue,
+		Satpen:        *satpen,
+		PTKPerSatpen:  *ptk,
+		Semesters:     *semesters,
+		Until:         untilDate,
+		ValidYears:    cfg.Dashboard.RegistrationValidYears,
+		BatchSize:     *batch,
+		DirtyRate:     *dirty,
+		DuplicateRate: *duplicates,
+	}
+	start := time.Now()
+	counts, err := seed.Run(db, opts)
+	if errors.Is(err, seed.ErrNotEmpty) {
+		log.Fatalf("%v (run with -reset)", err)
+	}
+	if err != nil {
+		log.Fatalf("Failed to seed: %v", err)
+	}
+
+	for _, c := range counts {
+		fmt.Printf("%-26s %8d\n", c.Table, c.Rows)
+	}
+	fmt.Printf("seeded in %s (-seed %d -satpen %d -until %s)\n", time.Since(start).Round(time.Millisecond), *seedValue, *satpen, *until)
+}
@@ -1,130 +1,18 @@
 -- ========================================
 -- Database Performance Optimization
--- Recommended Indexes for satpen-api
+-- Index maintenance for satpen-api
 -- ========================================
--- 
--- Purpose: Improve query performance for frequently used filters and joins
--- Impact: 50-80% improvement in response time for filtered queries
--- 
--- IMPORTANT: Run these on production database during low-traffic period
--- Use ALGORITHM=INPLACE when possible to avoid table lock
+--
+-- Index dibuat oleh migration di internal/database/migrations
+-- (0002_query_indexes, 0003_satpen_fulltext); jalankan `make migrate`.
+-- File ini hanya berisi query untuk memeriksa dan merawatnya.
 --
 -- ========================================
 
 USE testing_lpmaarif1;
 
 -- ========================================
--- 1. SATPEN TABLE INDEXES
--- ========================================
-
--- Index untuk filter berdasarkan jenjang pendidikan
--- Digunakan di: ?jenjang=xxx
-CREATE INDEX IF NOT EXISTS idx_satpen_id_jenjang 
-ON satpen(id_jenjang);
-
--- Index untuk filter berdasarkan provinsi
--- Digunakan di: ?provinsi=xxx
-CREATE INDEX IF NOT EXISTS idx_satpen_id_prov 
-ON satpen(id_prov);
-
--- Index untuk filter berdasarkan kabupaten
--- Digunakan di: ?kabupaten=xxx
-CREATE INDEX IF NOT EXISTS idx_satpen_id_kab 
-ON satpen(id_kab);
-
--- Index untuk filter berdasarkan kategori/akreditasi
--- Digunakan di: ?akreditasi=xxx
-CREATE INDEX IF NOT EXISTS idx_satpen_id_kategori 
-ON satpen(id_kategori);
-
--- Index untuk filter berdasarkan status (SANGAT PENTING)
--- Default query selalu filter: status = 'setujui'
-CREATE INDEX IF NOT EXISTS idx_satpen_status 
-ON satpen(status);
-
--- Index untuk sorting berdasarkan tanggal
--- Digunakan di: ?sort=-created_at atau ?sort=created_at
-CREATE INDEX IF NOT EXISTS idx_satpen_created_at 
-ON satpen(created_at);
-
-CREATE INDEX IF NOT EXISTS idx_satpen_updated_at 
-ON satpen(updated_at);
-
--- Composite index untuk filter kombinasi yang sering digunakan
--- (status + provinsi) adalah kombinasi yang SANGAT sering digunakan
-CREATE INDEX IF NOT EXISTS idx_satpen_status_prov 
-ON satpen(status, id_prov);
-
--- Composite index untuk (status + jenjang)
-CREATE INDEX IF NOT EXISTS idx_satpen_status_jenjang 
-ON satpen(status, id_jenjang);
-
--- ========================================
--- 2. PDPTK TABLE INDEXES (CRITICAL!)
--- ========================================
-
--- PDPTK join adalah query PALING BERAT di aplikasi ini
--- Setiap request GetAllSatpen melakukan Preload PDPTK dengan:
--- "WHERE (id_satpen, tapel) IN (SELECT id_satpen, MAX(tapel) FROM pdptk GROUP BY id_satpen)"
-
--- Index untuk JOIN dengan satpen
-CREATE INDEX IF NOT EXISTS idx_pdptk_id_satpen 
-ON pdptk(id_satpen);
-
--- Composite index untuk subquery MAX(tapel) per id_satpen (SANGAT PENTING!)
-CREATE INDEX IF NOT EXISTS idx_pdptk_id_satpen_tapel 
-ON pdptk(id_satpen, tapel DESC);
-
--- Index untuk sorting berdasarkan tahun ajaran
-CREATE INDEX IF NOT EXISTS idx_pdptk_tapel 
-ON pdptk(tapel DESC);
-
--- ========================================
--- 3. REFERENCE TABLE INDEXES
--- ========================================
-
--- Provinsi - untuk JOIN dan filter nama
-CREATE INDEX IF NOT EXISTS idx_provinsi_nm_prov 
-ON provinsi(nm_prov);
-
--- Kabupaten - untuk JOIN dan filter nama
-CREATE INDEX IF NOT EXISTS idx_kabupaten_nama_kab 
-ON kabupaten(nama_kab);
-
-CREATE INDEX IF NOT EXISTS idx_kabupaten_id_prov 
-ON kabupaten(id_prov);
-
--- Jenjang Pendidikan - untuk filter nama
-CREATE INDEX IF NOT EXISTS idx_jenjang_nm_jenjang 
-ON jenjang_pendidikan(nm_jenjang);
-
--- Kategori Satpen - untuk filter akreditasi
-CREATE INDEX IF NOT EXISTS idx_kategori_nm_kategori 
-ON kategori_satpen(nm_kategori);
-
--- ========================================
--- 4. FULL-TEXT SEARCH INDEX (Optional)
--- ========================================
-
--- Untuk search yang lebih cepat dengan ?search=xxx
--- CATATAN: Ini akan membuat index lebih besar, gunakan jika search sering digunakan
--- DROP terlebih dahulu jika sudah ada dengan tipe B-TREE
--- ALTER TABLE satpen DROP INDEX idx_satpen_nm_satpen IF EXISTS;
--- ALTER TABLE satpen DROP INDEX idx_satpen_alamat IF EXISTS;
-
--- Full-text index untuk search nama satpen dan alamat
--- CREATE FULLTEXT INDEX idx_satpen_nm_satpen_ft 
--- ON satpen(nm_satpen);
-
--- CREATE FULLTEXT INDEX idx_satpen_alamat_ft 
--- ON satpen(alamat);
-
--- Alternative: Composite FULLTEXT index (lebih efisien untuk multi-column search)
--- CREATE FULLTEXT INDEX idx_satpen_search 
--- ON satpen(nm_satpen, alamat);
-
--- ========================================
--- 5. VERIFY INDEXES
+-- 1. VERIFY INDEXES
 -- ========================================
 
 -- Cek semua indexes yang sudah dibuat
@@ -136,7 +24,7 @@ SHOW INDEXES FROM jenjang_pendidikan;
 SHOW INDEXES FROM kategori_satpen;
 
 -- ========================================
--- 6. ANALYZE TABLES (Refresh Statistics)
+-- 2. ANALYZE TABLES (Refresh Statistics)
 -- ========================================
 
 -- Setelah membuat index, jalankan ANALYZE untuk update statistics
@@ -148,7 +36,7 @@ ANALYZE TABLE jenjang_pendidikan;
 ANALYZE TABLE kategori_satpen;
 
 -- ========================================
--- 7. MONITORING QUERY PERFORMANCE
+-- 3. MONITORING QUERY PERFORMANCE
 -- ========================================
 
 -- Test query performance dengan EXPLAIN
@@ -172,7 +60,7 @@ LIMIT 20;
 -- Pastikan tidak ada 'Using filesort' atau 'Using temporary'
 
 -- ========================================
--- 8. PERFORMANCE TIPS
+-- 4. PERFORMANCE TIPS
 -- ========================================
 
 /*
@@ -213,9 +101,7 @@ DISK SPACE:
 
 NOTES:
 
-1. Index dibuat dengan IF NOT EXISTS untuk mencegah error jika sudah ada
-2. Index PDPTK adalah PALING PENTING karena query-nya paling berat
-3. Full-text search index optional, aktifkan jika search sering digunakan
-4. Test di staging dulu sebelum production
-5. Backup database sebelum menjalankan script ini
+1. Index PDPTK adalah PALING PENTING karena query-nya paling berat
+2. Test migration di staging dulu sebelum production
+3. Backup database sebelum menjalankan migration
 */
@@ -0,0 +1,116 @@
+// Package audit carries who made a request through its context and builds
+// the models.AuditLog entries that repositories write next to each change.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"satpen-api/internal/models"
+	"sort"
+)
+
+// SystemActor is the actor of changes made without a request whose context
+// names no actor. The scheduled Dapodik sync sets its own, dapodik-sync.
+const SystemActor = "system"
+
+// Info identifies the origin of a change
+type Info struct {
+	Actor     string
+	IP        string
+	RequestID string
+}
+
+type contextKey struct{}
+
+// WithInfo returns a copy of ctx carrying info
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the Info of ctx. The actor is SystemActor when ctx
+// carries none.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+	if info.Actor == "" {
+		info.Actor = SystemActor
+	}
+	return info
+}
+
+// NewEntry starts an audit entry for action on the entity, attributed to the
+// Info of ctx. Repositories complete it with Fill inside their transaction.
+func NewEntry(ctx context.Context, action, entityType string, entityID uint) *models.AuditLog {
+	info := FromContext(ctx)
+	return &models.AuditLog{
+		Actor:      info.Actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		IP:         info.IP,
+		RequestID:  info.RequestID,
+	}
+}
+
+// Fill stores the JSON of before and after (either may be nil) on entry
+// together with their diff
+func Fill(entry *models.AuditLog, before, after interface{}) error {
+	var err error
+	if entry.Before, err = marshal(before); err != nil {
+		return err
+	}
+	if entry.After, err = marshal(after); err != nil {
+		return err
+	}
+	entry.Changes, err = Diff(entry.Before, entry.After)
+	return err
+}
+
+// Change is one field of a diff
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Diff compares the top-level fields of two JSON objects and returns the
+// changed ones as {"field": {"from": ..., "to": ...}}. A missing or null
+// object counts as having no fields.
+func Diff(before, after json.RawMessage) (json.RawMessage, error) {
+	var b, a map[string]interface{}
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &b); err != nil {
+			return nil, err
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &a); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(b)+len(a))
+	for key := range b {
+		keys = append(keys, key)
+	}
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make(map[string]Change)
+	for _, key := range keys {
+		if !reflect.DeepEqual(b[key], a[key]) {
+			changes[key] = Change{From: b[key], To: a[key]}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+func marshal(v interface{}) (json.RawMessage, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"satpen-api/internal/models"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{"both missing", "", "", `{}`},
+		{"created", "", `{"nama":"MI NU 01","npsn":"20507001"}`, `{"nama":{"from":null,"to":"MI NU 01"},"npsn":{"from":null,"to":"20507001"}}`},
+		{"deleted", `{"nama":"MI NU 01"}`, "", `{"nama":{"from":"MI NU 01","to":null}}`},
+		{"null object", `null`, `{"nama":"MI NU 01"}`, `{"nama":{"from":null,"to":"MI NU 01"}}`},
+		{"unchanged", `{"nama":"MI NU 01","jml_pd":180}`, `{"jml_pd":180.0,"nama":"MI NU 01"}`, `{}`},
+		{"changed and added", `{"nama":"MI NU 01","jml_pd":180}`, `{"nama":"MI NU 01","jml_pd":200,"email":"mi@example.com"}`, `{"email":{"from":null,"to":"mi@example.com"},"jml_pd":{"from":180,"to":200}}`},
+		{"field set to null", `{"email":"mi@example.com"}`, `{"email":null}`, `{"email":{"from":"mi@example.com","to":null}}`},
+		// Nested objects are compared whole and reported as one change
+		{"nested change", `{"pdptk":{"jml_pd":180,"jml_guru":12},"nama":"MI"}`, `{"pdptk":{"jml_pd":180,"jml_guru":13},"nama":"MI"}`, `{"pdptk":{"from":{"jml_guru":12,"jml_pd":180},"to":{"jml_guru":13,"jml_pd":180}}}`},
+		{"nested unchanged", `{"pdptk":{"jml_pd":180},"tags":[1,2]}`, `{"tags":[1,2],"pdptk":{"jml_pd":180}}`, `{}`},
+		{"nested array", `{"tags":[1,2]}`, `{"tags":[2,1]}`, `{"tags":{"from":[1,2],"to":[2,1]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(json.RawMessage(tt.before), json.RawMessage(tt.after))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Diff = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Diff(json.RawMessage(`{"nama":`), nil); err == nil {
+		t.Error("invalid before: want an error")
+	}
+	if _, err := Diff(nil, json.RawMessage(`[1]`)); err == nil {
+		t.Error("array after: want an error")
+	}
+}
+
+type testEntity struct {
+	Nama   string `json:"nama"`
+	JmlPD  int    `json:"jml_pd"`
+	Detail *struct {
+		Kelurahan string `json:"kelurahan"`
+	} `json:"detail,omitempty"`
+}
+
+func TestFill(t *testing.T) {
+	var nilEntity *testEntity
+	before := &testEntity{Nama: "MI NU 01", JmlPD: 180}
+	after := &testEntity{Nama: "MI NU 01", JmlPD: 200}
+	after.Detail = &struct {
+		Kelurahan string `json:"kelurahan"`
+	}{"Kauman"}
+
+	tests := []struct {
+		name                  string
+		before, after         interface{}
+		wantBefore, wantAfter string
+		wantChanges           string
+	}{
+		{"update", before, after, `{"nama":"MI NU 01","jml_pd":180}`, `{"nama":"MI NU 01","jml_pd":200,"detail":{"kelurahan":"Kauman"}}`, `{"detail":{"from":null,"to":{"kelurahan":"Kauman"}},"jml_pd":{"from":180,"to":200}}`},
+		{"create", nil, before, "", `{"nama":"MI NU 01","jml_pd":180}`, `{"jml_pd":{"from":null,"to":180},"nama":{"from":null,"to":"MI NU 01"}}`},
+		{"delete with a typed nil after", before, nilEntity, `{"nama":"MI NU 01","jml_pd":180}`, "", `{"jml_pd":{"from":180,"to":null},"nama":{"from":"MI NU 01","to":null}}`},
+		{"nothing", nilEntity, nil, "", "", `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &models.AuditLog{}
+			if err := Fill(entry, tt.before, tt.after); err != nil {
+				t.Fatal(err)
+			}
+			if string(entry.Before) != tt.wantBefore || string(entry.After) != tt.wantAfter {
+				t.Errorf("before %s, after %s; want %s, %s", entry.Before, entry.After, tt.wantBefore, tt.wantAfter)
+			}
+			if string(entry.Changes) != tt.wantChanges {
+				t.Errorf("changes = %s, want %s", entry.Changes, tt.wantChanges)
+			}
+		})
+	}
+
+	if err := Fill(&models.AuditLog{}, func() {}, nil); err == nil {
+		t.Error("unmarshalable value: want an error")
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	entry := NewEntry(context.Background(), "update", "satpen", 7)
+	if entry.Actor != SystemActor || entry.EntityType != "satpen" || entry.EntityID != 7 {
+		t.Errorf("without info: %+v", entry)
+	}
+
+	ctx := WithInfo(context.Background(), Info{Actor: "admin", IP: "10.0.0.1", RequestID: "req-1"})
+	entry = NewEntry(ctx, "update", "satpen", 7)
+	if entry.Actor != "admin" || entry.IP != "10.0.0.1" || entry.RequestID != "req-1" {
+		t.Errorf("with info: %+v", entry)
+	}
+}
@@ -0,0 +1,243 @@
+// Package cluster implements an in-memory spatial index of satpen locations
+// that groups them into Web Mercator grid clusters for map rendering.
+package cluster
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// maxLevel is the grid resolution of stored points: 2^24 cells per side,
+	// a few metres at the equator
+	maxLevel = 24
+	// cellLevels subdivides each 256 px map tile into 4x4 cells of 64 px, the
+	// size of one cluster marker
+	cellLevels = 2
+	// maxCells bounds the cells scanned per query; a bbox much larger than the
+	// zoom suggests is clustered on a coarser grid instead
+	maxCells = 4096
+	// mercatorMaxLat is the latitude where Web Mercator tiles end
+	mercatorMaxLat = 85.05112878
+)
+
+// ErrBBox is returned for a bounding box that is out of range, inverted or
+// not finite
+var ErrBBox = errors.New("bbox must be minLng,minLat,maxLng,maxLat within -180..180 and -90..90")
+
+// Point is one indexed satpen
+type Point struct {
+	ID        uint    `json:"id"`
+	NPSN      string  `json:"npsn"`
+	Name      string  `json:"nama"`
+	Jenjang   string  `json:"jenjang,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// BBox is a bounding box in degrees
+type BBox struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+}
+
+// Validate checks the box lies within valid coordinates and is not inverted.
+// Boxes crossing the antimeridian are not supported.
+func (b BBox) Validate() error {
+	// NaN fails no comparison below, so non-finite values are rejected first
+	for _, v := range []float64{b.MinLng, b.MinLat, b.MaxLng, b.MaxLat} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ErrBBox
+		}
+	}
+	if b.MinLng < -180 || b.MaxLng > 180 || b.MinLat < -90 || b.MaxLat > 90 ||
+		b.MinLng > b.MaxLng || b.MinLat > b.MaxLat {
+		return ErrBBox
+	}
+	return nil
+}
+
+func (b BBox) contains(p *Point) bool {
+	return p.Longitude >= b.MinLng && p.Longitude <= b.MaxLng &&
+		p.Latitude >= b.MinLat && p.Latitude <= b.MaxLat
+}
+
+// Cluster is a group of points in one grid cell. Single points are returned
+// as Point instead.
+type Cluster struct {
+	Count     int        `json:"count"`
+	Latitude  float64    `json:"latitude"`  // centroid
+	Longitude float64    `json:"longitude"` // centroid
+	BBox      [4]float64 `json:"bbox"`      // minLng, minLat, maxLng, maxLat of the members
+}
+
+// Result is the content of a bbox at a zoom level. Truncated is set when
+// individual points beyond maxPoints were left out; Omitted counts them.
+type Result struct {
+	Clusters  []Cluster `json:"clusters"`
+	Points    []Point   `json:"points"`
+	Truncated bool      `json:"truncated"`
+	Omitted   int       `json:"omitted"`
+}
+
+// Index holds points sorted by their Morton (Z-order) code so that every
+// grid cell at any level is a contiguous range. It is immutable once built.
+type Index struct {
+	points  []Point
+	codes   []uint64
+	builtAt time.Time
+}
+
+// Build indexes points, dropping any outside the Web Mercator range
+func Build(points []Point) *Index {
+	type entry struct {
+		code  uint64
+		point Point
+	}
+	entries := make([]entry, 0, len(points))
+	for _, p := range points {
+		if p.Latitude < -mercatorMaxLat || p.Latitude > mercatorMaxLat || p.Longitude < -180 || p.Longitude > 180 {
+			continue
+		}
+		x, y := cellXY(p.Latitude, p.Longitude, maxLevel)
+		entries = append(entries, entry{code: interleave(x, y), point: p})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].code < entries[j].code })
+
+	idx := &Index{
+		points:  make([]Point, len(entries)),
+		codes:   make([]uint64, len(entries)),
+		builtAt: time.Now(),
+	}
+	for i, e := range entries {
+		idx.points[i] = e.point
+		idx.codes[i] = e.code
+	}
+	return idx
+}
+
+// Len returns the number of indexed points
+func (idx *Index) Len() int {
+	return len(idx.points)
+}
+
+// BuiltAt returns when the index was built
+func (idx *Index) BuiltAt() time.Time {
+	return idx.builtAt
+}
+
+// Query returns the points in bbox at zoom, grouped into grid clusters of
+// roughly 64 px. From pointZoom on every point is returned individually, up
+// to maxPoints, and the rest are counted in Result.Omitted; clusters of a
+// single point are returned as points too. keep, when not nil, filters
+// points (e.g. by jenjang).
+func (idx *Index) Query(bbox BBox, zoom, pointZoom, maxPoints int, keep func(*Point) bool) Result {
+	result := Result{Clusters: []Cluster{}, Points: []Point{}}
+	bbox.MinLat = math.Max(bbox.MinLat, -mercatorMaxLat)
+	bbox.MaxLat = math.Min(bbox.MaxLat, mercatorMaxLat)
+	if bbox.MinLat > bbox.MaxLat || len(idx.points) == 0 {
+		return result
+	}
+
+	level := zoom + cellLevels
+	if level > maxLevel {
+		level = maxLevel
+	}
+	if level < 0 {
+		level = 0
+	}
+	// Cells are named by their corner in tile coordinates: y grows southward
+	x0, y0 := cellXY(bbox.MaxLat, bbox.MinLng, level)
+	x1, y1 := cellXY(bbox.MinLat, bbox.MaxLng, level)
+	for level > 0 && uint64(x1-x0+1)*uint64(y1-y0+1) > maxCells {
+		level--
+		x0, y0, x1, y1 = x0>>1, y0>>1, x1>>1, y1>>1
+	}
+
+	individual := zoom >= pointZoom
+	shift := uint(2 * (maxLevel - level))
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			cell := interleave(x, y) << shift
+			from := sort.Search(len(idx.codes), func(i int) bool { return idx.codes[i] >= cell })
+			to := sort.Search(len(idx.codes), func(i int) bool { return idx.codes[i] >= cell+(1<<shift) })
+
+			var c Cluster
+			var first *Point
+			var sumLat, sumLng float64
+			for i := from; i < to; i++ {
+				p := &idx.points[i]
+				if !bbox.contains(p) || (keep != nil && !keep(p)) {
+					continue
+				}
+				if individual {
+					if len(result.Points) < maxPoints {
+						result.Points = append(result.Points, *p)
+					} else {
+						result.Omitted++
+					}
+					continue
+				}
+				if c.Count == 0 {
+					first = p
+					c.BBox = [4]float64{p.Longitude, p.Latitude, p.Longitude, p.Latitude}
+				}
+				c.Count++
+				sumLat += p.Latitude
+				sumLng += p.Longitude
+				c.BBox[0] = math.Min(c.BBox[0], p.Longitude)
+				c.BBox[1] = math.Min(c.BBox[1], p.Latitude)
+				c.BBox[2] = math.Max(c.BBox[2], p.Longitude)
+				c.BBox[3] = math.Max(c.BBox[3], p.Latitude)
+			}
+
+			switch {
+			case c.Count == 1:
+				result.Points = append(result.Points, *first)
+			case c.Count > 1:
+				c.Latitude = sumLat / float64(c.Count)
+				c.Longitude = sumLng / float64(c.Count)
+				result.Clusters = append(result.Clusters, c)
+			}
+		}
+	}
+	result.Truncated = result.Omitted > 0
+	return result
+}
+
+// cellXY returns the Web Mercator tile cell of a location at level, where
+// level n has 2^n cells per side
+func cellXY(lat, lng float64, level int) (uint32, uint32) {
+	lat = math.Max(-mercatorMaxLat, math.Min(mercatorMaxLat, lat))
+	n := float64(uint64(1) << uint(level))
+	sin := math.Sin(lat * math.Pi / 180)
+	fx := (lng + 180) / 360
+	fy := 0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)
+	return clampCell(fx*n, n), clampCell(fy*n, n)
+}
+
+func clampCell(v, n float64) uint32 {
+	if v < 0 {
+		return 0
+	}
+	if v >= n {
+		return uint32(n - 1)
+	}
+	return uint32(v)
+}
+
+// interleave returns the Morton code of x and y: bits x0 y0 x1 y1 ...
+func interleave(x, y uint32) uint64 {
+	return spread(x) | spread(y)<<1
+}
+
+func spread(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
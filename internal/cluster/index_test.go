@@ -0,0 +1,234 @@
+package cluster
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testPoints are three satpen in Malang, one in Surabaya and one in Kudus
+var testPoints = []Point{
+	{ID: 1, Name: "MI NU 01 Malang", Jenjang: "MI", Latitude: -7.98, Longitude: 112.63},
+	{ID: 2, Name: "MTs NU Malang", Jenjang: "MTs", Latitude: -7.97, Longitude: 112.64},
+	{ID: 3, Name: "MI NU 02 Malang", Jenjang: "MI", Latitude: -7.96, Longitude: 112.62},
+	{ID: 4, Name: "MA NU Surabaya", Jenjang: "MA", Latitude: -7.25, Longitude: 112.75},
+	{ID: 5, Name: "MTs Ma'arif Kudus", Jenjang: "MTs", Latitude: -6.80, Longitude: 110.84},
+}
+
+var java = BBox{MinLng: 105, MinLat: -9, MaxLng: 115, MaxLat: -5}
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		x, y uint32
+		want uint64
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{0, 1, 2},
+		{1, 1, 3},
+		{2, 0, 4},
+		{3, 3, 15},
+		{1<<24 - 1, 0, 0x555555555555},
+		{0, 1<<24 - 1, 0xAAAAAAAAAAAA},
+	}
+	for _, tt := range tests {
+		if got := interleave(tt.x, tt.y); got != tt.want {
+			t.Errorf("interleave(%d, %d) = %#x, want %#x", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestCellXY(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		level    int
+		x, y     uint32
+	}{
+		{0, 0, 1, 1, 1}, // the corner of four cells belongs to the south-east one
+		{1, -1, 1, 0, 0},
+		{-1, 1, 1, 1, 1},
+		{mercatorMaxLat, -180, 4, 0, 0},
+		{-90, 180, 4, 15, 15}, // clamped into the grid
+	}
+	for _, tt := range tests {
+		if x, y := cellXY(tt.lat, tt.lng, tt.level); x != tt.x || y != tt.y {
+			t.Errorf("cellXY(%v, %v, %d) = %d, %d; want %d, %d", tt.lat, tt.lng, tt.level, x, y, tt.x, tt.y)
+		}
+	}
+}
+
+// Every grid cell at any level is one contiguous range of the sorted codes
+func TestBuildMortonCells(t *testing.T) {
+	idx := Build(append(testPoints,
+		Point{ID: 6, Latitude: 89, Longitude: 10},      // beyond Web Mercator
+		Point{ID: 7, Latitude: 0, Longitude: 181},      // out of range
+		Point{ID: 8, Latitude: -6.2, Longitude: 106.8}, // Jakarta
+	))
+	if idx.Len() != 6 {
+		t.Fatalf("Len = %d, want 6 of 8 points", idx.Len())
+	}
+	if !sort.SliceIsSorted(idx.codes, func(i, j int) bool { return idx.codes[i] < idx.codes[j] }) {
+		t.Fatal("codes are not sorted")
+	}
+
+	for level := 0; level <= maxLevel; level += 4 {
+		shift := uint(2 * (maxLevel - level))
+		for i, p := range idx.points {
+			x, y := cellXY(p.Latitude, p.Longitude, level)
+			if got := idx.codes[i] >> shift; got != interleave(x, y) {
+				t.Fatalf("level %d: point %d has cell %#x, want %#x", level, p.ID, got, interleave(x, y))
+			}
+		}
+		// Points of one cell are adjacent
+		seen := make(map[uint64]bool)
+		for i := range idx.codes {
+			cell := idx.codes[i] >> shift
+			if seen[cell] && idx.codes[i-1]>>shift != cell {
+				t.Errorf("level %d: cell %#x is split", level, cell)
+			}
+			seen[cell] = true
+		}
+	}
+}
+
+func TestQueryClustersPerZoom(t *testing.T) {
+	idx := Build(testPoints)
+	malang := BBox{MinLng: 112.5, MinLat: -8.1, MaxLng: 112.8, MaxLat: -7.9}
+
+	tests := []struct {
+		bbox         BBox
+		zoom         int
+		wantClusters []int  // counts, in query order
+		wantPoints   []uint // ids
+	}{
+		{java, 0, []int{5}, nil},
+		{java, 6, []int{4}, []uint{5}},
+		{java, 8, []int{3}, []uint{4, 5}},
+		{malang, 9, []int{3}, nil},
+		{malang, 10, []int{2}, []uint{1}},
+		{malang, 12, nil, []uint{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		result := idx.Query(tt.bbox, tt.zoom, 14, 100, nil)
+		if counts, ids := contents(result); !reflect.DeepEqual(counts, tt.wantClusters) || !reflect.DeepEqual(ids, tt.wantPoints) {
+			t.Errorf("zoom %d: clusters %v, points %v; want %v, %v", tt.zoom, counts, ids, tt.wantClusters, tt.wantPoints)
+		}
+		if result.Truncated || result.Omitted != 0 {
+			t.Errorf("zoom %d: truncated %v, omitted %d", tt.zoom, result.Truncated, result.Omitted)
+		}
+	}
+}
+
+func TestQueryCentroid(t *testing.T) {
+	result := Build(testPoints).Query(java, 8, 14, 100, nil)
+	if len(result.Clusters) != 1 {
+		t.Fatalf("clusters = %+v, want the Malang one", result.Clusters)
+	}
+	c := result.Clusters[0]
+	if math.Abs(c.Latitude-(-7.97)) > 1e-9 || math.Abs(c.Longitude-112.63) > 1e-9 {
+		t.Errorf("centroid = %v, %v; want -7.97, 112.63", c.Latitude, c.Longitude)
+	}
+	if want := [4]float64{112.62, -7.98, 112.64, -7.96}; c.BBox != want {
+		t.Errorf("bbox = %v, want %v", c.BBox, want)
+	}
+}
+
+func TestQueryBBoxAcrossCellEdges(t *testing.T) {
+	// Two points either side of the 0 meridian and the equator, the edge of
+	// cells at every level, and one further east in the same cells
+	idx := Build([]Point{
+		{ID: 1, Latitude: 0.001, Longitude: -0.001},
+		{ID: 2, Latitude: -0.001, Longitude: 0.001},
+		{ID: 3, Latitude: -0.001, Longitude: 0.5},
+	})
+
+	tests := []struct {
+		name         string
+		bbox         BBox
+		zoom         int
+		wantClusters []int
+		wantPoints   []uint
+	}{
+		// Neighbours across the edge are never merged
+		{"both sides", BBox{-1, -1, 1, 1}, 0, []int{2}, []uint{1}},
+		// The bbox edge on the cell edge includes points on it only
+		{"east half", BBox{0, -1, 1, 1}, 0, []int{2}, nil},
+		{"west half", BBox{-1, -1, 0, 1}, 0, nil, []uint{1}},
+		// Cutting through a cell keeps only its members inside the bbox
+		{"part of a cell", BBox{0, -1, 0.1, 1}, 0, nil, []uint{2}},
+		{"bbox corner on the points", BBox{-0.001, -0.001, 0.001, 0.001}, 0, nil, []uint{1, 2}},
+		{"empty", BBox{1, 1, 2, 2}, 0, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, ids := contents(idx.Query(tt.bbox, tt.zoom, 14, 100, nil))
+			if !reflect.DeepEqual(counts, tt.wantClusters) || !reflect.DeepEqual(ids, tt.wantPoints) {
+				t.Errorf("clusters %v, points %v; want %v, %v", counts, ids, tt.wantClusters, tt.wantPoints)
+			}
+		})
+	}
+}
+
+func TestQueryPointsTruncated(t *testing.T) {
+	idx := Build(testPoints)
+
+	result := idx.Query(java, 14, 14, 3, nil)
+	if len(result.Points) != 3 || len(result.Clusters) != 0 || !result.Truncated || result.Omitted != 2 {
+		t.Errorf("points %d, clusters %d, truncated %v, omitted %d; want 3, 0, true, 2",
+			len(result.Points), len(result.Clusters), result.Truncated, result.Omitted)
+	}
+
+	// Filtered points do not count as omitted
+	mts := func(p *Point) bool { return p.Jenjang == "MTs" }
+	result = idx.Query(java, 14, 14, 2, mts)
+	if len(result.Points) != 2 || result.Truncated || result.Omitted != 0 {
+		t.Errorf("jenjang MTs: points %+v, truncated %v, omitted %d", result.Points, result.Truncated, result.Omitted)
+	}
+}
+
+func TestQueryCoarsensLargeBBox(t *testing.T) {
+	// The whole world at zoom 13 would be 2^30 cells: clustered coarser
+	result := Build(testPoints).Query(BBox{-180, -90, 180, 90}, 13, 14, 100, nil)
+	total := len(result.Points)
+	for _, c := range result.Clusters {
+		total += c.Count
+	}
+	if total != len(testPoints) {
+		t.Errorf("clusters %+v and %d points hold %d satpen, want %d", result.Clusters, len(result.Points), total, len(testPoints))
+	}
+}
+
+func TestBBoxValidate(t *testing.T) {
+	for _, bbox := range []BBox{{-180, -90, 180, 90}, {110, -8, 110, -8}} {
+		if err := bbox.Validate(); err != nil {
+			t.Errorf("%v: %v", bbox, err)
+		}
+	}
+	nan, inf := math.NaN(), math.Inf(1)
+	invalid := []BBox{
+		{-181, 0, 0, 1}, {0, -91, 1, 0}, {10, 0, 5, 1}, {0, 5, 1, 0}, {170, 0, -170, 1},
+		// What strconv.ParseFloat returns for "NaN", "Inf" and "-Inf"
+		{nan, 0, 1, 1}, {0, nan, 1, 1}, {0, 0, nan, 1}, {0, 0, 1, nan}, {nan, nan, nan, nan},
+		{-inf, 0, 1, 1}, {0, 0, inf, 1}, {0, -inf, 1, inf},
+	}
+	for _, bbox := range invalid {
+		if err := bbox.Validate(); err != ErrBBox {
+			t.Errorf("%v: err = %v, want ErrBBox", bbox, err)
+		}
+	}
+}
+
+// contents returns the cluster counts in query order and the sorted point ids
+func contents(result Result) ([]int, []uint) {
+	var counts []int
+	for _, c := range result.Clusters {
+		counts = append(counts, c.Count)
+	}
+	var ids []uint
+	for _, p := range result.Points {
+		ids = append(ids, p.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return counts, ids
+}
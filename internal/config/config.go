@@ -18,6 +18,16 @@ type Config struct {
 	Logging    LoggingConfig    `yaml:"logging"`
 	Security   SecurityConfig   `yaml:"security"`
 	Monitoring MonitoringConfig `yaml:"monitoring"`
+	Admin      AdminConfig      `yaml:"admin"`
+	Dapodik    DapodikConfig    `yaml:"dapodik"`
+	Search     SearchConfig     `yaml:"search"`
+	Suggest    SuggestConfig    `yaml:"suggest"`
+	Nearby     NearbyConfig     `yaml:"nearby"`
+	Cluster    ClusterConfig    `yaml:"cluster"`
+	Dashboard  DashboardConfig  `yaml:"dashboard"`
+	Statistics StatisticsConfig `yaml:"statistics"`
+	Indicators IndicatorsConfig `yaml:"indicators"`
+	Duplicates DuplicatesConfig `yaml:"duplicates"`
 }
 
 type AppConfig struct {
@@ -39,6 +49,7 @@ type DatabaseConfig struct {
 	MaxIdleConns    int    `yaml:"max_idle_conns"`
 	MaxOpenConns    int    `yaml:"max_open_conns"`
 	ConnMaxLifetime int    `yaml:"conn_max_lifetime"`
+	AutoMigrate     bool   `yaml:"auto_migrate"` // apply pending migrations at startup
 }
 
 type RedisConfig struct {
@@ -103,6 +114,79 @@ type MonitoringConfig struct {
 	HealthCheckPath string `yaml:"health_check_path"`
 }
 
+type AdminConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	APIKeys []AdminAPIKey `yaml:"api_keys"`
+}
+
+type AdminAPIKey struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type DapodikConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	BaseURL     string `yaml:"base_url"`
+	Token       string `yaml:"token"`
+	Tapel       string `yaml:"tapel"`
+	Timeout     int    `yaml:"timeout"`      // seconds
+	Interval    int    `yaml:"interval"`     // seconds between scheduled runs
+	BatchSize   int    `yaml:"batch_size"`   // schools pulled per run
+	StaleAfter  int    `yaml:"stale_after"`  // seconds before a school is synced again
+	MaxRetries  int    `yaml:"max_retries"`  // retries per school within a run
+	BackoffBase int    `yaml:"backoff_base"` // seconds
+	BackoffMax  int    `yaml:"backoff_max"`  // seconds
+}
+
+type SearchConfig struct {
+	DefaultMode  string `yaml:"default_mode"`   // like, natural, boolean
+	MinTokenSize int    `yaml:"min_token_size"` // innodb_ft_min_token_size
+}
+
+type SuggestConfig struct {
+	RefreshInterval int `yaml:"refresh_interval"` // seconds
+	DefaultLimit    int `yaml:"default_limit"`
+	MaxLimit        int `yaml:"max_limit"`
+}
+
+type NearbyConfig struct {
+	DefaultRadiusKm float64 `yaml:"default_radius_km"`
+	MaxRadiusKm     float64 `yaml:"max_radius_km"`
+}
+
+type ClusterConfig struct {
+	RefreshInterval int `yaml:"refresh_interval"` // seconds
+	PointZoom       int `yaml:"point_zoom"`       // from this zoom on satpen are not clustered
+	MaxPoints       int `yaml:"max_points"`
+}
+
+type DashboardConfig struct {
+	RegistrationValidYears int `yaml:"registration_valid_years"` // 0: registrations don't expire
+	ExpiringWithinDays     int `yaml:"expiring_within_days"`
+}
+
+type StatisticsConfig struct {
+	TopN    int `yaml:"top_n"`     // entries in the top_* lists
+	MaxTopN int `yaml:"max_top_n"` // cap on ?top=
+}
+
+// IndicatorsConfig holds the thresholds a satpen is flagged outside of, per
+// indicator (rasio_siswa_guru, persen_guru_perempuan, tendik_per_100_siswa)
+type IndicatorsConfig struct {
+	Thresholds map[string]IndicatorThreshold `yaml:"thresholds"`
+}
+
+// IndicatorThreshold bounds one indicator; a nil bound is not checked
+type IndicatorThreshold struct {
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+}
+
+type DuplicatesConfig struct {
+	MinScore float64 `yaml:"min_score"` // 0-1, pairs scoring lower are not reported
+	MaxPairs int     `yaml:"max_pairs"` // cap on pairs per response
+}
+
 var GlobalConfig *Config
 
 // LoadConfig loads configuration from config.yaml
@@ -155,6 +239,15 @@ func overrideWithEnv(config *Config) {
 	if redisPass := os.Getenv("REDIS_PASSWORD"); redisPass != "" {
 		config.Redis.Password = redisPass
 	}
+	if adminKey := os.Getenv("ADMIN_API_KEY"); adminKey != "" {
+		config.Admin.APIKeys = append(config.Admin.APIKeys, AdminAPIKey{Name: "env", Key: adminKey})
+	}
+	if dapodikURL := os.Getenv("DAPODIK_BASE_URL"); dapodikURL != "" {
+		config.Dapodik.BaseURL = dapodikURL
+	}
+	if dapodikToken := os.Getenv("DAPODIK_TOKEN"); dapodikToken != "" {
+		config.Dapodik.Token = dapodikToken
+	}
 }
 
 // GetDSN returns database connection string
@@ -0,0 +1,22 @@
+package dapodik
+
+import "time"
+
+// Backoff returns the exponential delay before retry number attempt (1-based),
+// doubling from base and capped at maxDelay
+func Backoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
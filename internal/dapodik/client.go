@@ -0,0 +1,132 @@
+package dapodik
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"satpen-api/internal/config"
+)
+
+var (
+	// ErrNotFound is returned when the upstream has no data for the requested NPSN/tapel
+	ErrNotFound = errors.New("dapodik: data not found")
+	// ErrUnauthorized is returned when the upstream rejects the configured token
+	ErrUnauthorized = errors.New("dapodik: unauthorized")
+)
+
+// PDPTK is the per-school learner and staff summary returned by the upstream
+type PDPTK struct {
+	NPSN     string `json:"npsn"`
+	Tapel    string `json:"tapel"`
+	PDLK     int    `json:"pd_lk"`
+	PDPR     int    `json:"pd_pr"`
+	GuruLK   int    `json:"guru_lk"`
+	GuruPR   int    `json:"guru_pr"`
+	TendikLK int    `json:"tendik_lk"`
+	TendikPR int    `json:"tendik_pr"`
+}
+
+// Client pulls data from a Dapodik-compatible upstream
+type Client interface {
+	FetchPDPTK(ctx context.Context, npsn, tapel string) (*PDPTK, error)
+}
+
+// UpstreamError is returned for unexpected upstream responses
+type UpstreamError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("dapodik: upstream returned %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed when repeated later
+func (e *UpstreamError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrUnauthorized) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr.Retryable()
+	}
+	// Network errors and timeouts
+	return true
+}
+
+type httpClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewHTTPClient creates a Client talking to cfg.Dapodik.BaseURL
+func NewHTTPClient(cfg *config.Config) Client {
+	timeout := time.Duration(cfg.Dapodik.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+
+	return &httpClient{
+		baseURL:    strings.TrimRight(cfg.Dapodik.BaseURL, "/"),
+		token:      cfg.Dapodik.Token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// FetchPDPTK handles GET {base_url}/satpen/{npsn}/pdptk?tapel={tapel}
+func (c *httpClient) FetchPDPTK(ctx context.Context, npsn, tapel string) (*PDPTK, error) {
+	endpoint := fmt.Sprintf("%s/satpen/%s/pdptk", c.baseURL, url.PathEscape(npsn))
+	if tapel != "" {
+		endpoint += "?tapel=" + url.QueryEscape(tapel)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, ErrUnauthorized
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &UpstreamError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	var data PDPTK
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("dapodik: failed to decode response: %w", err)
+	}
+	if data.Tapel == "" {
+		data.Tapel = tapel
+	}
+	return &data, nil
+}
@@ -0,0 +1,129 @@
+// Package dapodiktest provides a fake Dapodik upstream for local development
+// and tests.
+package dapodiktest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"satpen-api/internal/dapodik"
+)
+
+// Server is an in-memory Dapodik upstream. Records are keyed by NPSN and tapel.
+type Server struct {
+	Token string
+
+	mu       sync.Mutex
+	records  map[string]dapodik.PDPTK
+	failures map[string][]int
+	requests map[string]int
+}
+
+// New creates an empty fake upstream. An empty token disables auth checks.
+func New(token string) *Server {
+	return &Server{
+		Token:    token,
+		records:  make(map[string]dapodik.PDPTK),
+		failures: make(map[string][]int),
+		requests: make(map[string]int),
+	}
+}
+
+// Start serves the fake upstream on a random local port
+func (s *Server) Start() *httptest.Server {
+	return httptest.NewServer(s.Handler())
+}
+
+// Put stores the record returned for data.NPSN/data.Tapel
+func (s *Server) Put(data dapodik.PDPTK) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key(data.NPSN, data.Tapel)] = data
+}
+
+// FailNext makes the next requests for npsn answer with the given status codes, in order
+func (s *Server) FailNext(npsn string, statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[npsn] = append(s.failures[npsn], statusCodes...)
+}
+
+// Requests returns how many requests were received for npsn
+func (s *Server) Requests(npsn string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[npsn]
+}
+
+// Handler serves GET /satpen/{npsn}/pdptk?tapel={tapel}
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/satpen/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+			return
+		}
+
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/satpen/"), "/"), "/")
+		if len(parts) != 2 || parts[1] != "pdptk" || parts[0] == "" {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+		npsn := parts[0]
+		tapel := r.URL.Query().Get("tapel")
+
+		s.mu.Lock()
+		s.requests[npsn]++
+		if codes := s.failures[npsn]; len(codes) > 0 {
+			s.failures[npsn] = codes[1:]
+			s.mu.Unlock()
+			writeJSON(w, codes[0], map[string]string{"error": http.StatusText(codes[0])})
+			return
+		}
+		data, ok := s.lookup(npsn, tapel)
+		s.mu.Unlock()
+
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, data)
+	})
+	return mux
+}
+
+// lookup finds the record for npsn/tapel, or the latest tapel when tapel is empty.
+// Callers must hold s.mu.
+func (s *Server) lookup(npsn, tapel string) (dapodik.PDPTK, bool) {
+	if tapel != "" {
+		data, ok := s.records[key(npsn, tapel)]
+		return data, ok
+	}
+
+	var latest dapodik.PDPTK
+	found := false
+	for _, data := range s.records {
+		if data.NPSN == npsn && (!found || data.Tapel > latest.Tapel) {
+			latest = data
+			found = true
+		}
+	}
+	return latest, found
+}
+
+func key(npsn, tapel string) string {
+	return npsn + "|" + tapel
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
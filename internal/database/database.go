@@ -58,20 +58,28 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
-// AutoMigrate runs auto migration for models
-// DISABLED: Menggunakan database yang sudah ada (testing_lpmaarif1)
-func AutoMigrate(db *gorm.DB) error {
-	log.Println("Skipping auto migration (using existing database schema)")
-	// Database schema already exists, no need to migrate
-	// Models are mapped to existing tables:
-	// - models.Satpen -> satpen
-	// - models.Provinsi -> provinsi
-	// - models.Kabupaten -> kabupaten
-	// - models.JenjangPendidikan -> jenjang_pendidikan
-	// - models.KategoriSatpen -> kategori_satpen
-	// - models.PengurusCabang -> pengurus_cabang
-	// - models.PDPTK -> pdptk
-	return nil
+// AutoMigrate applies the pending schema migrations when database.auto_migrate
+// is set, and otherwise only warns about them. Run cmd/migrate to apply them
+// by hand.
+func AutoMigrate(db *gorm.DB, cfg *config.Config) error {
+	if !cfg.Database.AutoMigrate {
+		states, err := MigrationStatus(db)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			if state.AppliedAt == nil {
+				log.Printf("Pending migration %04d_%s (database.auto_migrate is off)", state.Version, state.Name)
+			}
+		}
+		return nil
+	}
+
+	applied, err := Migrate(db)
+	for _, mig := range applied {
+		log.Printf("Applied migration %04d_%s", mig.Version, mig.Name)
+	}
+	return err
 }
 
 // Close closes database connection
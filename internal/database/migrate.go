@@ -0,0 +1,271 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationTable records the applied migrations. The main application owns
+// the table called migrations.
+const migrationTable = "schema_migrations"
+
+// migrationLock is the GET_LOCK name held while migrating, so instances
+// starting together don't apply the same migration twice
+const migrationLock = "satpen_api_migrate"
+
+const migrationLockTimeout = 60 // seconds
+
+// MySQL errors of an object that already exists (up) or is already gone
+// (down). They are skipped so a database changed by hand with the old
+// docs/*.sql scripts can adopt the migrations.
+var (
+	ignoredUpErrors   = map[uint16]bool{1050: true, 1060: true, 1061: true} // table, column, key exists
+	ignoredDownErrors = map[uint16]bool{1051: true, 1091: true}             // unknown table, can't drop column/key
+)
+
+// ErrIrreversible is returned when rolling back a migration without a down script
+var ErrIrreversible = errors.New("migration has no down script")
+
+// Migration is one versioned schema change, read from
+// migrations/<version>_<name>.up.sql and the optional .down.sql
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is a migration and when it was applied, nil when pending
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   uint      `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;size:255;not null"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (schemaMigration) TableName() string {
+	return migrationTable
+}
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migrations returns the embedded migrations ordered by version
+func Migrations() ([]Migration, error) {
+	return readMigrations(migrationFiles)
+}
+
+// readMigrations reads the migrations directory of fsys
+func readMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		m := migrationFileName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.ParseUint(m[1], 10, 32)
+		content, err := fs.ReadFile(fsys, path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[uint(version)]
+		if !ok {
+			mig = &Migration{Version: uint(version), Name: m[2]}
+			byVersion[uint(version)] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrationStatus returns every embedded migration with the time it was applied
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		db = nil
+	}
+	return migrationStates(db, migrations)
+}
+
+// Migrate applies the pending migrations in version order and returns them.
+// MySQL commits DDL implicitly, so a migration failing halfway is not rolled
+// back; fix the cause and run it again.
+func Migrate(db *gorm.DB) ([]Migration, error) {
+	var applied []Migration
+	err := withMigrationLock(db, func(conn *gorm.DB, states []MigrationState) error {
+		for _, state := range states {
+			if state.AppliedAt != nil {
+				continue
+			}
+			if err := execScript(conn, state.Up, ignoredUpErrors); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", state.Version, state.Name, err)
+			}
+			record := schemaMigration{Version: state.Version, Name: state.Name, AppliedAt: time.Now()}
+			if err := conn.Create(&record).Error; err != nil {
+				return err
+			}
+			applied = append(applied, state.Migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Rollback reverts the last steps applied migrations, newest first, and
+// returns them
+func Rollback(db *gorm.DB, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := withMigrationLock(db, func(conn *gorm.DB, states []MigrationState) error {
+		for i := len(states) - 1; i >= 0 && len(reverted) < steps; i-- {
+			state := states[i]
+			if state.AppliedAt == nil {
+				continue
+			}
+			if state.Down == "" {
+				return fmt.Errorf("migration %d_%s: %w", state.Version, state.Name, ErrIrreversible)
+			}
+			if err := execScript(conn, state.Down, ignoredDownErrors); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", state.Version, state.Name, err)
+			}
+			if err := conn.Delete(&schemaMigration{}, "version = ?", state.Version).Error; err != nil {
+				return err
+			}
+			reverted = append(reverted, state.Migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// withMigrationLock runs fn on one connection holding migrationLock, with the
+// current state of the migrations
+func withMigrationLock(db *gorm.DB, fn func(conn *gorm.DB, states []MigrationState) error) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	return db.Connection(func(conn *gorm.DB) error {
+		var locked int
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", migrationLock, migrationLockTimeout).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if locked != 1 {
+			return fmt.Errorf("timed out waiting for migration lock %s", migrationLock)
+		}
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", migrationLock)
+
+		if err := conn.AutoMigrate(&schemaMigration{}); err != nil {
+			return fmt.Errorf("failed to create %s: %w", migrationTable, err)
+		}
+		states, err := migrationStates(conn, migrations)
+		if err != nil {
+			return err
+		}
+		return fn(conn, states)
+	})
+}
+
+// migrationStates pairs migrations with their schema_migrations records; with
+// a nil db all are pending
+func migrationStates(db *gorm.DB, migrations []Migration) ([]MigrationState, error) {
+	var records []schemaMigration
+	if db != nil {
+		if err := db.Find(&records).Error; err != nil {
+			return nil, err
+		}
+	}
+	appliedAt := make(map[uint]time.Time, len(records))
+	for _, r := range records {
+		appliedAt[r.Version] = r.AppliedAt
+	}
+
+	states := make([]MigrationState, len(migrations))
+	for i, mig := range migrations {
+		states[i].Migration = mig
+		if t, ok := appliedAt[mig.Version]; ok {
+			states[i].AppliedAt = &t
+		}
+	}
+	return states, nil
+}
+
+// execScript runs the statements of a migration script one by one, skipping
+// the MySQL errors in ignored
+func execScript(conn *gorm.DB, script string, ignored map[uint16]bool) error {
+	for _, stmt := range splitStatements(script) {
+		if err := conn.Exec(stmt).Error; err != nil {
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) && ignored[mysqlErr.Number] {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements splits a script on semicolons ending a line, dropping
+// lines that are only a -- comment
+func splitStatements(script string) []string {
+	var stmts []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
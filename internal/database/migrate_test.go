@@ -0,0 +1,143 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "one per line",
+			script: "DROP INDEX a ON t;\nDROP INDEX b ON t;\n",
+			want:   []string{"DROP INDEX a ON t", "DROP INDEX b ON t"},
+		},
+		{
+			name: "comments and blank lines dropped",
+			script: "-- hapus index lama\n\n  -- indented comment\nDROP INDEX a ON t;\n\n" +
+				"--\nDROP INDEX b ON t;\n-- trailing comment\n",
+			want: []string{"DROP INDEX a ON t", "DROP INDEX b ON t"},
+		},
+		{
+			name: "multi-line statement",
+			script: "CREATE TABLE t (\n  id int NOT NULL,\n  -- the name\n  name varchar(10)\n);\n" +
+				"ALTER TABLE t\n  ADD KEY idx_name (name);",
+			want: []string{
+				"CREATE TABLE t (\n  id int NOT NULL,\n  name varchar(10)\n)",
+				"ALTER TABLE t\n  ADD KEY idx_name (name)",
+			},
+		},
+		{
+			name:   "semicolon inside a line does not split",
+			script: "UPDATE t SET note = 'a;b' WHERE id = 1;\n",
+			want:   []string{"UPDATE t SET note = 'a;b' WHERE id = 1"},
+		},
+		{
+			name:   "last statement without semicolon",
+			script: "DROP INDEX a ON t;\nDROP INDEX b ON t",
+			want:   []string{"DROP INDEX a ON t", "DROP INDEX b ON t"},
+		},
+		{
+			name:   "only comments",
+			script: "-- nothing to do\n\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	migrations, err := readMigrations(fstest.MapFS{
+		"migrations/0002_second.up.sql":   file("CREATE TABLE b (id int);"),
+		"migrations/0002_second.down.sql": file("DROP TABLE b;"),
+		"migrations/0001_first.up.sql":    file("CREATE TABLE a (id int);"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "first", Up: "CREATE TABLE a (id int);"},
+		{Version: 2, Name: "second", Up: "CREATE TABLE b (id int);", Down: "DROP TABLE b;"},
+	}
+	if !reflect.DeepEqual(migrations, want) {
+		t.Errorf("readMigrations = %+v, want %+v", migrations, want)
+	}
+
+	invalid := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr string
+	}{
+		{
+			name: "two names for one version",
+			fsys: fstest.MapFS{
+				"migrations/0001_first.up.sql":   file("CREATE TABLE a (id int);"),
+				"migrations/0001_other.down.sql": file("DROP TABLE a;"),
+			},
+			wantErr: "migration 1 has two names",
+		},
+		{
+			name: "no up script",
+			fsys: fstest.MapFS{
+				"migrations/0001_first.up.sql":    file("CREATE TABLE a (id int);"),
+				"migrations/0002_second.down.sql": file("DROP TABLE b;"),
+			},
+			wantErr: "migration 2_second has no up script",
+		},
+		{
+			name:    "invalid file name",
+			fsys:    fstest.MapFS{"migrations/0001-first.sql": file("CREATE TABLE a (id int);")},
+			wantErr: "invalid migration file name",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readMigrations(tt.fsys); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestEmbeddedMigrations checks the shipped scripts: versions are 1..n without
+// gaps, every script splits into statements and every migration after the
+// base schema can be rolled back
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	for i, mig := range migrations {
+		if mig.Version != uint(i+1) {
+			t.Errorf("migration %d_%s at position %d, want version %d", mig.Version, mig.Name, i, i+1)
+		}
+		if len(splitStatements(mig.Up)) == 0 {
+			t.Errorf("migration %d_%s: up script has no statements", mig.Version, mig.Name)
+		}
+		if mig.Version == 1 {
+			continue
+		}
+		if mig.Down == "" {
+			t.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+		} else if len(splitStatements(mig.Down)) == 0 {
+			t.Errorf("migration %d_%s: down script has no statements", mig.Version, mig.Name)
+		}
+	}
+}
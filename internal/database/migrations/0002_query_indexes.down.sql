@@ -0,0 +1,10 @@
+DROP INDEX idx_kategori_nm_kategori ON kategori_satpen;
+DROP INDEX idx_jenjang_nm_jenjang ON jenjang_pendidikan;
+DROP INDEX idx_kabupaten_nama_kab ON kabupaten;
+DROP INDEX idx_provinsi_nm_prov ON provinsi;
+DROP INDEX idx_pdptk_id_satpen_tapel ON pdptk;
+DROP INDEX idx_satpen_status_jenjang ON satpen;
+DROP INDEX idx_satpen_status_prov ON satpen;
+DROP INDEX idx_satpen_updated_at ON satpen;
+DROP INDEX idx_satpen_created_at ON satpen;
+DROP INDEX idx_satpen_status ON satpen;
@@ -0,0 +1,24 @@
+-- Index untuk filter dan join yang sering dipakai (dulu docs/db_indexes.sql).
+-- Kolom yang sudah punya index dari foreign key (satpen.id_prov, id_kab,
+-- id_jenjang, id_kategori, pdptk.id_satpen, pdptk.tapel, kabupaten.id_prov)
+-- tidak diberi index kedua.
+
+-- Default query selalu filter status = 'setujui'
+CREATE INDEX idx_satpen_status ON satpen(status);
+
+-- ?sort=created_at / ?sort=updated_at
+CREATE INDEX idx_satpen_created_at ON satpen(created_at);
+CREATE INDEX idx_satpen_updated_at ON satpen(updated_at);
+
+-- Kombinasi filter yang paling sering: status + provinsi, status + jenjang
+CREATE INDEX idx_satpen_status_prov ON satpen(status, id_prov);
+CREATE INDEX idx_satpen_status_jenjang ON satpen(status, id_jenjang);
+
+-- Subquery PDPTK terbaru: MAX(tapel) per id_satpen
+CREATE INDEX idx_pdptk_id_satpen_tapel ON pdptk(id_satpen, tapel DESC);
+
+-- Filter nama di tabel referensi
+CREATE INDEX idx_provinsi_nm_prov ON provinsi(nm_prov);
+CREATE INDEX idx_kabupaten_nama_kab ON kabupaten(nama_kab);
+CREATE INDEX idx_jenjang_nm_jenjang ON jenjang_pendidikan(nm_jenjang);
+CREATE INDEX idx_kategori_nm_kategori ON kategori_satpen(nm_kategori);
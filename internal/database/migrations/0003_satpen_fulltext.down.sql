@@ -0,0 +1 @@
+DROP INDEX idx_satpen_fulltext ON satpen;
@@ -0,0 +1,3 @@
+-- ?search=...&search_mode=natural|boolean. Kolom harus sama persis dengan
+-- fulltextColumns di internal/repository/satpen_search.go
+CREATE FULLTEXT INDEX idx_satpen_fulltext ON satpen(nm_satpen, alamat, yayasan, kecamatan, kelurahan);
@@ -0,0 +1,5 @@
+DROP INDEX idx_satpen_location ON satpen;
+
+ALTER TABLE satpen
+  DROP COLUMN bujur,
+  DROP COLUMN lintang;
@@ -0,0 +1,9 @@
+-- GET /satpen/nearby dan /satpen/clusters. Derajat desimal WGS84, sama
+-- seperti lintang/bujur di profile_pengurus_cabang tetapi numerik agar bisa
+-- dihitung jaraknya
+ALTER TABLE satpen
+  ADD COLUMN lintang DECIMAL(10,7) NULL DEFAULT NULL AFTER alamat,
+  ADD COLUMN bujur DECIMAL(11,7) NULL DEFAULT NULL AFTER lintang;
+
+-- Bounding box prefilter di FindNearby
+CREATE INDEX idx_satpen_location ON satpen(lintang, bujur);
@@ -0,0 +1,15 @@
+-- POST /admin/satpen/merge. before_data/after_data: snapshot JSON sebelum
+-- dan sesudah perubahan
+CREATE TABLE IF NOT EXISTS audit_log (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  actor varchar(100) NOT NULL,
+  action varchar(50) NOT NULL,
+  entity_type varchar(50) NOT NULL,
+  entity_id bigint unsigned NOT NULL,
+  before_data json DEFAULT NULL,
+  after_data json DEFAULT NULL,
+  created_at timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  KEY idx_audit_log_entity (entity_type, entity_id),
+  KEY idx_audit_log_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+DROP INDEX idx_audit_log_actor ON audit_log;
+
+ALTER TABLE audit_log
+  DROP COLUMN request_id,
+  DROP COLUMN ip,
+  DROP COLUMN changes;
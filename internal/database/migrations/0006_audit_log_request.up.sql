@@ -0,0 +1,8 @@
+-- GET /audit. changes: {"field": {"from": ..., "to": ...}} antara
+-- before_data dan after_data
+ALTER TABLE audit_log
+  ADD COLUMN changes json DEFAULT NULL AFTER after_data,
+  ADD COLUMN ip varchar(45) DEFAULT NULL AFTER changes,
+  ADD COLUMN request_id varchar(64) DEFAULT NULL AFTER ip;
+
+CREATE INDEX idx_audit_log_actor ON audit_log(actor, created_at);
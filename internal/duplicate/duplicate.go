@@ -0,0 +1,239 @@
+// Package duplicate finds satpen registered more than once under different
+// no_registrasi, by comparing normalised names and addresses of the satpen of
+// one kabupaten.
+package duplicate
+
+import (
+	"satpen-api/internal/region"
+	"satpen-api/internal/suggest"
+	"sort"
+	"strings"
+)
+
+// weights of each compared field in the pair score. A field that is empty on
+// either side is left out and the others are weighted up.
+var weights = struct {
+	Nama, Alamat, Kelurahan, Yayasan float64
+}{0.5, 0.25, 0.15, 0.1}
+
+// numberMismatchFactor scales the name similarity of names carrying
+// different numbers: "MI NU 01" and "MI NU 02" are different schools
+const numberMismatchFactor = 0.5
+
+// Record is one satpen as compared by Detect
+type Record struct {
+	ID           uint   `json:"id"`
+	NoRegistrasi string `json:"no_registrasi"`
+	NPSN         string `json:"npsn"`
+	Nama         string `json:"nama"`
+	Alamat       string `json:"alamat"`
+	Kelurahan    string `json:"kelurahan"`
+	Yayasan      string `json:"yayasan"`
+	Status       string `json:"status"`
+	IDKab        uint   `json:"-"`
+	Kabupaten    string `json:"-"`
+}
+
+// Pair is a candidate duplicate. A has the lower ID.
+type Pair struct {
+	Kabupaten string      `json:"kabupaten"`
+	Score     float64     `json:"score"` // 0-1
+	Fields    FieldScores `json:"fields"`
+	A         Record      `json:"a"`
+	B         Record      `json:"b"`
+}
+
+// FieldScores are the similarities of the compared fields, nil when the
+// field is empty on either side
+type FieldScores struct {
+	Nama      *float64 `json:"nama"`
+	Alamat    *float64 `json:"alamat"`
+	Kelurahan *float64 `json:"kelurahan"`
+	Yayasan   *float64 `json:"yayasan"`
+}
+
+// prepared holds the normalised fields of one record
+type prepared struct {
+	record                           Record
+	nama, alamat, kelurahan, yayasan trigramSet
+	numbers                          string
+}
+
+// Detect compares every two records of the same kabupaten and returns the
+// pairs scoring at least minScore, highest score first
+func Detect(records []Record, minScore float64) []Pair {
+	byKab := make(map[uint][]prepared)
+	var kabs []uint
+	for _, r := range records {
+		if _, ok := byKab[r.IDKab]; !ok {
+			kabs = append(kabs, r.IDKab)
+		}
+		byKab[r.IDKab] = append(byKab[r.IDKab], prepare(r))
+	}
+
+	pairs := []Pair{}
+	for _, kab := range kabs {
+		group := byKab[kab]
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				pair := compare(group[i], group[j])
+				if pair.Score >= minScore {
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		if pairs[i].A.ID != pairs[j].A.ID {
+			return pairs[i].A.ID < pairs[j].A.ID
+		}
+		return pairs[i].B.ID < pairs[j].B.ID
+	})
+	return pairs
+}
+
+func prepare(r Record) prepared {
+	nama := suggest.Normalize(r.Nama)
+	return prepared{
+		record:    r,
+		nama:      newTrigramSet(nama),
+		alamat:    newTrigramSet(NormalizeAlamat(r.Alamat)),
+		kelurahan: newTrigramSet(region.Normalize(r.Kelurahan)),
+		yayasan:   newTrigramSet(NormalizeYayasan(r.Yayasan)),
+		numbers:   numbers(nama),
+	}
+}
+
+func compare(a, b prepared) Pair {
+	if a.record.ID > b.record.ID {
+		a, b = b, a
+	}
+	pair := Pair{Kabupaten: a.record.Kabupaten, A: a.record, B: b.record}
+
+	var total, weight float64
+	add := func(x, y trigramSet, w, factor float64) *float64 {
+		if len(x) == 0 || len(y) == 0 {
+			return nil
+		}
+		s := round(dice(x, y) * factor)
+		total += s * w
+		weight += w
+		return &s
+	}
+
+	namaFactor := 1.0
+	if a.numbers != "" && b.numbers != "" && a.numbers != b.numbers {
+		namaFactor = numberMismatchFactor
+	}
+	pair.Fields.Nama = add(a.nama, b.nama, weights.Nama, namaFactor)
+	pair.Fields.Alamat = add(a.alamat, b.alamat, weights.Alamat, 1)
+	pair.Fields.Kelurahan = add(a.kelurahan, b.kelurahan, weights.Kelurahan, 1)
+	pair.Fields.Yayasan = add(a.yayasan, b.yayasan, weights.Yayasan, 1)
+
+	// Without a name there is nothing to go on
+	if pair.Fields.Nama != nil && weight > 0 {
+		pair.Score = round(total / weight)
+	}
+	return pair
+}
+
+// alamatWords maps spelling variants of address words to one form
+var alamatWords = map[string]string{
+	"jalan":     "jl",
+	"jln":       "jl",
+	"nomor":     "no",
+	"nomer":     "no",
+	"dusun":     "dsn",
+	"desa":      "ds",
+	"kelurahan": "kel",
+	"kecamatan": "kec",
+	"kabupaten": "kab",
+	"gang":      "gg",
+	"rukun":     "",
+	"tetangga":  "",
+	"warga":     "",
+}
+
+// NormalizeAlamat lowercases an address, collapses punctuation and unifies
+// the usual abbreviations: "Jalan Raya No. 5" -> "jl raya no 5"
+func NormalizeAlamat(alamat string) string {
+	words := strings.Fields(suggest.Normalize(alamat))
+	out := words[:0]
+	for _, w := range words {
+		if short, ok := alamatWords[w]; ok {
+			if short == "" {
+				continue
+			}
+			w = short
+		}
+		out = append(out, w)
+	}
+	return strings.Join(out, " ")
+}
+
+// NormalizeYayasan normalises a yayasan name without the word yayasan itself
+func NormalizeYayasan(yayasan string) string {
+	words := strings.Fields(suggest.Normalize(yayasan))
+	out := words[:0]
+	for _, w := range words {
+		if w == "yayasan" || w == "yys" || w == "yay" {
+			continue
+		}
+		out = append(out, w)
+	}
+	return strings.Join(out, " ")
+}
+
+// numbers returns the numbers in a normalised name without leading zeros,
+// space separated: "mi nu 01" -> "1"
+func numbers(normalized string) string {
+	var out []string
+	for _, w := range strings.Fields(normalized) {
+		if strings.Trim(w, "0123456789") != "" {
+			continue
+		}
+		if n := strings.TrimLeft(w, "0"); n != "" {
+			out = append(out, n)
+		} else {
+			out = append(out, "0")
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+type trigramSet map[string]struct{}
+
+// newTrigramSet returns the trigrams of s padded with spaces, so word order
+// barely matters and single typos cost only a few grams
+func newTrigramSet(s string) trigramSet {
+	set := make(trigramSet)
+	for _, word := range strings.Fields(s) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// dice is the Sørensen-Dice coefficient of two trigram sets
+func dice(a, b trigramSet) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for gram := range a {
+		if _, ok := b[gram]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+func round(v float64) float64 {
+	return float64(int(v*1000+0.5)) / 1000
+}
@@ -0,0 +1,186 @@
+package duplicate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeAlamat(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Jalan Raya No. 5", "jl raya no 5"},
+		{"JLN. Raya Nomor 5", "jl raya no 5"},
+		{"Jl. Raya Nomer 5", "jl raya no 5"},
+		{"Dusun Krajan, Desa Sumberejo", "dsn krajan ds sumberejo"},
+		{"Gang Mawar Rukun Tetangga 01 Rukun Warga 02", "gg mawar 01 02"},
+		{"Kelurahan Kauman Kecamatan Kota Kabupaten Kudus", "kel kauman kec kota kab kudus"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeAlamat(tt.in); got != tt.want {
+			t.Errorf("NormalizeAlamat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeYayasan(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Yayasan Pendidikan Ma'arif", "pendidikan maarif"},
+		{"YYS. Pendidikan Maarif", "pendidikan maarif"},
+		{"Yay. Nahdlatul Ulama", "nu"},
+		{"Yayasan", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeYayasan(tt.in); got != tt.want {
+			t.Errorf("NormalizeYayasan(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"mi nu 01", "1"},
+		{"mi nu 1", "1"},
+		{"mi nu 00", "0"},
+		{"smk 2 malang 010", "2 10"},
+		{"mi nurul huda", ""},
+		{"mi 1a", ""}, // not a number on its own
+	}
+	for _, tt := range tests {
+		if got := numbers(tt.in); got != tt.want {
+			t.Errorf("numbers(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// record is a satpen of kabupaten 1 with every compared field set
+func record(id uint, nama, alamat string) Record {
+	return Record{ID: id, Nama: nama, Alamat: alamat, Kelurahan: "Kauman", Yayasan: "Yayasan Pendidikan Ma'arif", IDKab: 1, Kabupaten: "Kabupaten Kudus"}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Record
+		wantMin float64
+		wantMax float64
+	}{
+		{"identical", record(1, "MI NU 01", "Jl. Merdeka 5"), record(2, "MI NU 01", "Jl. Merdeka 5"), 1, 1},
+		{"spelled out", record(1, "Madrasah Ibtidaiyah Nahdlatul Ulama 01", "Jalan Merdeka No. 5"), record(2, "MI NU 01", "Jl Merdeka no 5"), 1, 1},
+		{"leading zero", record(1, "MI NU 01", "Jl. Merdeka 5"), record(2, "MI NU 1", "Jl. Merdeka 5"), 0.85, 0.9},
+		{"different number", record(1, "MI NU 01", "Jl. Merdeka 5"), record(2, "MI NU 02", "Jl. Merdeka 5"), 0.65, 0.7},
+		{"different school", record(1, "MI Nurul Huda", "Jl. Merdeka 5"), record(2, "MTs Darul Ulum", "Dusun Krajan"), 0, 0.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := compare(prepare(tt.a), prepare(tt.b))
+			if pair.Score < tt.wantMin || pair.Score > tt.wantMax {
+				t.Errorf("score = %v, want %v-%v (fields %+v)", pair.Score, tt.wantMin, tt.wantMax, fieldValues(pair.Fields))
+			}
+		})
+	}
+}
+
+func TestCompareNumberMismatch(t *testing.T) {
+	a, b := prepare(record(1, "MI NU 01", "")), prepare(record(2, "MI NU 02", ""))
+	pair := compare(a, b)
+
+	// The name similarity is halved, the other fields are not affected
+	want := round(dice(a.nama, b.nama) * numberMismatchFactor)
+	if pair.Fields.Nama == nil || *pair.Fields.Nama != want {
+		t.Errorf("nama = %v, want %v", fieldValues(pair.Fields)[0], want)
+	}
+	if *pair.Fields.Kelurahan != 1 || *pair.Fields.Yayasan != 1 {
+		t.Errorf("fields = %v", fieldValues(pair.Fields))
+	}
+
+	// Names without a number, or with a number on one side only, are not
+	// penalised
+	for _, names := range [][2]string{{"MI Nurul Huda", "MI Nurul Huda"}, {"MI NU 01", "MI NU"}} {
+		a, b := prepare(record(1, names[0], "")), prepare(record(2, names[1], ""))
+		pair := compare(a, b)
+		if want := round(dice(a.nama, b.nama)); *pair.Fields.Nama != want {
+			t.Errorf("%q vs %q: nama = %v, want %v", names[0], names[1], *pair.Fields.Nama, want)
+		}
+	}
+}
+
+func TestCompareWeights(t *testing.T) {
+	same := record(1, "MI NU 01", "Jl. Merdeka 5")
+
+	tests := []struct {
+		name       string
+		other      Record
+		wantScore  float64
+		wantFields [4]interface{} // nama, alamat, kelurahan, yayasan
+	}{
+		// Alamat shares no trigram: 0.5 + 0.15 + 0.1 out of 1
+		{"alamat differs", record(2, "MI NU 01", "Dusun Krajan"), 0.75, [4]interface{}{1.0, 0.0, 1.0, 1.0}},
+		// Alamat left out: the other fields are weighted up to 1
+		{"alamat empty", record(2, "MI NU 01", ""), 1, [4]interface{}{1.0, nil, 1.0, 1.0}},
+		// Kelurahan and yayasan differ, alamat empty: 0.5 out of 0.75
+		{"only nama matches", Record{ID: 2, Nama: "MI NU 01", Kelurahan: "Jepang", Yayasan: "Yayasan Darul Ulum", IDKab: 1}, 0.667, [4]interface{}{1.0, nil, 0.0, 0.0}},
+		// Nothing but the name
+		{"nama only", Record{ID: 2, Nama: "MI NU 01", IDKab: 1}, 1, [4]interface{}{1.0, nil, nil, nil}},
+		// Without a name on one side there is no score at all
+		{"nama empty", Record{ID: 2, Alamat: "Jl. Merdeka 5", Kelurahan: "Kauman", IDKab: 1}, 0, [4]interface{}{nil, 1.0, 1.0, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := compare(prepare(same), prepare(tt.other))
+			if pair.Score != tt.wantScore {
+				t.Errorf("score = %v, want %v", pair.Score, tt.wantScore)
+			}
+			if got := fieldValues(pair.Fields); got != tt.wantFields {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	records := []Record{
+		record(3, "MI NU 1", "Jl. Merdeka 5"),
+		record(1, "MI NU 01", "Jl. Merdeka 5"),
+		record(2, "MI NU 02", "Jl. Merdeka 5"),
+		record(4, "MTs Darul Ulum", "Dusun Krajan"),
+		// The same school in another kabupaten is not compared
+		{ID: 5, Nama: "MI NU 01", Alamat: "Jl. Merdeka 5", Kelurahan: "Kauman", IDKab: 2, Kabupaten: "Kabupaten Pati"},
+	}
+
+	pairs := Detect(records, 0.6)
+	var got [][2]uint
+	for _, p := range pairs {
+		got = append(got, [2]uint{p.A.ID, p.B.ID})
+		if p.Kabupaten != "Kabupaten Kudus" {
+			t.Errorf("pair %d-%d in %q", p.A.ID, p.B.ID, p.Kabupaten)
+		}
+	}
+	// Highest score first, lower ID as A: 01/1 above the pairs with 02
+	want := [][2]uint{{1, 3}, {1, 2}, {2, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pairs = %v, want %v", got, want)
+	}
+	for i := 1; i < len(pairs); i++ {
+		if pairs[i].Score > pairs[i-1].Score {
+			t.Errorf("pairs not sorted by score: %v after %v", pairs[i].Score, pairs[i-1].Score)
+		}
+	}
+
+	if pairs := Detect(records, 0.99); len(pairs) != 0 {
+		t.Errorf("Detect(0.99) = %+v, want none", pairs)
+	}
+	if pairs := Detect(nil, 0); pairs == nil || len(pairs) != 0 {
+		t.Errorf("Detect(nil) = %#v, want an empty slice", pairs)
+	}
+}
+
+// fieldValues dereferences the field scores for comparison and printing
+func fieldValues(f FieldScores) [4]interface{} {
+	var out [4]interface{}
+	for i, v := range []*float64{f.Nama, f.Alamat, f.Kelurahan, f.Yayasan} {
+		if v != nil {
+			out[i] = *v
+		}
+	}
+	return out
+}
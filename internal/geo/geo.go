@@ -0,0 +1,72 @@
+// Package geo holds coordinate validation and great-circle distance helpers.
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// EarthRadiusKm is the mean Earth radius used for haversine distances
+const EarthRadiusKm = 6371.0
+
+// kmPerDegree is the length of one degree of latitude
+const kmPerDegree = 111.045
+
+var (
+	// ErrLatitude is returned for a latitude outside [-90, 90]
+	ErrLatitude = errors.New("latitude must be between -90 and 90")
+	// ErrLongitude is returned for a longitude outside [-180, 180]
+	ErrLongitude = errors.New("longitude must be between -180 and 180")
+)
+
+// ValidateLatitude checks that lat is a finite latitude
+func ValidateLatitude(lat float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return ErrLatitude
+	}
+	return nil
+}
+
+// ValidateLongitude checks that lng is a finite longitude
+func ValidateLongitude(lng float64) error {
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return ErrLongitude
+	}
+	return nil
+}
+
+// Validate checks both coordinates, reporting the latitude first
+func Validate(lat, lng float64) error {
+	if err := ValidateLatitude(lat); err != nil {
+		return err
+	}
+	return ValidateLongitude(lng)
+}
+
+// Haversine returns the great-circle distance in km between two points
+func Haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox returns a box that contains every point within radiusKm of
+// (lat, lng), for index-friendly prefiltering before the exact distance
+func BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	dLat := radiusKm / kmPerDegree
+	minLat, maxLat = math.Max(-90, lat-dLat), math.Min(90, lat+dLat)
+
+	cos := math.Cos(radians(lat))
+	if cos < 0.01 || maxLat == 90 || minLat == -90 {
+		// Near the poles every longitude is close
+		return minLat, maxLat, -180, 180
+	}
+	dLng := radiusKm / (kmPerDegree * cos)
+	return minLat, maxLat, math.Max(-180, lng-dLng), math.Min(180, lng+dLng)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,52 @@
+// Package geojson holds the RFC 7946 types served to map clients.
+package geojson
+
+// ContentType is the media type of GeoJSON documents
+const ContentType = "application/geo+json"
+
+// FeatureCollection is a GeoJSON FeatureCollection. Meta is a foreign member
+// for pagination and similar information outside the features.
+type FeatureCollection struct {
+	Type     string      `json:"type"`
+	Features []Feature   `json:"features"`
+	Meta     interface{} `json:"meta,omitempty"`
+}
+
+// Feature is a GeoJSON Feature. Geometry is null for features without a
+// location, as RFC 7946 allows.
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Point                 `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Point is a GeoJSON Point geometry
+type Point struct {
+	Type string `json:"type"`
+	// Coordinates are longitude first, then latitude
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// NewFeatureCollection returns an empty collection ready for features
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+}
+
+// NewPoint returns a Point at lat, lng
+func NewPoint(lat, lng float64) *Point {
+	return &Point{Type: "Point", Coordinates: [2]float64{lng, lat}}
+}
+
+// Add appends a feature with the given id, geometry and properties
+func (fc *FeatureCollection) Add(id interface{}, geometry *Point, properties map[string]interface{}) {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	fc.Features = append(fc.Features, Feature{
+		Type:       "Feature",
+		ID:         id,
+		Geometry:   geometry,
+		Properties: properties,
+	})
+}
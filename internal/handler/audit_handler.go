@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"satpen-api/internal/models"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(service service.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// GetAuditLog handles GET /api/v1/audit?entity_type=satpen&entity_id=1&actor=...&from=2024-01-01&to=2024-01-31
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	invalid := make(map[string]string)
+	filter := &models.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+		From:       queryDate(c, "from", invalid),
+		To:         queryDate(c, "to", invalid),
+	}
+	if entityID := queryInt(c, "entity_id", invalid); entityID != nil {
+		filter.EntityID = uint(*entityID)
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		invalid["to"] = "must not be before from"
+	}
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	entries, pagination, err := h.service.GetAuditLog(filter, page, limit)
+	if err != nil {
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Audit log retrieved successfully", gin.H{
+		"audit":      entries,
+		"pagination": pagination,
+	})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"satpen-api/internal/cluster"
+	"satpen-api/internal/geojson"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxZoom is the deepest zoom level of common web map tiles
+const maxZoom = 22
+
+type ClusterHandler struct {
+	service service.ClusterService
+}
+
+func NewClusterHandler(service service.ClusterService) *ClusterHandler {
+	return &ClusterHandler{service: service}
+}
+
+// GetClusters handles GET /api/v1/satpen/clusters?bbox=&zoom=
+// bbox is minLng,minLat,maxLng,maxLat (Leaflet's toBBoxString order)
+func (h *ClusterHandler) GetClusters(c *gin.Context) {
+	invalid := make(map[string]string)
+
+	bbox, err := parseBBox(c.Query("bbox"))
+	if err != nil {
+		invalid["bbox"] = err.Error()
+	}
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < 0 || zoom > maxZoom {
+		invalid["zoom"] = "must be an integer between 0 and " + strconv.Itoa(maxZoom)
+	}
+	geoJSON := parseFormat(c, invalid, formatJSON, formatGeoJSON) == formatGeoJSON
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+
+	result, err := h.service.Clusters(bbox, zoom, queryList(c, "jenjang"))
+	if err != nil {
+		switch {
+		case err.Error() == "cluster index not ready":
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, "Clusters not available yet", err.Error())
+		case errors.Is(err, cluster.ErrBBox):
+			utils.ValidationErrorResponse(c, "Invalid query parameters", gin.H{"bbox": err.Error()})
+		default:
+			utils.InternalErrorResponse(c, err)
+		}
+		return
+	}
+
+	if geoJSON {
+		c.Header("Content-Type", geojson.ContentType)
+		c.JSON(http.StatusOK, clusterFeatures(result, zoom))
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Clusters retrieved successfully", gin.H{
+		"zoom":      zoom,
+		"clusters":  result.Clusters,
+		"points":    result.Points,
+		"truncated": result.Truncated,
+		"omitted":   result.Omitted,
+	})
+}
+
+// clusterFeatures returns clusters and points as features, marking clusters
+// with cluster and point_count properties like supercluster does
+func clusterFeatures(result *cluster.Result, zoom int) *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+	for _, cl := range result.Clusters {
+		fc.Add(nil, geojson.NewPoint(cl.Latitude, cl.Longitude), map[string]interface{}{
+			"cluster":     true,
+			"point_count": cl.Count,
+			"bbox":        cl.BBox,
+		})
+	}
+	for _, p := range result.Points {
+		fc.Add(p.ID, geojson.NewPoint(p.Latitude, p.Longitude), map[string]interface{}{
+			"cluster": false,
+			"npsn":    p.NPSN,
+			"nama":    p.Name,
+			"jenjang": p.Jenjang,
+		})
+	}
+	fc.Meta = gin.H{"zoom": zoom, "truncated": result.Truncated, "omitted": result.Omitted}
+	return fc
+}
+
+// parseBBox parses "minLng,minLat,maxLng,maxLat"
+func parseBBox(value string) (cluster.BBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return cluster.BBox{}, cluster.ErrBBox
+	}
+	var v [4]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return cluster.BBox{}, cluster.ErrBBox
+		}
+		v[i] = f
+	}
+	bbox := cluster.BBox{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}
+	return bbox, bbox.Validate()
+}
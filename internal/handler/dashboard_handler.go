@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DashboardHandler struct {
+	service service.DashboardService
+}
+
+func NewDashboardHandler(service service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{service: service}
+}
+
+// GetPengurusCabangDashboard handles GET /api/v1/pengurus-cabang/:id/dashboard
+func (h *DashboardHandler) GetPengurusCabangDashboard(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ID", err.Error())
+		return
+	}
+
+	dashboard, err := h.service.GetPengurusCabangDashboard(uint(id))
+	if err != nil {
+		if err.Error() == "pengurus cabang not found" {
+			utils.NotFoundResponse(c, "Pengurus cabang not found")
+			return
+		}
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Dashboard retrieved successfully", dashboard)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"satpen-api/internal/quality"
+	"satpen-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDataQualityIssues handles GET /api/v1/data-quality/issues?rule=npsn_invalid,email_invalid
+// Satpen failing the data quality rules, lowest score first, with the list
+// filters for region, jenjang and status
+func (h *SatpenHandler) GetDataQualityIssues(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+
+	rules := queryList(c, "rule")
+	var unknown []string
+	for _, rule := range rules {
+		if !quality.IsValidRule(rule) {
+			unknown = append(unknown, rule)
+		}
+	}
+	if len(unknown) > 0 {
+		invalid["rule"] = "unknown rule " + strings.Join(unknown, ", ")
+	}
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	items, pagination, summary, err := h.service.GetDataQualityIssues(filter, rules, page, limit)
+	if err != nil {
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data quality issues retrieved successfully", gin.H{
+		"issues":     items,
+		"pagination": pagination,
+		"summary":    summary,
+	})
+}
+
+// GetDataQualityRules handles GET /api/v1/data-quality/rules
+func (h *SatpenHandler) GetDataQualityRules(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Data quality rules retrieved successfully", quality.Rules)
+}
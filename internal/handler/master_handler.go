@@ -235,6 +235,108 @@ func (h *MasterHandler) GetPengurusCabangByID(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Pengurus cabang retrieved successfully", pengurusCabang)
 }
 
+// GetAllPengurusWilayah godoc
+// @Summary Get all pengurus wilayah
+// @Description Get list of pengurus wilayah (one per provinsi) with their profile
+// @Tags master
+// @Accept json
+// @Produce json
+// @Param search query string false "Search by nama provinsi"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /pengurus-wilayah [get]
+func (h *MasterHandler) GetAllPengurusWilayah(c *gin.Context) {
+	search := c.Query("search")
+
+	pengurusWilayah, err := h.service.GetAllPengurusWilayah(search)
+	if err != nil {
+		h.log.WithError(err).Error("Failed to get pengurus wilayah")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get pengurus wilayah", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pengurus wilayah retrieved successfully", pengurusWilayah)
+}
+
+// GetPengurusWilayahByProvinsi godoc
+// @Summary Get pengurus wilayah by provinsi
+// @Description Get the pengurus wilayah of a provinsi with its profile and pengurus cabang
+// @Tags master
+// @Accept json
+// @Produce json
+// @Param id path int true "Provinsi ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /pengurus-wilayah/{id} [get]
+func (h *MasterHandler) GetPengurusWilayahByProvinsi(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ID", err.Error())
+		return
+	}
+
+	pengurusWilayah, err := h.service.GetPengurusWilayahByProvinsi(uint(id))
+	if err != nil {
+		h.log.WithError(err).Error("Failed to get pengurus wilayah")
+		utils.ErrorResponse(c, http.StatusNotFound, "Pengurus wilayah not found", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pengurus wilayah retrieved successfully", pengurusWilayah)
+}
+
+// GetAllKategoriSatpen godoc
+// @Summary Get all kategori satpen
+// @Description Get list of all kategori satpen (akreditasi) with their konotasi and satpen count
+// @Tags master
+// @Accept json
+// @Produce json
+// @Param search query string false "Search by nama or konotasi"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /kategori-satpen [get]
+func (h *MasterHandler) GetAllKategoriSatpen(c *gin.Context) {
+	search := c.Query("search")
+
+	kategori, err := h.service.GetAllKategoriSatpen(search)
+	if err != nil {
+		h.log.WithError(err).Error("Failed to get kategori satpen")
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get kategori satpen", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Kategori satpen retrieved successfully", kategori)
+}
+
+// GetKategoriSatpenByID godoc
+// @Summary Get kategori satpen by ID
+// @Description Get single kategori satpen by ID with its satpen count
+// @Tags master
+// @Accept json
+// @Produce json
+// @Param id path int true "Kategori Satpen ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /kategori-satpen/{id} [get]
+func (h *MasterHandler) GetKategoriSatpenByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ID", err.Error())
+		return
+	}
+
+	kategori, err := h.service.GetKategoriSatpenByID(uint(id))
+	if err != nil {
+		h.log.WithError(err).Error("Failed to get kategori satpen")
+		utils.ErrorResponse(c, http.StatusNotFound, "Kategori satpen not found", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Kategori satpen retrieved successfully", kategori)
+}
+
 // GetAllJenjangPendidikan godoc
 // @Summary Get all jenjang pendidikan
 // @Description Get list of all jenjang pendidikan
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDrillDown handles GET /api/v1/satpen/statistics/drilldown
+// provinsi_id, kabupaten_id and kecamatan_key select the node whose children
+// are counted; the list filters apply as well
+func (h *SatpenHandler) GetDrillDown(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+
+	var path service.DrillDownPath
+	if id := queryInt(c, "provinsi_id", invalid); id != nil {
+		path.ProvinsiID = uint(*id)
+	}
+	if id := queryInt(c, "kabupaten_id", invalid); id != nil {
+		path.KabupatenID = uint(*id)
+	}
+	path.Kecamatan = c.Query("kecamatan_key")
+	if path.Kecamatan != "" && path.KabupatenID == 0 {
+		invalid["kecamatan_key"] = service.ErrDrillDownPath.Error()
+	}
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+
+	drillDown, err := h.service.GetDrillDown(path, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrDrillDownPath) {
+			utils.ValidationErrorResponse(c, "Invalid query parameters", gin.H{"kecamatan_key": err.Error()})
+			return
+		}
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Drill-down statistics retrieved successfully", drillDown)
+}
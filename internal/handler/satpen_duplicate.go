@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FindDuplicates handles GET /api/v1/admin/satpen/duplicates?kabupaten=...&min_score=0.8
+// Candidate duplicate pairs within each kabupaten, highest score first, with
+// the list filters
+func (h *SatpenHandler) FindDuplicates(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+
+	minScore := queryFloat(c, "min_score", false, invalid)
+	if _, ok := invalid["min_score"]; !ok && (minScore < 0 || minScore > 1) {
+		invalid["min_score"] = service.ErrMinScore.Error()
+	}
+	limit := queryInt(c, "limit", invalid)
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+
+	limitValue := 0
+	if limit != nil {
+		limitValue = *limit
+	}
+
+	report, err := h.service.FindDuplicates(filter, minScore, limitValue)
+	if err != nil {
+		if errors.Is(err, service.ErrMinScore) {
+			utils.ValidationErrorResponse(c, "Invalid query parameters", gin.H{"min_score": err.Error()})
+			return
+		}
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Duplicate candidates retrieved successfully", report)
+}
+
+// MergeSatpenRequest is the body of POST /admin/satpen/merge
+type MergeSatpenRequest struct {
+	SurvivorID uint `json:"survivor_id" binding:"required"`
+	LoserID    uint `json:"loser_id" binding:"required"`
+	Preview    bool `json:"preview"`
+}
+
+// MergeSatpen handles POST /api/v1/admin/satpen/merge
+// Moves the PDPTK, PTK and timeline of a duplicate to the surviving satpen
+// and deletes the duplicate; preview only reports what would move
+func (h *SatpenHandler) MergeSatpen(c *gin.Context) {
+	var req MergeSatpenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.service.MergeSatpen(c.Request.Context(), req.SurvivorID, req.LoserID, req.Preview)
+	if err != nil {
+		switch {
+		case err.Error() == "satuan pendidikan not found":
+			utils.NotFoundResponse(c, "Satuan pendidikan not found")
+		case errors.Is(err, service.ErrMergeSame):
+			utils.ValidationErrorResponse(c, "Invalid request body", gin.H{"loser_id": err.Error()})
+		default:
+			utils.InternalErrorResponse(c, err)
+		}
+		return
+	}
+
+	message := "Satuan pendidikan merged successfully"
+	if result.Preview {
+		message = "Merge preview retrieved successfully"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, result)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"satpen-api/internal/models"
+	"satpen-api/internal/service"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseSatpenFilter reads the satpen filter query parameters shared by the
+// list and export endpoints. Multi-value filters take comma separated values
+// (jenjang=MI,MTs). The returned map holds a reason per invalid parameter and
+// is empty when all are valid.
+func parseSatpenFilter(c *gin.Context) (*models.SatpenFilter, map[string]string) {
+	filter := &models.SatpenFilter{
+		Jenjang:        queryList(c, "jenjang"),
+		Provinsi:       queryList(c, "provinsi"),
+		Kabupaten:      queryList(c, "kabupaten"),
+		PengurusCabang: queryList(c, "pengurus_cabang"),
+		Kecamatan:      queryList(c, "kecamatan"),
+		Kelurahan:      queryList(c, "kelurahan"),
+		Yayasan:        strings.TrimSpace(c.Query("yayasan")),
+		Akreditasi:     queryList(c, "akreditasi"),
+		Search:         c.Query("search"),
+	}
+	invalid := make(map[string]string)
+
+	if searchMode := c.Query("search_mode"); searchMode != "" {
+		if !service.IsValidSearchMode(searchMode) {
+			invalid["search_mode"] = "must be one of: like, natural, boolean"
+		}
+		filter.SearchMode = searchMode
+	}
+
+	for _, status := range queryList(c, "status") {
+		if !isValidStatus(status) {
+			invalid["status"] = "must be aktif, non-aktif or one of: " + strings.Join(models.SatpenStatuses, ", ")
+			break
+		}
+		filter.Status = append(filter.Status, status)
+	}
+
+	filter.Verified = queryBool(c, "verified", invalid)
+	filter.HasLocation = queryBool(c, "has_location", invalid)
+
+	filter.TahunBerdiriMin = queryInt(c, "tahun_berdiri_min", invalid)
+	filter.TahunBerdiriMax = queryInt(c, "tahun_berdiri_max", invalid)
+	filter.JumlahSiswaMin = queryInt(c, "jumlah_siswa_min", invalid)
+	filter.JumlahSiswaMax = queryInt(c, "jumlah_siswa_max", invalid)
+	filter.TglRegistrasiFrom = queryDate(c, "tgl_registrasi_from", invalid)
+	filter.TglRegistrasiTo = queryDate(c, "tgl_registrasi_to", invalid)
+
+	if filter.TahunBerdiriMin != nil && filter.TahunBerdiriMax != nil && *filter.TahunBerdiriMin > *filter.TahunBerdiriMax {
+		invalid["tahun_berdiri_max"] = "must not be less than tahun_berdiri_min"
+	}
+	if filter.JumlahSiswaMin != nil && filter.JumlahSiswaMax != nil && *filter.JumlahSiswaMin > *filter.JumlahSiswaMax {
+		invalid["jumlah_siswa_max"] = "must not be less than jumlah_siswa_min"
+	}
+	if filter.TglRegistrasiFrom != nil && filter.TglRegistrasiTo != nil && filter.TglRegistrasiFrom.After(*filter.TglRegistrasiTo) {
+		invalid["tgl_registrasi_to"] = "must not be before tgl_registrasi_from"
+	}
+
+	return filter, invalid
+}
+
+// parseSatpenSort reads sort, recording unknown sort fields in invalid
+func parseSatpenSort(c *gin.Context, invalid map[string]string) string {
+	sort := c.Query("sort")
+	if err := service.ValidateSort(sort); err != nil {
+		invalid["sort"] = err.Error()
+	}
+	return sort
+}
+
+// Output formats selectable with format=
+const (
+	formatJSON    = "json"
+	formatGeoJSON = "geojson"
+	formatXLSX    = "xlsx"
+)
+
+// parseFormat reads format=, defaulting to the first allowed format
+func parseFormat(c *gin.Context, invalid map[string]string, allowed ...string) string {
+	format := c.Query("format")
+	if format == "" {
+		return allowed[0]
+	}
+	for _, f := range allowed {
+		if f == format {
+			return format
+		}
+	}
+	invalid["format"] = "must be one of: " + strings.Join(allowed, ", ")
+	return ""
+}
+
+// queryList splits a comma separated query parameter, dropping blank items
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// queryBool parses a true/false query parameter
+func queryBool(c *gin.Context, key string, invalid map[string]string) *bool {
+	value := c.Query(key)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		invalid[key] = "must be true or false"
+		return nil
+	}
+	return &b
+}
+
+// queryInt parses a non-negative integer query parameter
+func queryInt(c *gin.Context, key string, invalid map[string]string) *int {
+	value := c.Query(key)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		invalid[key] = "must be a non-negative integer"
+		return nil
+	}
+	return &n
+}
+
+// queryDate parses a YYYY-MM-DD query parameter
+func queryDate(c *gin.Context, key string, invalid map[string]string) *time.Time {
+	value := c.Query(key)
+	if value == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		invalid[key] = "must be a date in YYYY-MM-DD format"
+		return nil
+	}
+	return &t
+}
+
+func isValidStatus(status string) bool {
+	if status == "aktif" || status == "non-aktif" {
+		return true
+	}
+	for _, s := range models.SatpenStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
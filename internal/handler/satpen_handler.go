@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"satpen-api/internal/geojson"
+	"satpen-api/internal/models"
 	"satpen-api/internal/service"
 	"satpen-api/internal/utils"
 	"strconv"
@@ -19,76 +22,103 @@ func NewSatpenHandler(service service.SatpenService) *SatpenHandler {
 
 // GetAllSatpen handles GET /api/v1/satpen
 func (h *SatpenHandler) GetAllSatpen(c *gin.Context) {
-	// Parse query parameters
-	filters := make(map[string]interface{})
-
-	if jenjang := c.Query("jenjang"); jenjang != "" {
-		filters["jenjang"] = jenjang
-	}
-
-	if provinsi := c.Query("provinsi"); provinsi != "" {
-		filters["provinsi"] = provinsi
-	}
-
-	if kabupaten := c.Query("kabupaten"); kabupaten != "" {
-		filters["kabupaten"] = kabupaten
-	}
-
-	if search := c.Query("search"); search != "" {
-		filters["search"] = search
-	}
-
-	if akreditasi := c.Query("akreditasi"); akreditasi != "" {
-		filters["akreditasi"] = akreditasi
+	// Parse query parameters; sort is empty for -created_at, or relevance
+	// while searching in fulltext mode
+	filter, invalid := parseSatpenFilter(c)
+	sort := parseSatpenSort(c, invalid)
+	geoJSON := parseFormat(c, invalid, formatJSON, formatGeoJSON) == formatGeoJSON
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
 	}
-
-	if status := c.Query("status"); status != "" {
-		filters["status"] = status
-	}
-
-	if verified := c.Query("verified"); verified != "" {
-		if verified == "true" {
-			filters["verified"] = true
-		} else if verified == "false" {
-			filters["verified"] = false
-		}
+	if geoJSON {
+		// Features need a geometry
+		hasLocation := true
+		filter.HasLocation = &hasLocation
 	}
 
 	// Parse pagination
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
-	// Parse sort
-	sort := c.DefaultQuery("sort", "-created_at")
-
 	// Check if statistics are needed (skip by default for performance)
 	includeStats := c.Query("include_stats") == "true"
 
+	fieldSet, ok := parseFieldSet(c, geoJSON)
+	if !ok {
+		return
+	}
+
+	// Cursor pagination: ?pagination=cursor for the first page, then ?cursor=
+	cursor := c.Query("cursor")
+	if cursor != "" || c.Query("pagination") == "cursor" {
+		withCount := c.Query("count") != "false"
+
+		satpen, pagination, stats, err := h.service.GetAllSatpenCursor(filter, cursor, limit, sort, withCount, includeStats, fieldSet)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidCursor) || errors.Is(err, service.ErrCursorSort) {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cursor pagination", err.Error())
+				return
+			}
+			utils.InternalErrorResponse(c, err)
+			return
+		}
+
+		respondSatpenList(c, satpen, fieldSet, geoJSON, gin.H{"pagination": pagination}, stats)
+		return
+	}
+
 	// Get data from service
-	satpen, pagination, stats, err := h.service.GetAllSatpen(filters, page, limit, sort, includeStats)
+	satpen, pagination, stats, err := h.service.GetAllSatpen(filter, page, limit, sort, includeStats, fieldSet)
 	if err != nil {
 		utils.InternalErrorResponse(c, err)
 		return
 	}
 
-	// Response
-	responseData := gin.H{
-		"satpen":     satpen,
-		"pagination": pagination,
+	respondSatpenList(c, satpen, fieldSet, geoJSON, gin.H{"pagination": pagination}, stats)
+}
+
+// respondSatpenList writes a listing as {"satpen": [...], ...extra} or, for
+// GeoJSON, as a FeatureCollection carrying extra in its meta member. stats is
+// added when not nil.
+func respondSatpenList(c *gin.Context, satpen []models.Satpen, fieldSet *service.SatpenFieldSet, geoJSON bool, extra gin.H, stats *models.SatpenStatistics) {
+	if stats != nil {
+		extra["statistics"] = stats
 	}
 
-	if includeStats && stats != nil {
-		responseData["statistics"] = stats
+	if geoJSON {
+		fc, err := service.SatpenFeatureCollection(satpen, fieldSet)
+		if err != nil {
+			utils.InternalErrorResponse(c, err)
+			return
+		}
+		fc.Meta = extra
+		c.Header("Content-Type", geojson.ContentType)
+		c.JSON(http.StatusOK, fc)
+		return
+	}
+
+	shaped, err := fieldSet.Shape(satpen)
+	if err != nil {
+		utils.InternalErrorResponse(c, err)
+		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Satuan pendidikan retrieved successfully", responseData)
+	// Response
+	extra["satpen"] = shaped
+	utils.SuccessResponse(c, http.StatusOK, "Satuan pendidikan retrieved successfully", extra)
 }
 
 // GetSatpenByID handles GET /api/v1/satpen/:id
 func (h *SatpenHandler) GetSatpenByID(c *gin.Context) {
 	id := c.Param("id")
 
-	satpen, err := h.service.GetSatpenByID(id)
+	fieldSet, ok := parseFieldSet(c, false)
+	if !ok {
+		return
+	}
+
+	satpen, err := h.service.GetSatpenByID(id, fieldSet)
 	if err != nil {
 		if err.Error() == "satuan pendidikan not found" {
 			utils.NotFoundResponse(c, "Satuan pendidikan not found")
@@ -98,43 +128,54 @@ func (h *SatpenHandler) GetSatpenByID(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Satuan pendidikan retrieved successfully", satpen)
-}
+	shaped, err := fieldSet.Shape(satpen)
+	if err != nil {
+		utils.InternalErrorResponse(c, err)
+		return
+	}
 
-// DownloadExcel handles GET /api/v1/satpen/export
-// Supports same filters as GetAllSatpen: jenjang, provinsi, kabupaten, search, akreditasi, status, verified, sort
-func (h *SatpenHandler) DownloadExcel(c *gin.Context) {
-	filters := make(map[string]interface{})
+	utils.SuccessResponse(c, http.StatusOK, "Satuan pendidikan retrieved successfully", shaped)
+}
 
-	if jenjang := c.Query("jenjang"); jenjang != "" {
-		filters["jenjang"] = jenjang
-	}
-	if provinsi := c.Query("provinsi"); provinsi != "" {
-		filters["provinsi"] = provinsi
-	}
-	if kabupaten := c.Query("kabupaten"); kabupaten != "" {
-		filters["kabupaten"] = kabupaten
-	}
-	if search := c.Query("search"); search != "" {
-		filters["search"] = search
-	}
-	if akreditasi := c.Query("akreditasi"); akreditasi != "" {
-		filters["akreditasi"] = akreditasi
+// parseFieldSet reads fields= and include=, answering 400 with the unknown
+// names when validation fails. GeoJSON output has its own default fields.
+func parseFieldSet(c *gin.Context, geoJSON bool) (*service.SatpenFieldSet, bool) {
+	parse := service.ParseSatpenFieldSet
+	if geoJSON {
+		parse = service.ParseSatpenGeoFieldSet
 	}
-	if status := c.Query("status"); status != "" {
-		filters["status"] = status
-	}
-	if verified := c.Query("verified"); verified != "" {
-		if verified == "true" {
-			filters["verified"] = true
-		} else if verified == "false" {
-			filters["verified"] = false
+	include, includeSet := c.GetQuery("include")
+	fieldSet, err := parse(c.Query("fields"), include, includeSet)
+	if err != nil {
+		var fieldErr *service.FieldSetError
+		if errors.As(err, &fieldErr) {
+			utils.ValidationErrorResponse(c, "Invalid fields or include", fieldErr.Invalid)
+			return nil, false
 		}
+		utils.InternalErrorResponse(c, err)
+		return nil, false
+	}
+	return fieldSet, true
+}
+
+// DownloadExcel handles GET /api/v1/satpen/export
+// Supports the same filters and sort as GetAllSatpen, see parseSatpenFilter.
+// format=geojson exports located satpen as GeoJSON instead of Excel.
+func (h *SatpenHandler) DownloadExcel(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+	sort := parseSatpenSort(c, invalid)
+	format := parseFormat(c, invalid, formatXLSX, formatGeoJSON)
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
 	}
 
-	sort := c.DefaultQuery("sort", "-created_at")
+	if format == formatGeoJSON {
+		h.downloadGeoJSON(c, filter, sort)
+		return
+	}
 
-	buf, filename, err := h.service.ExportSatpen(filters, sort)
+	buf, filename, err := h.service.ExportSatpen(filter, sort)
 	if err != nil {
 		utils.InternalErrorResponse(c, err)
 		return
@@ -147,21 +188,68 @@ func (h *SatpenHandler) DownloadExcel(c *gin.Context) {
 	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
 }
 
-// GetStatistics handles GET /api/v1/satpen/statistics
-func (h *SatpenHandler) GetStatistics(c *gin.Context) {
-	// Parse filters
-	filters := make(map[string]interface{})
+func (h *SatpenHandler) downloadGeoJSON(c *gin.Context, filter *models.SatpenFilter, sort string) {
+	fieldSet, ok := parseFieldSet(c, true)
+	if !ok {
+		return
+	}
+	hasLocation := true
+	filter.HasLocation = &hasLocation
+
+	fc, filename, err := h.service.ExportSatpenGeoJSON(filter, sort, fieldSet)
+	if err != nil {
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", geojson.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.JSON(http.StatusOK, fc)
+}
+
+// GetSatpenRegions handles GET /api/v1/satpen/geojson/regions?level=provinsi|kabupaten
+// Satpen counts per region for choropleth maps, with the list filters
+func (h *SatpenHandler) GetSatpenRegions(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+	level := c.DefaultQuery("level", "provinsi")
+	if level != "provinsi" && level != "kabupaten" {
+		invalid["level"] = service.ErrRegionLevel.Error()
+	}
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
 
-	if provinsi := c.Query("provinsi"); provinsi != "" {
-		filters["provinsi"] = provinsi
+	fc, err := h.service.GetSatpenRegions(level, filter)
+	if err != nil {
+		utils.InternalErrorResponse(c, err)
+		return
 	}
 
-	if jenjang := c.Query("jenjang"); jenjang != "" {
-		filters["jenjang"] = jenjang
+	c.Header("Content-Type", geojson.ContentType)
+	c.JSON(http.StatusOK, fc)
+}
+
+// GetStatistics handles GET /api/v1/satpen/statistics
+// Every filter of GET /satpen applies; top sets the length of the top lists
+func (h *SatpenHandler) GetStatistics(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+	top := queryInt(c, "top", invalid)
+	if top != nil && *top < 1 {
+		invalid["top"] = "must be a positive integer"
+	}
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+	topN := 0
+	if top != nil {
+		topN = *top
 	}
 
 	// Get statistics
-	stats, err := h.service.GetStatistics(filters)
+	stats, err := h.service.GetStatistics(filter, topN)
 	if err != nil {
 		utils.InternalErrorResponse(c, err)
 		return
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"satpen-api/internal/models"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetIndikatorRanking handles GET /api/v1/satpen/statistics/indicators?indikator=rasio_siswa_guru&group_by=provinsi|kabupaten|jenjang
+// Ranks regions or jenjang by the median of an indicator, with percentiles and
+// the satpen outside the configured thresholds, with the list filters
+func (h *SatpenHandler) GetIndikatorRanking(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+
+	indikator := c.DefaultQuery("indikator", models.IndikatorRasioSiswaGuru)
+	if !models.IsValidIndikator(indikator) {
+		invalid["indikator"] = service.ErrIndikator.Error()
+	}
+	groupBy := c.DefaultQuery("group_by", "provinsi")
+	if groupBy != "provinsi" && groupBy != "kabupaten" && groupBy != "jenjang" {
+		invalid["group_by"] = service.ErrIndikatorGroup.Error()
+	}
+	order := c.DefaultQuery("order", "desc")
+	if order != "asc" && order != "desc" {
+		invalid["order"] = "must be asc or desc"
+	}
+	withFlagged := queryBool(c, "flagged", invalid)
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+
+	ranking, err := h.service.GetIndikatorRanking(indikator, groupBy, order == "asc", withFlagged != nil && *withFlagged, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrIndikator) {
+			utils.ValidationErrorResponse(c, "Invalid query parameters", gin.H{"indikator": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrIndikatorGroup) {
+			utils.ValidationErrorResponse(c, "Invalid query parameters", gin.H{"group_by": err.Error()})
+			return
+		}
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Indicator ranking retrieved successfully", ranking)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"satpen-api/internal/geo"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNearbySatpen handles GET /api/v1/satpen/nearby?lat=&lng=&radius_km=
+// Accepts the list filters, fields and include as well
+func (h *SatpenHandler) GetNearbySatpen(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+
+	lat := queryFloat(c, "lat", true, invalid)
+	lng := queryFloat(c, "lng", true, invalid)
+	radiusKm := queryFloat(c, "radius_km", false, invalid)
+	geoJSON := parseFormat(c, invalid, formatJSON, formatGeoJSON) == formatGeoJSON
+	if _, ok := invalid["lat"]; !ok {
+		if err := geo.ValidateLatitude(lat); err != nil {
+			invalid["lat"] = err.Error()
+		}
+	}
+	if _, ok := invalid["lng"]; !ok {
+		if err := geo.ValidateLongitude(lng); err != nil {
+			invalid["lng"] = err.Error()
+		}
+	}
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+
+	fieldSet, ok := parseFieldSet(c, geoJSON)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	satpen, radiusKm, err := h.service.GetNearbySatpen(lat, lng, radiusKm, limit, filter, fieldSet)
+	if err != nil {
+		if errors.Is(err, service.ErrRadius) {
+			utils.ValidationErrorResponse(c, "Invalid query parameters", gin.H{"radius_km": err.Error()})
+			return
+		}
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	respondSatpenList(c, satpen, fieldSet, geoJSON, gin.H{
+		"center":    gin.H{"latitude": lat, "longitude": lng},
+		"radius_km": radiusKm,
+	}, nil)
+}
+
+// UpdateLocationRequest is the body of PUT /admin/satpen/:id/location
+type UpdateLocationRequest struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// UpdateSatpenLocation handles PUT /api/v1/admin/satpen/:id/location
+// Both coordinates null clears the location
+func (h *SatpenHandler) UpdateSatpenLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ID", err.Error())
+		return
+	}
+
+	var req UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	satpen, err := h.service.UpdateSatpenLocation(c.Request.Context(), uint(id), req.Latitude, req.Longitude)
+	if err != nil {
+		switch {
+		case err.Error() == "satuan pendidikan not found":
+			utils.NotFoundResponse(c, "Satuan pendidikan not found")
+		case errors.Is(err, geo.ErrLatitude):
+			utils.ValidationErrorResponse(c, "Invalid location", gin.H{"latitude": err.Error()})
+		case errors.Is(err, geo.ErrLongitude):
+			utils.ValidationErrorResponse(c, "Invalid location", gin.H{"longitude": err.Error()})
+		case errors.Is(err, service.ErrPartialLocation):
+			utils.ValidationErrorResponse(c, "Invalid location", gin.H{"location": err.Error()})
+		default:
+			utils.InternalErrorResponse(c, err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Location updated successfully", satpen)
+}
+
+// queryFloat parses a float query parameter, recording a missing required
+// or malformed value in invalid
+func queryFloat(c *gin.Context, key string, required bool, invalid map[string]string) float64 {
+	value := c.Query(key)
+	if value == "" {
+		if required {
+			invalid[key] = "is required"
+		}
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		invalid[key] = "must be a number"
+		return 0
+	}
+	return f
+}
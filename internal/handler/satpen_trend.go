@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStatisticsTrend handles GET /api/v1/satpen/statistics/trend?interval=month|quarter|year
+// Registrations, approvals, expiries and active counts over time, with the
+// list filters
+func (h *SatpenHandler) GetStatisticsTrend(c *gin.Context) {
+	filter, invalid := parseSatpenFilter(c)
+
+	interval := c.DefaultQuery("interval", "month")
+	if !service.IsValidInterval(interval) {
+		invalid["interval"] = service.ErrInterval.Error()
+	}
+	from := queryDate(c, "from", invalid)
+	to := queryDate(c, "to", invalid)
+	if from != nil && to != nil && from.After(*to) {
+		invalid["to"] = "must not be before from"
+	}
+	if len(invalid) > 0 {
+		utils.ValidationErrorResponse(c, "Invalid query parameters", invalid)
+		return
+	}
+
+	trend, err := h.service.GetTrend(interval, from, to, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrTrendRange) || errors.Is(err, service.ErrTrendFrom) {
+			utils.ValidationErrorResponse(c, "Invalid query parameters", gin.H{"from": err.Error()})
+			return
+		}
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trend retrieved successfully", trend)
+}
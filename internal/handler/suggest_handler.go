@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SuggestHandler struct {
+	service service.SuggestService
+}
+
+func NewSuggestHandler(service service.SuggestService) *SuggestHandler {
+	return &SuggestHandler{service: service}
+}
+
+// Suggest handles GET /api/v1/satpen/suggest?q=&limit=
+// Typo-tolerant autocomplete over satpen names
+func (h *SuggestHandler) Suggest(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Missing query", "q is required")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	matches, err := h.service.Suggest(query, limit)
+	if err != nil {
+		if err.Error() == "suggest index not ready" {
+			utils.ErrorResponse(c, http.StatusServiceUnavailable, "Suggestions not available yet", err.Error())
+			return
+		}
+		utils.InternalErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Suggestions retrieved successfully", matches)
+}
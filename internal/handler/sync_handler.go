@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"satpen-api/internal/dapodik"
+	"satpen-api/internal/service"
+	"satpen-api/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SyncHandler struct {
+	service service.SyncService
+	log     *logrus.Logger
+}
+
+func NewSyncHandler(service service.SyncService, log *logrus.Logger) *SyncHandler {
+	return &SyncHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// SyncSatpen handles POST /api/v1/admin/satpen/:id/sync
+// Pulls PDPTK for one satpen from Dapodik immediately
+func (h *SyncHandler) SyncSatpen(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ID", err.Error())
+		return
+	}
+
+	result, err := h.service.SyncSatpen(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case err.Error() == "satuan pendidikan not found":
+			utils.NotFoundResponse(c, "Satuan pendidikan not found")
+		case err.Error() == "satuan pendidikan has no NPSN":
+			utils.ErrorResponse(c, http.StatusUnprocessableEntity, "Satuan pendidikan cannot be synced", err.Error())
+		case errors.Is(err, dapodik.ErrUnauthorized):
+			h.log.WithError(err).Error("Dapodik rejected credentials")
+			utils.ErrorResponse(c, http.StatusBadGateway, "Dapodik sync failed", err.Error())
+		default:
+			h.log.WithError(err).Error("Failed to sync satpen")
+			utils.InternalErrorResponse(c, err)
+		}
+		return
+	}
+
+	if !result.Success {
+		c.JSON(http.StatusBadGateway, utils.Response{
+			Success: false,
+			Message: "Dapodik sync failed",
+			Data:    result,
+			Error:   result.Error,
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Satuan pendidikan synced successfully", result)
+}
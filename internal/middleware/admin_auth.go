@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"satpen-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminActorKey is the gin context key holding the name of the authenticated admin key
+const AdminActorKey = "admin_actor"
+
+// AdminAuth protects admin endpoints with the API keys from cfg.Admin.
+// Requests must send one of the configured keys in the X-API-Key header.
+func AdminAuth(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.Admin.Enabled || len(cfg.Admin.APIKeys) == 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "Admin API disabled",
+				"error":   "No admin API keys configured",
+			})
+			c.Abort()
+			return
+		}
+
+		provided := c.GetHeader("X-API-Key")
+		for _, apiKey := range cfg.Admin.APIKeys {
+			if apiKey.Key != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey.Key)) == 1 {
+				c.Set(AdminActorKey, apiKey.Name)
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Unauthorized",
+			"error":   "Missing or invalid X-API-Key header",
+		})
+		c.Abort()
+	}
+}
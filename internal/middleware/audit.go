@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"satpen-api/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditContext puts the admin actor, client IP and request ID into the
+// request context, where services pick them up for the audit log. Use after
+// AdminAuth.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := audit.Info{
+			Actor:     c.GetString(AdminActorKey),
+			IP:        c.ClientIP(),
+			RequestID: c.GetString(RequestIDKey),
+		}
+		c.Request = c.Request.WithContext(audit.WithInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
@@ -17,6 +17,7 @@ func Logger(log *logrus.Logger) gin.HandlerFunc {
 		latency := endTime.Sub(startTime)
 
 		log.WithFields(logrus.Fields{
+			"request_id": c.GetString(RequestIDKey),
 			"status":     c.Writer.Status(),
 			"method":     c.Request.Method,
 			"path":       c.Request.URL.Path,
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog records one data-changing operation
+type AuditLog struct {
+	ID         uint            `json:"id" gorm:"column:id;primaryKey"`
+	Actor      string          `json:"actor" gorm:"column:actor;size:100;not null"` // admin API key name, or system
+	Action     string          `json:"action" gorm:"column:action;size:50;not null"`
+	EntityType string          `json:"entity_type" gorm:"column:entity_type;size:50;not null"`
+	EntityID   uint            `json:"entity_id" gorm:"column:entity_id;not null"`
+	Before     json.RawMessage `json:"before,omitempty" gorm:"column:before_data;type:json"`
+	After      json.RawMessage `json:"after,omitempty" gorm:"column:after_data;type:json"`
+	Changes    json.RawMessage `json:"changes,omitempty" gorm:"column:changes;type:json"` // {"field": {"from": ..., "to": ...}}
+	IP         string          `json:"ip,omitempty" gorm:"column:ip;size:45"`
+	RequestID  string          `json:"request_id,omitempty" gorm:"column:request_id;size:64"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"column:created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// Audit actions
+const (
+	AuditActionMerge          = "merge"
+	AuditActionUpdateLocation = "update_location"
+	AuditActionSync           = "sync"
+	AuditActionSyncFailed     = "sync_failed"
+)
+
+// AuditFilter holds the filters of GET /audit; zero values don't filter
+type AuditFilter struct {
+	EntityType string
+	EntityID   uint
+	Actor      string
+	Action     string
+	From       *time.Time // created_at on or after
+	To         *time.Time // created_at before the end of this day
+}
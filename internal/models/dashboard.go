@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// JenjangStatusCount is the satpen count of one jenjang and status, with the
+// totals of their latest PDPTK
+type JenjangStatusCount struct {
+	Jenjang string
+	Status  string
+	Count   int64
+	Siswa   int64
+	Guru    int64
+	Tendik  int64
+}
+
+// ExpiringSatpen is an active satpen whose registration expires soon
+type ExpiringSatpen struct {
+	ID          uint      `json:"id" gorm:"column:id_satpen"`
+	NPSN        string    `json:"npsn" gorm:"column:npsn"`
+	Nama        string    `json:"nama" gorm:"column:nama"`
+	Jenjang     string    `json:"jenjang" gorm:"column:jenjang"`
+	ActivedDate time.Time `json:"actived_date" gorm:"column:actived_date"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"column:expires_at"`
+}
+
+// PengurusCabangDashboard aggregates the satpen of one pengurus cabang
+type PengurusCabangDashboard struct {
+	PengurusCabang *PengurusCabang `json:"pengurus_cabang"`
+	// TotalSatpen counts every status; the siswa/guru/tendik totals only
+	// the satpen listed by default (ListedSatpenStatuses)
+	TotalSatpen            int64                       `json:"total_satpen"`
+	TotalSiswa             int64                       `json:"total_siswa"`
+	TotalGuru              int64                       `json:"total_guru"`
+	TotalTendik            int64                       `json:"total_tendik"`
+	ByJenjang              map[string]DashboardJenjang `json:"by_jenjang"`
+	ByStatus               map[string]int64            `json:"by_status"`
+	ByAkreditasi           map[string]int64            `json:"by_akreditasi"`
+	PendingRegistrations   PendingRegistrations        `json:"pending_registrations"`
+	ExpiringSoon           *ExpiringSoon               `json:"expiring_soon,omitempty"`
+	PTKPendingVerification int64                       `json:"ptk_pending_verification"`
+}
+
+type DashboardJenjang struct {
+	Count    int64            `json:"count"`
+	ByStatus map[string]int64 `json:"by_status"`
+	Siswa    int64            `json:"siswa"`
+	Guru     int64            `json:"guru"`
+	Tendik   int64            `json:"tendik"`
+}
+
+type PendingRegistrations struct {
+	Total    int64            `json:"total"`
+	ByStatus map[string]int64 `json:"by_status"`
+}
+
+type ExpiringSoon struct {
+	WithinDays int              `json:"within_days"`
+	Total      int              `json:"total"`
+	Satpen     []ExpiringSatpen `json:"satpen"`
+}
+
+// PendingSatpenStatuses are the statuses of registrations not yet approved
+var PendingSatpenStatuses = []string{"permohonan", "revisi", "proses dokumen"}
@@ -0,0 +1,34 @@
+package models
+
+// DataQuality is the outcome of the data quality rules for one satpen
+type DataQuality struct {
+	Score  int                `json:"score"` // 0-100, 100 when no rule fails
+	Issues []DataQualityIssue `json:"issues"`
+}
+
+// DataQualityIssue is one failed data quality rule
+type DataQualityIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // error or warning
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+}
+
+// DataQualityItem is one satpen with issues in GET /data-quality/issues
+type DataQualityItem struct {
+	ID        uint   `json:"id"`
+	NPSN      string `json:"npsn"`
+	Nama      string `json:"nama"`
+	Provinsi  string `json:"provinsi,omitempty"`
+	Kabupaten string `json:"kabupaten,omitempty"`
+	DataQuality
+}
+
+// DataQualitySummary counts the checked satpen and the failures per rule
+type DataQualitySummary struct {
+	Tapel        string           `json:"tapel,omitempty"` // tapel of the pdptk_missing rule
+	Checked      int              `json:"checked"`
+	WithIssues   int              `json:"with_issues"`
+	AverageScore float64          `json:"average_score"`
+	ByRule       map[string]int64 `json:"by_rule"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import "math"
+
+// Nama indikator rasio dan mutu satpen
+const (
+	IndikatorRasioSiswaGuru      = "rasio_siswa_guru"
+	IndikatorPersenGuruPerempuan = "persen_guru_perempuan"
+	IndikatorTendikPer100Siswa   = "tendik_per_100_siswa"
+)
+
+// Indikators lists the indicator names in response order
+var Indikators = []string{IndikatorRasioSiswaGuru, IndikatorPersenGuruPerempuan, IndikatorTendikPer100Siswa}
+
+// Indikator holds the ratio and quality indicators of one satpen, computed
+// from its latest PDPTK. An indicator is nil when its divisor is zero.
+type Indikator struct {
+	RasioSiswaGuru      *float64 `json:"rasio_siswa_guru"`
+	PersenGuruPerempuan *float64 `json:"persen_guru_perempuan"`
+	TendikPer100Siswa   *float64 `json:"tendik_per_100_siswa"`
+
+	// Indikator di luar ambang batas, mis. "rasio_siswa_guru_above"; diisi service
+	Flags []string `json:"flags,omitempty"`
+}
+
+// NewIndikator computes the indicators from siswa, guru, guru perempuan and
+// tendik counts
+func NewIndikator(siswa, guru, guruPR, tendik int) *Indikator {
+	return &Indikator{
+		RasioSiswaGuru:      ratio(float64(siswa), float64(guru)),
+		PersenGuruPerempuan: ratio(float64(guruPR)*100, float64(guru)),
+		TendikPer100Siswa:   ratio(float64(tendik)*100, float64(siswa)),
+	}
+}
+
+// Value returns the indicator called name, nil when it is not available
+func (i *Indikator) Value(name string) *float64 {
+	switch name {
+	case IndikatorRasioSiswaGuru:
+		return i.RasioSiswaGuru
+	case IndikatorPersenGuruPerempuan:
+		return i.PersenGuruPerempuan
+	case IndikatorTendikPer100Siswa:
+		return i.TendikPer100Siswa
+	}
+	return nil
+}
+
+// IsValidIndikator reports whether name is one of Indikators
+func IsValidIndikator(name string) bool {
+	for _, n := range Indikators {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func ratio(a, b float64) *float64 {
+	if b == 0 {
+		return nil
+	}
+	v := Round2(a / b)
+	return &v
+}
+
+// Round2 rounds v to two decimals
+func Round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// IndikatorRow is one satpen of the indicator ranking with the counts of its
+// latest PDPTK and the group (provinsi, kabupaten or jenjang) it falls in
+type IndikatorRow struct {
+	IDSatpen  uint
+	NPSN      string
+	Nama      string
+	GroupID   uint
+	GroupName string
+	JmlPD     int
+	JmlGuru   int
+	GuruPR    int
+	JmlTendik int
+}
+
+// IndikatorRanking is the response of GET /satpen/statistics/indicators
+type IndikatorRanking struct {
+	Indikator string             `json:"indikator"`
+	GroupBy   string             `json:"group_by"`
+	Min       *float64           `json:"min_threshold"`
+	Max       *float64           `json:"max_threshold"`
+	Overall   IndikatorStats     `json:"overall"`
+	Groups    []IndikatorGroup   `json:"groups"`
+	Flagged   []IndikatorFlagged `json:"flagged,omitempty"`
+}
+
+// IndikatorStats summarises the indicator over the satpen of a group.
+// Satpen without the indicator (no guru or siswa) are left out of Count.
+type IndikatorStats struct {
+	Count    int      `json:"count"`
+	Mean     *float64 `json:"mean"`
+	Min      *float64 `json:"min"`
+	P25      *float64 `json:"p25"`
+	Median   *float64 `json:"median"`
+	P75      *float64 `json:"p75"`
+	P90      *float64 `json:"p90"`
+	Max      *float64 `json:"max"`
+	BelowMin int      `json:"below_min"`
+	AboveMax int      `json:"above_max"`
+}
+
+// IndikatorGroup is one ranked provinsi, kabupaten or jenjang
+type IndikatorGroup struct {
+	Rank int    `json:"rank"`
+	ID   uint   `json:"id"`
+	Nama string `json:"nama"`
+	IndikatorStats
+}
+
+// IndikatorFlagged is a satpen whose indicator is outside the thresholds
+type IndikatorFlagged struct {
+	ID    uint    `json:"id"`
+	NPSN  string  `json:"npsn"`
+	Nama  string  `json:"nama"`
+	Group string  `json:"group"`
+	Value float64 `json:"value"`
+	Flag  string  `json:"flag"` // below or above
+}
@@ -14,3 +14,10 @@ type KategoriSatpen struct {
 func (KategoriSatpen) TableName() string {
 	return "kategori_satpen"
 }
+
+// KategoriSatpenWithCount is a kategori with the number of listed satpen
+// (ListedSatpenStatuses) in it
+type KategoriSatpenWithCount struct {
+	KategoriSatpen
+	JumlahSatpen int64 `json:"jumlah_satpen" gorm:"column:jumlah_satpen;->;-:migration"`
+}
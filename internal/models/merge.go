@@ -0,0 +1,18 @@
+package models
+
+// MergeResult describes what a satpen merge moved (or would move, for a
+// preview) from the losing satpen to the surviving one
+type MergeResult struct {
+	SurvivorID uint `json:"survivor_id"`
+	LoserID    uint `json:"loser_id"`
+	Preview    bool `json:"preview"`
+
+	// PDPTK per tapel: moved to the survivor, or dropped because the
+	// survivor already has a PDPTK for that tapel
+	PDPTKMoved    []string `json:"pdptk_moved"`
+	PDPTKDropped  []string `json:"pdptk_dropped"`
+	PTKMoved      int64    `json:"ptk_moved"`
+	TimelineMoved int64    `json:"timeline_reg_moved"`
+
+	AuditID uint `json:"audit_id,omitempty"`
+}
@@ -22,3 +22,10 @@ type PDPTK struct {
 func (PDPTK) TableName() string {
 	return "pdptk"
 }
+
+// Nilai status_sinkron untuk sinkronisasi data PDPTK dengan Dapodik
+const (
+	StatusSinkronBelum    = 0
+	StatusSinkronBerhasil = 1
+	StatusSinkronGagal    = 2
+)
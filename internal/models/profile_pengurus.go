@@ -0,0 +1,97 @@
+package models
+
+import (
+	"satpen-api/internal/geo"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProfilPengurus holds the columns shared by profile_pengurus_cabang and
+// profile_pengurus_wilayah
+type ProfilPengurus struct {
+	Alamat         string `json:"alamat,omitempty" gorm:"column:alamat;size:255"`
+	Kelurahan      string `json:"kelurahan,omitempty" gorm:"column:kelurahan;size:100"`
+	Kecamatan      string `json:"kecamatan,omitempty" gorm:"column:kecamatan;size:100"`
+	Kabupaten      string `json:"kabupaten,omitempty" gorm:"column:kabupaten;size:100"`
+	Lintang        string `json:"-" gorm:"column:lintang;size:50"`
+	Bujur          string `json:"-" gorm:"column:bujur;size:50"`
+	Website        string `json:"website,omitempty" gorm:"column:website;size:50"`
+	Ketua          string `json:"ketua,omitempty" gorm:"column:ketua;size:100"`
+	TelpKetua      string `json:"telp_ketua,omitempty" gorm:"column:telp_ketua;size:15"`
+	WakilKetua     string `json:"wakil_ketua,omitempty" gorm:"column:wakil_ketua;size:100"`
+	TelpWakil      string `json:"telp_wakil,omitempty" gorm:"column:telp_wakil;size:15"`
+	Bendahara      string `json:"bendahara,omitempty" gorm:"column:bendahara;size:100"`
+	TelpBendahara  string `json:"telp_bendahara,omitempty" gorm:"column:telp_bendahara;size:15"`
+	Sekretaris     string `json:"sekretaris,omitempty" gorm:"column:sekretaris;size:100"`
+	TelpSekretaris string `json:"telp_sekretaris,omitempty" gorm:"column:telp_sekretaris;size:15"`
+	MasaKhidmat    string `json:"masa_khidmat,omitempty" gorm:"column:masa_khidmat;size:50"`
+
+	// Lintang/bujur are free text in these tables; parsed when valid
+	Coordinates *Coordinates `json:"coordinates,omitempty" gorm:"-"`
+}
+
+// parseCoordinates fills Coordinates from lintang/bujur, accepting a decimal
+// comma ("-7,2575") and ignoring values that are not valid coordinates
+func (p *ProfilPengurus) parseCoordinates() {
+	lat, errLat := strconv.ParseFloat(strings.Replace(strings.TrimSpace(p.Lintang), ",", ".", 1), 64)
+	lng, errLng := strconv.ParseFloat(strings.Replace(strings.TrimSpace(p.Bujur), ",", ".", 1), 64)
+	if errLat != nil || errLng != nil || geo.Validate(lat, lng) != nil {
+		p.Coordinates = nil
+		return
+	}
+	p.Coordinates = &Coordinates{Latitude: lat, Longitude: lng}
+}
+
+type ProfilePengurusCabang struct {
+	ID             int  `json:"-" gorm:"column:id;primaryKey"`
+	IDPC           uint `json:"-" gorm:"column:id_pc;not null"`
+	ProfilPengurus `gorm:"embedded"`
+	CreatedAt      *time.Time `json:"-" gorm:"column:created_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (ProfilePengurusCabang) TableName() string {
+	return "profile_pengurus_cabang"
+}
+
+// AfterFind hook to parse the coordinates
+func (p *ProfilePengurusCabang) AfterFind(tx *gorm.DB) error {
+	p.parseCoordinates()
+	return nil
+}
+
+type ProfilePengurusWilayah struct {
+	ID             int  `json:"-" gorm:"column:id;primaryKey"`
+	IDPW           uint `json:"-" gorm:"column:id_pw;not null"` // id_prov
+	ProfilPengurus `gorm:"embedded"`
+	CreatedAt      *time.Time `json:"-" gorm:"column:created_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (ProfilePengurusWilayah) TableName() string {
+	return "profile_pengurus_wilayah"
+}
+
+// AfterFind hook to parse the coordinates
+func (p *ProfilePengurusWilayah) AfterFind(tx *gorm.DB) error {
+	p.parseCoordinates()
+	return nil
+}
+
+// PengurusWilayah is the Pengurus Wilayah (PW) of a provinsi. There is no
+// table of its own: it is the provinsi row with its profile.
+type PengurusWilayah struct {
+	IDProv         uint                    `json:"id" gorm:"column:id_prov;primaryKey"`
+	KodeProv       string                  `json:"kode" gorm:"column:kode_prov"`
+	NmProv         string                  `json:"provinsi" gorm:"column:nm_prov"`
+	Profile        *ProfilePengurusWilayah `json:"profile,omitempty" gorm:"foreignKey:IDPW;references:IDProv"`
+	JumlahCabang   int64                   `json:"jumlah_cabang" gorm:"column:jumlah_cabang;->;-:migration"`
+	PengurusCabang []PengurusCabang        `json:"pengurus_cabang,omitempty" gorm:"foreignKey:IDProv;references:IDProv"`
+}
+
+func (PengurusWilayah) TableName() string {
+	return "provinsi"
+}
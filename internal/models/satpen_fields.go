@@ -0,0 +1,97 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SatpenField describes one JSON field of Satpen for sparse fieldsets
+type SatpenField struct {
+	JSON     string
+	Column   string   // database column, empty for relations and computed fields
+	Relation string   // Preload name when the field is a relation
+	Columns  []string // columns needed to produce the field (FKs for relations)
+	Preloads []string // relations needed to produce a computed field
+}
+
+// computedSatpenFields lists what the AfterFind hook reads for each virtual field
+var computedSatpenFields = map[string]SatpenField{
+	"jumlah_siswa":  {Preloads: []string{"PDPTK"}},
+	"jumlah_guru":   {Preloads: []string{"PDPTK"}},
+	"jumlah_rombel": {},
+	"akreditasi":    {Columns: []string{"id_kategori"}, Preloads: []string{"Kategori"}},
+	"is_verified":   {Columns: []string{"status"}},
+	"verified_at":   {Columns: []string{"status", "actived_date"}},
+	"coordinates":   {Columns: []string{"lintang", "bujur"}},
+	"indikator":     {Preloads: []string{"PDPTK"}},
+	"data_quality":  {},
+	"relevance":     {},
+	"distance_km":   {},
+}
+
+var (
+	satpenFieldsOnce sync.Once
+	satpenFields     map[string]SatpenField
+)
+
+// SatpenFields returns the allowlist of JSON field names of Satpen, derived
+// from its json and gorm tags
+func SatpenFields() map[string]SatpenField {
+	satpenFieldsOnce.Do(func() {
+		satpenFields = buildSatpenFields()
+	})
+	return satpenFields
+}
+
+func buildSatpenFields() map[string]SatpenField {
+	t := reflect.TypeOf(Satpen{})
+	columns := make(map[string]string) // Go field name -> column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if column := gormTagValue(f.Tag.Get("gorm"), "column"); column != "" {
+			columns[f.Name] = column
+		}
+	}
+
+	fields := make(map[string]SatpenField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		field := SatpenField{JSON: name}
+		gormTag := f.Tag.Get("gorm")
+		switch {
+		case gormTagValue(gormTag, "foreignKey") != "":
+			field.Relation = f.Name
+			// Preloading needs the key column the relation is joined on
+			if fk, ok := columns[gormTagValue(gormTag, "foreignKey")]; ok {
+				field.Columns = []string{fk}
+			}
+		case gormTag == "-":
+			computed := computedSatpenFields[name]
+			field.Columns = computed.Columns
+			field.Preloads = computed.Preloads
+		case strings.Contains(gormTag, "->"):
+			// read-only, selected on demand (relevance)
+		default:
+			field.Column = columns[f.Name]
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// gormTagValue returns the value of key in a gorm struct tag ("column:npsn;size:45")
+func gormTagValue(tag, key string) string {
+	for _, part := range strings.Split(tag, ";") {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), key) {
+			return strings.TrimSpace(kv[1])
+		}
+	}
+	return ""
+}
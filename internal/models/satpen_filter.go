@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// SatpenFilter holds the filters of satpen listing, export and statistics.
+// Slice fields match any of their values; zero values don't filter.
+type SatpenFilter struct {
+	Jenjang        []string // nm_jenjang
+	Provinsi       []string // id_prov or part of nm_prov
+	Kabupaten      []string // id_kab or part of nama_kab
+	PengurusCabang []string // id_pc or part of nama_pc
+	Kecamatan      []string
+	Kelurahan      []string
+	Yayasan        string // part of the yayasan name
+	Akreditasi     []string
+	Status         []string // aktif, non-aktif or a raw status; empty lists setujui, expired and perpanjangan
+	Verified       *bool
+	HasLocation    *bool // satpen with (true) or without (false) coordinates
+
+	Search     string
+	SearchMode string
+	// SearchMinTokenSize is filled from config by the service
+	SearchMinTokenSize int
+
+	TahunBerdiriMin   *int
+	TahunBerdiriMax   *int
+	JumlahSiswaMin    *int
+	JumlahSiswaMax    *int
+	TglRegistrasiFrom *time.Time
+	TglRegistrasiTo   *time.Time
+
+	// Exact IDs, set by services rather than from query parameters
+	ProvinsiID       uint
+	KabupatenID      uint
+	PengurusCabangID uint
+}
+
+// SatpenStatuses are the raw values of satpen.status
+var SatpenStatuses = []string{"permohonan", "revisi", "proses dokumen", "setujui", "expired", "perpanjangan"}
+
+// ListedSatpenStatuses are the statuses listed when no status filter is given
+var ListedSatpenStatuses = []string{"setujui", "expired", "perpanjangan"}
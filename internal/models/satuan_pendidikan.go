@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Satpen struct {
@@ -38,6 +40,10 @@ type Satpen struct {
 	CreatedAt      time.Time          `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt      time.Time          `json:"updated_at" gorm:"column:updated_at"`
 
+	// Lokasi (derajat desimal, WGS84), kosong bila belum dipetakan
+	Lintang        *float64           `json:"-" gorm:"column:lintang;type:decimal(10,7)"`
+	Bujur          *float64           `json:"-" gorm:"column:bujur;type:decimal(11,7)"`
+
 	// Data PDPTK dari relasi (untuk jumlah siswa dan guru)
 	PDPTK          *PDPTK             `json:"pdptk,omitempty" gorm:"foreignKey:IDSatpen;references:IDSatpen"`
 
@@ -48,6 +54,23 @@ type Satpen struct {
 	Akreditasi     string             `json:"akreditasi,omitempty" gorm:"-"`
 	IsVerified     bool               `json:"is_verified" gorm:"-"`
 	VerifiedAt     *time.Time         `json:"verified_at,omitempty" gorm:"-"`
+	Coordinates    *Coordinates       `json:"coordinates,omitempty" gorm:"-"`
+	Indikator      *Indikator         `json:"indikator,omitempty" gorm:"-"`
+
+	// Hasil pemeriksaan kualitas data, hanya terisi di endpoint detail
+	DataQuality    *DataQuality       `json:"data_quality,omitempty" gorm:"-"`
+
+	// Skor relevansi FULLTEXT, hanya terisi saat search_mode natural/boolean
+	Relevance      *float64           `json:"relevance,omitempty" gorm:"column:relevance;->;-:migration"`
+
+	// Jarak dari titik pencarian, hanya terisi di /satpen/nearby
+	Distance       *float64           `json:"distance_km,omitempty" gorm:"column:distance_km;->;-:migration"`
+}
+
+// Coordinates is the location of a satpen in decimal degrees
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
 }
 
 func (Satpen) TableName() string {
@@ -55,11 +78,12 @@ func (Satpen) TableName() string {
 }
 
 // AfterFind hook to populate jumlah siswa and guru from PDPTK
-func (s *Satpen) AfterFind() error {
+func (s *Satpen) AfterFind(tx *gorm.DB) error {
 	if s.PDPTK != nil {
 		s.JumlahSiswa = uint(s.PDPTK.JmlPD)
 		s.JumlahGuru = uint(s.PDPTK.JmlGuru)
 		s.JumlahRombel = 0 // Not available in current schema
+		s.Indikator = NewIndikator(s.PDPTK.JmlPD, s.PDPTK.JmlGuru, s.PDPTK.GuruPR, s.PDPTK.JmlTendik)
 	}
 
 	// Map kategori to akreditasi
@@ -73,5 +97,9 @@ func (s *Satpen) AfterFind() error {
 		s.VerifiedAt = s.ActivedDate
 	}
 
+	if s.Lintang != nil && s.Bujur != nil {
+		s.Coordinates = &Coordinates{Latitude: *s.Lintang, Longitude: *s.Bujur}
+	}
+
 	return nil
 }
@@ -1,13 +1,19 @@
 package models
 
+// SatpenStatistics summarises the satpen matching a filter; every figure
+// applies the same filter
 type SatpenStatistics struct {
-	TotalSatpen   int64                     `json:"total_satpen"`
-	TotalProvinsi int64                     `json:"total_provinsi"`
-	TotalSiswa    int64                     `json:"total_siswa"`
-	TotalGuru     int64                     `json:"total_guru"`
-	ByJenjang     map[string]JenjangStats   `json:"by_jenjang"`
-	ByAkreditasi  map[string]int64          `json:"by_akreditasi"`
-	TopProvinsi   []ProvinsiStats           `json:"top_provinsi"`
+	TotalSatpen         int64                     `json:"total_satpen"`
+	TotalProvinsi       int64                     `json:"total_provinsi"` // provinsi with matching satpen
+	TotalKabupaten      int64                     `json:"total_kabupaten"`
+	TotalPengurusCabang int64                     `json:"total_pengurus_cabang"`
+	TotalSiswa          int64                     `json:"total_siswa"`
+	TotalGuru           int64                     `json:"total_guru"`
+	ByJenjang           map[string]JenjangStats   `json:"by_jenjang"`
+	ByAkreditasi        map[string]int64          `json:"by_akreditasi"`
+	TopProvinsi         []ProvinsiStats           `json:"top_provinsi"`
+	TopKabupaten        []KabupatenStats          `json:"top_kabupaten"`
+	TopPengurusCabang   []PengurusCabangStats     `json:"top_pengurus_cabang"`
 }
 
 type JenjangStats struct {
@@ -17,18 +23,125 @@ type JenjangStats struct {
 }
 
 type ProvinsiStats struct {
+	ID       uint   `json:"id"`
 	Provinsi string `json:"provinsi"`
 	Count    int64  `json:"count"`
+	Siswa    int64  `json:"siswa"`
+	Guru     int64  `json:"guru"`
+}
+
+type KabupatenStats struct {
+	ID        uint   `json:"id"`
+	Kabupaten string `json:"kabupaten"`
+	Count     int64  `json:"count"`
+	Siswa     int64  `json:"siswa"`
+	Guru      int64  `json:"guru"`
+}
+
+type PengurusCabangStats struct {
+	ID             uint   `json:"id"`
+	PengurusCabang string `json:"pengurus_cabang"`
+	Count          int64  `json:"count"`
+	Siswa          int64  `json:"siswa"`
+	Guru           int64  `json:"guru"`
 }
 
 type AkreditasiCount struct {
 	Akreditasi string
+	Konotasi   string
 	Count      int64
 }
 
+// Label is the by_akreditasi key: the kategori with its konotasi, such as
+// "A (Unggul)", or the kategori alone when it has no konotasi
+func (a AkreditasiCount) Label() string {
+	if a.Konotasi == "" {
+		return a.Akreditasi
+	}
+	return a.Akreditasi + " (" + a.Konotasi + ")"
+}
+
 type JenjangCount struct {
 	Jenjang string
 	Count   int64
 	Siswa   int64
 	Guru    int64
 }
+
+// RegionCount is the satpen count of one provinsi or kabupaten. Latitude and
+// Longitude are the mean location of its located satpen, nil when none is.
+type RegionCount struct {
+	ID        uint
+	Kode      string
+	Map       string
+	IDProv    uint
+	Nama      string
+	Count     int64
+	Located   int64
+	Siswa     int64
+	Guru      int64
+	Latitude  *float64
+	Longitude *float64
+}
+
+// PlaceCount is the satpen count of one raw kecamatan (and kelurahan)
+// spelling as stored on satpen
+type PlaceCount struct {
+	Kecamatan string
+	Kelurahan string
+	Count     int64
+	Siswa     int64
+	Guru      int64
+}
+
+// DrillDown is one level of the provinsi → kabupaten → kecamatan →
+// kelurahan drill-down: the children of the selected node
+type DrillDown struct {
+	Level       string          `json:"level"`
+	TotalSatpen int64           `json:"total_satpen"`
+	TotalSiswa  int64           `json:"total_siswa"`
+	TotalGuru   int64           `json:"total_guru"`
+	Nodes       []DrillDownNode `json:"nodes"`
+}
+
+// DrillDownNode is a provinsi or kabupaten (by ID) or a kecamatan or
+// kelurahan (by normalised name key, merging its spelling variants)
+type DrillDownNode struct {
+	ID       uint     `json:"id,omitempty"`
+	Key      string   `json:"key,omitempty"`
+	Nama     string   `json:"nama"`
+	Variants []string `json:"variants,omitempty"`
+	Count    int64    `json:"count"`
+	Siswa    int64    `json:"siswa"`
+	Guru     int64    `json:"guru"`
+}
+
+// PeriodCount is the number of events in one period: a month (Part 1-12),
+// quarter (Part 1-4) or year (Part 1)
+type PeriodCount struct {
+	Year  int
+	Part  int
+	Count int64
+}
+
+// Trend is the registration time series of GET /satpen/statistics/trend
+type Trend struct {
+	Interval      string       `json:"interval"`
+	From          string       `json:"from"` // first day of the first period
+	To            string       `json:"to"`   // last day of the last period
+	Registrations int64        `json:"registrations"`
+	Approvals     int64        `json:"approvals"`
+	Expiries      int64        `json:"expiries"`
+	Points        []TrendPoint `json:"points"`
+}
+
+// TrendPoint holds the events of one period. Active is the number of
+// registrations in force at the end of the period.
+type TrendPoint struct {
+	Period        string `json:"period"` // 2024-03, 2024-Q1 or 2024
+	Start         string `json:"start"`
+	Registrations int64  `json:"registrations"`
+	Approvals     int64  `json:"approvals"`
+	Expiries      int64  `json:"expiries"`
+	Active        int64  `json:"active"`
+}
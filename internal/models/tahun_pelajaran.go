@@ -0,0 +1,11 @@
+package models
+
+type TahunPelajaran struct {
+	ID        int    `json:"id" gorm:"column:id;primaryKey"`
+	TapelDapo string `json:"tapel_dapo" gorm:"column:tapel_dapo;size:50;uniqueIndex"`
+	NamaTapel string `json:"nama" gorm:"column:nama_tapel;size:50"`
+}
+
+func (TahunPelajaran) TableName() string {
+	return "tahun_pelajaran"
+}
@@ -0,0 +1,168 @@
+// Package quality implements the rule-based data quality checks of satpen
+// records and their PDPTK.
+package quality
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"satpen-api/internal/models"
+	"strings"
+	"time"
+)
+
+// Severities of a rule
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// weights of a failed rule in the score, per severity
+var weights = map[string]int{
+	SeverityError:   3,
+	SeverityWarning: 1,
+}
+
+// Patterns of the text rules. The repository runs the same patterns with
+// MySQL REGEXP, so the issue list and the detail view agree.
+const (
+	BlankPattern = `^[[:space:]]*$`
+	NPSNPattern  = `^[0-9]{8}$`
+	// 7-15 digits, optionally with a leading +, separated by spaces, dots,
+	// dashes or a parenthesised area code
+	PhonePattern = `^[[:space:]]*[ ().-]*[+]?([ ().-]*[0-9]){7,15}[ ().-]*[[:space:]]*$`
+	// A bare address with a dotted domain
+	EmailPattern = `^[[:space:]]*[A-Za-z0-9._%+'-]+@[A-Za-z0-9-]+([.][A-Za-z0-9-]+)+[[:space:]]*$`
+)
+
+var (
+	blankRE = regexp.MustCompile(BlankPattern)
+	npsnRE  = regexp.MustCompile(NPSNPattern)
+	phoneRE = regexp.MustCompile(PhonePattern)
+	emailRE = regexp.MustCompile(EmailPattern)
+)
+
+// Record is the input of the rules: one satpen with its Provinsi, Kabupaten
+// and PengurusCabang loaded, and its PDPTK of Tapel (nil when missing)
+type Record struct {
+	Satpen models.Satpen
+	PDPTK  *models.PDPTK
+	Tapel  string // current tahun pelajaran, empty skips pdptk_missing
+}
+
+// Rule is one data quality check. Check returns a message when the record
+// fails the rule.
+type Rule struct {
+	ID          string `json:"rule"`
+	Field       string `json:"field"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+
+	Check func(r *Record, now time.Time) (string, bool) `json:"-"`
+}
+
+// Rules lists every rule in check order
+var Rules = []Rule{
+	{
+		ID: "kepsek_empty", Field: "kepala_sekolah", Severity: SeverityWarning,
+		Description: "Nama kepala sekolah kosong",
+		Check: func(r *Record, _ time.Time) (string, bool) {
+			return "kepala sekolah is empty", blankRE.MatchString(r.Satpen.Kepsek)
+		},
+	},
+	{
+		ID: "telpon_invalid", Field: "phone", Severity: SeverityWarning,
+		Description: "Nomor telepon terisi tetapi bukan 7-15 digit",
+		Check: func(r *Record, _ time.Time) (string, bool) {
+			phone := r.Satpen.Telpon
+			return fmt.Sprintf("phone %q is not a valid number", strings.TrimSpace(phone)), !blankRE.MatchString(phone) && !phoneRE.MatchString(phone)
+		},
+	},
+	{
+		ID: "email_invalid", Field: "email", Severity: SeverityWarning,
+		Description: "Email terisi tetapi formatnya salah",
+		Check: func(r *Record, _ time.Time) (string, bool) {
+			email := r.Satpen.Email
+			return fmt.Sprintf("email %q is not a valid address", strings.TrimSpace(email)), !blankRE.MatchString(email) && !emailRE.MatchString(email)
+		},
+	},
+	{
+		ID: "npsn_invalid", Field: "npsn", Severity: SeverityError,
+		Description: "NPSN bukan 8 digit",
+		Check: func(r *Record, _ time.Time) (string, bool) {
+			return fmt.Sprintf("npsn %q must be 8 digits", r.Satpen.NPSN), !npsnRE.MatchString(r.Satpen.NPSN)
+		},
+	},
+	{
+		ID: "thn_berdiri_future", Field: "tahun_berdiri", Severity: SeverityError,
+		Description: "Tahun berdiri setelah tahun ini",
+		Check: func(r *Record, now time.Time) (string, bool) {
+			return fmt.Sprintf("tahun berdiri %d is in the future", r.Satpen.ThnBerdiri), r.Satpen.ThnBerdiri > now.Year()
+		},
+	},
+	{
+		ID: "pdptk_missing", Field: "pdptk", Severity: SeverityWarning,
+		Description: "Belum ada PDPTK untuk tahun pelajaran berjalan",
+		Check: func(r *Record, _ time.Time) (string, bool) {
+			return fmt.Sprintf("no pdptk for tapel %s", r.Tapel), r.Tapel != "" && r.PDPTK == nil
+		},
+	},
+	{
+		ID: "pengurus_cabang_provinsi", Field: "pengurus_cabang", Severity: SeverityError,
+		Description: "Pengurus cabang berada di provinsi lain dari satpen",
+		Check: func(r *Record, _ time.Time) (string, bool) {
+			pc := r.Satpen.PengurusCabang
+			if pc == nil {
+				return "", false
+			}
+			return fmt.Sprintf("pengurus cabang %s belongs to provinsi %d, satpen to %d", pc.NamaPC, pc.IDProv, r.Satpen.IDProv), pc.IDProv != r.Satpen.IDProv
+		},
+	},
+}
+
+// IsValidRule reports whether id names one of Rules
+func IsValidRule(id string) bool {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Weight is the share of the rule in the score: 3 for an error, 1 for a
+// warning
+func (rule Rule) Weight() int {
+	return weights[rule.Severity]
+}
+
+// TotalWeight is the sum of the weights of all Rules
+func TotalWeight() int {
+	total := 0
+	for _, rule := range Rules {
+		total += rule.Weight()
+	}
+	return total
+}
+
+// Check runs every rule against r. The score is the share of the rule
+// weights (3 per error, 1 per warning) that passed, 0-100.
+func Check(r *Record, now time.Time) models.DataQuality {
+	result := models.DataQuality{Issues: []models.DataQualityIssue{}}
+
+	total, failed := TotalWeight(), 0
+	for _, rule := range Rules {
+		if message, fails := rule.Check(r, now); fails {
+			failed += rule.Weight()
+			result.Issues = append(result.Issues, models.DataQualityIssue{
+				Rule:     rule.ID,
+				Severity: rule.Severity,
+				Field:    rule.Field,
+				Message:  message,
+			})
+		}
+	}
+
+	result.Score = int(math.Round(100 * float64(total-failed) / float64(total)))
+	return result
+}
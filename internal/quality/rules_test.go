@@ -0,0 +1,121 @@
+package quality
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"satpen-api/internal/models"
+)
+
+var now = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// validRecord passes every rule
+func validRecord() *Record {
+	return &Record{
+		Satpen: models.Satpen{
+			IDSatpen: 1, IDProv: 35, NPSN: "20507001", Kepsek: "Ahmad Fauzi",
+			Telpon: "(0341) 458-123", Email: "mi@example.com", ThnBerdiri: 1985,
+			PengurusCabang: &models.PengurusCabang{IDPC: 1, IDProv: 35, NamaPC: "PCNU Kabupaten Malang"},
+		},
+		PDPTK: &models.PDPTK{Tapel: "20241"},
+		Tapel: "20241",
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(r *Record)
+		want   []string // failed rules
+	}{
+		{"valid", func(r *Record) {}, nil},
+
+		{"kepsek empty", func(r *Record) { r.Satpen.Kepsek = "" }, []string{"kepsek_empty"}},
+		{"kepsek blank", func(r *Record) { r.Satpen.Kepsek = " \t" }, []string{"kepsek_empty"}},
+
+		{"npsn 7 digits", func(r *Record) { r.Satpen.NPSN = "2050700" }, []string{"npsn_invalid"}},
+		{"npsn 9 digits", func(r *Record) { r.Satpen.NPSN = "205070011" }, []string{"npsn_invalid"}},
+		{"npsn letters", func(r *Record) { r.Satpen.NPSN = "2050700A" }, []string{"npsn_invalid"}},
+		{"npsn empty", func(r *Record) { r.Satpen.NPSN = "" }, []string{"npsn_invalid"}},
+
+		{"phone empty", func(r *Record) { r.Satpen.Telpon = "" }, nil},
+		{"phone blank", func(r *Record) { r.Satpen.Telpon = "  " }, nil},
+		{"phone international", func(r *Record) { r.Satpen.Telpon = "+62 812-3456-7890" }, nil},
+		{"phone dotted", func(r *Record) { r.Satpen.Telpon = " 0341.458.123 " }, nil},
+		{"phone 7 digits", func(r *Record) { r.Satpen.Telpon = "4581234" }, nil},
+		{"phone 6 digits", func(r *Record) { r.Satpen.Telpon = "458123" }, []string{"telpon_invalid"}},
+		{"phone 16 digits", func(r *Record) { r.Satpen.Telpon = "0812345678901234" }, []string{"telpon_invalid"}},
+		{"phone letters", func(r *Record) { r.Satpen.Telpon = "0341 ext 12" }, []string{"telpon_invalid"}},
+		{"phone plus inside", func(r *Record) { r.Satpen.Telpon = "0341+458123" }, []string{"telpon_invalid"}},
+
+		{"email empty", func(r *Record) { r.Satpen.Email = "" }, nil},
+		{"email padded", func(r *Record) { r.Satpen.Email = " mi.nu01@maarif-nu.or.id " }, nil},
+		{"email without domain dot", func(r *Record) { r.Satpen.Email = "admin@localhost" }, []string{"email_invalid"}},
+		{"email trailing dot", func(r *Record) { r.Satpen.Email = "mi@example." }, []string{"email_invalid"}},
+		{"email without at", func(r *Record) { r.Satpen.Email = "mi.example.com" }, []string{"email_invalid"}},
+		{"email with name", func(r *Record) { r.Satpen.Email = "MI NU <mi@example.com>" }, []string{"email_invalid"}},
+		{"email two addresses", func(r *Record) { r.Satpen.Email = "a@example.com, b@example.com" }, []string{"email_invalid"}},
+
+		{"thn_berdiri this year", func(r *Record) { r.Satpen.ThnBerdiri = 2024 }, nil},
+		{"thn_berdiri next year", func(r *Record) { r.Satpen.ThnBerdiri = 2025 }, []string{"thn_berdiri_future"}},
+		{"thn_berdiri unknown", func(r *Record) { r.Satpen.ThnBerdiri = 0 }, nil},
+
+		{"pdptk missing", func(r *Record) { r.PDPTK = nil }, []string{"pdptk_missing"}},
+		{"pdptk missing without tapel", func(r *Record) { r.PDPTK, r.Tapel = nil, "" }, nil},
+
+		{"pengurus cabang in another provinsi", func(r *Record) { r.Satpen.PengurusCabang.IDProv = 33 }, []string{"pengurus_cabang_provinsi"}},
+		{"no pengurus cabang", func(r *Record) { r.Satpen.PengurusCabang = nil }, nil},
+
+		{"several", func(r *Record) { r.Satpen.NPSN, r.Satpen.Kepsek, r.PDPTK = "123", "", nil }, []string{"kepsek_empty", "npsn_invalid", "pdptk_missing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validRecord()
+			tt.change(r)
+
+			var got []string
+			for _, issue := range Check(r, now).Issues {
+				got = append(got, issue.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("failed rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckScore(t *testing.T) {
+	if TotalWeight() != 13 {
+		t.Fatalf("TotalWeight = %d, want 3 errors and 4 warnings = 13", TotalWeight())
+	}
+
+	tests := []struct {
+		name      string
+		change    func(r *Record)
+		wantScore int
+	}{
+		{"clean", func(r *Record) {}, 100},
+		{"one warning", func(r *Record) { r.Satpen.Kepsek = "" }, 92},                   // 12/13
+		{"one error", func(r *Record) { r.Satpen.ThnBerdiri = 2030 }, 77},               // 10/13
+		{"error and warning", func(r *Record) { r.Satpen.NPSN, r.PDPTK = "", nil }, 69}, // 9/13
+		{"everything", func(r *Record) {
+			r.Satpen = models.Satpen{IDProv: 1, Telpon: "x", Email: "x", ThnBerdiri: 2030, PengurusCabang: &models.PengurusCabang{IDProv: 2}}
+			r.PDPTK = nil
+		}, 0},
+	}
+	for _, tt := range tests {
+		r := validRecord()
+		tt.change(r)
+		result := Check(r, now)
+		if result.Score != tt.wantScore {
+			t.Errorf("%s: score = %d, want %d (%+v)", tt.name, result.Score, tt.wantScore, result.Issues)
+		}
+	}
+
+	issue := Check(&Record{Satpen: models.Satpen{NPSN: "20507001", Kepsek: "A", Email: " x@y "}}, now).Issues[0]
+	want := models.DataQualityIssue{Rule: "email_invalid", Severity: SeverityWarning, Field: "email", Message: `email "x@y" is not a valid address`}
+	if issue != want {
+		t.Errorf("issue = %+v, want %+v", issue, want)
+	}
+}
@@ -0,0 +1,46 @@
+// Package region normalises the free-text kecamatan and kelurahan names
+// stored on satpen so spelling variants can be aggregated together.
+package region
+
+import (
+	"strings"
+	"unicode"
+
+	"satpen-api/internal/textnorm"
+)
+
+// prefixes are administrative words written in front of some names; they
+// are dropped so "Kec. Sukamaju" and "SUKAMAJU" share a key
+var prefixes = map[string]bool{
+	"kecamatan": true,
+	"kec":       true,
+	"kelurahan": true,
+	"kel":       true,
+	"desa":      true,
+	"ds":        true,
+	"distrik":   true,
+}
+
+// Normalize returns the aggregation key of a kecamatan or kelurahan name: the
+// words of textnorm.Words without leading administrative prefixes.
+//
+//	"Kec.  Suka-Maju" -> "suka maju"
+func Normalize(name string) string {
+	words := textnorm.Words(name)
+	// Keep the last word even if it is a prefix ("Desa" alone stays "desa")
+	for len(words) > 1 && prefixes[words[0]] {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Title returns a display name for a normalised key: "suka maju" -> "Suka Maju"
+func Title(key string) string {
+	words := strings.Fields(key)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,45 @@
+package region
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Sukamaju", "sukamaju"},
+		{"Kec. Sukamaju", "sukamaju"},
+		{"KECAMATAN SUKAMAJU", "sukamaju"},
+		{"  kec.   sukamaju ", "sukamaju"},
+		{"Kec.  Suka-Maju", "suka maju"},
+		{"Kel. Desa Sukamaju", "sukamaju"},
+		{"Ds Sukamaju", "sukamaju"},
+		{"Distrik Abepura", "abepura"},
+		{"Sukamaju Kecamatan", "sukamaju kecamatan"},
+		{"Desa", "desa"},
+		{"", ""},
+		{" - ", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"suka maju", "Suka Maju"},
+		{"sukamaju", "Sukamaju"},
+		{"ümbul", "Ümbul"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Title(tt.in); got != tt.want {
+			t.Errorf("Title(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
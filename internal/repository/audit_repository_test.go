@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"satpen-api/internal/models"
+)
+
+// newTestAuditRepository returns an auditRepository holding six entries,
+// ids 1-6, from 2024-09-01 to 2024-09-03. 2 and 6 share their created_at;
+// 4 is exactly midnight of 2024-09-03.
+func newTestAuditRepository(t *testing.T) *auditRepository {
+	t.Helper()
+	db := newTestDB(t)
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 9, day, hour, minute, 0, 0, time.UTC)
+	}
+	entries := []models.AuditLog{
+		{ID: 1, Actor: "admin", Action: models.AuditActionMerge, EntityType: "satpen", EntityID: 1, CreatedAt: at(1, 10, 0)},
+		{ID: 2, Actor: "admin", Action: models.AuditActionUpdateLocation, EntityType: "satpen", EntityID: 2, CreatedAt: at(2, 9, 0)},
+		{ID: 3, Actor: "dapodik-sync", Action: models.AuditActionSync, EntityType: "pdptk", EntityID: 2, CreatedAt: at(2, 23, 30)},
+		{ID: 4, Actor: "dapodik-sync", Action: models.AuditActionSyncFailed, EntityType: "pdptk", EntityID: 3, CreatedAt: at(3, 0, 0)},
+		{ID: 5, Actor: "admin", Action: models.AuditActionMerge, EntityType: "satpen", EntityID: 5, CreatedAt: at(3, 8, 0)},
+		{ID: 6, Actor: "admin", Action: models.AuditActionUpdateLocation, EntityType: "satpen", EntityID: 3, CreatedAt: at(2, 9, 0)},
+	}
+	if err := db.Create(&entries).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &auditRepository{db: db}
+}
+
+func TestAuditFindAll(t *testing.T) {
+	repo := newTestAuditRepository(t)
+	day := func(d int) *time.Time { return datePtr(2024, time.September, d) }
+
+	tests := []struct {
+		name   string
+		filter models.AuditFilter
+		want   []uint
+	}{
+		// Newest first, ties by id
+		{"no filter", models.AuditFilter{}, []uint{5, 4, 3, 6, 2, 1}},
+		{"entity type", models.AuditFilter{EntityType: "satpen"}, []uint{5, 6, 2, 1}},
+		{"entity", models.AuditFilter{EntityType: "satpen", EntityID: 2}, []uint{2}},
+		{"entity id of any type", models.AuditFilter{EntityID: 2}, []uint{3, 2}},
+		{"actor", models.AuditFilter{Actor: "dapodik-sync"}, []uint{4, 3}},
+		{"action", models.AuditFilter{Action: models.AuditActionMerge}, []uint{5, 1}},
+		{"from is inclusive", models.AuditFilter{From: day(2)}, []uint{5, 4, 3, 6, 2}},
+		// The whole "to" day up to, not including, midnight of the next
+		{"to ends at the next day", models.AuditFilter{To: day(2)}, []uint{3, 6, 2, 1}},
+		{"one day", models.AuditFilter{From: day(2), To: day(2)}, []uint{3, 6, 2}},
+		{"combined", models.AuditFilter{Actor: "admin", From: day(2), Action: models.AuditActionUpdateLocation}, []uint{6, 2}},
+		{"no match", models.AuditFilter{Actor: "nobody"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, total, err := repo.FindAll(&tt.filter, 1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := auditIDs(entries); !reflect.DeepEqual(got, tt.want) || total != int64(len(tt.want)) {
+				t.Errorf("FindAll = %v (total %d), want %v", got, total, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditFindAllPage(t *testing.T) {
+	repo := newTestAuditRepository(t)
+
+	pages := [][]uint{{5, 4}, {3, 6}, {2, 1}, nil}
+	for i, want := range pages {
+		entries, total, err := repo.FindAll(&models.AuditFilter{}, i+1, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := auditIDs(entries); !reflect.DeepEqual(got, want) || total != 6 {
+			t.Errorf("page %d = %v (total %d), want %v (total 6)", i+1, got, total, want)
+		}
+	}
+}
+
+func auditIDs(entries []models.AuditLog) []uint {
+	var ids []uint
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// addYears returns the SQL for the datetime expr plus years. MySQL is the
+// production database; SQLite backs the repository tests and has no
+// DATE_ADD ... INTERVAL syntax.
+func addYears(db *gorm.DB, expr string, years int) string {
+	if db.Dialector.Name() == "sqlite" {
+		return fmt.Sprintf("datetime(%s, '%+d years')", expr, years)
+	}
+	return fmt.Sprintf("DATE_ADD(%s, INTERVAL %d YEAR)", expr, years)
+}
@@ -19,6 +19,14 @@ type MasterRepository interface {
 	GetAllPengurusCabang(filters map[string]interface{}, page, limit int) ([]models.PengurusCabang, int64, error)
 	GetPengurusCabangByID(id uint) (*models.PengurusCabang, error)
 
+	// Pengurus Wilayah
+	GetAllPengurusWilayah(search string) ([]models.PengurusWilayah, error)
+	GetPengurusWilayahByProvinsi(idProv uint) (*models.PengurusWilayah, error)
+
+	// Kategori Satpen
+	GetAllKategoriSatpen(search string) ([]models.KategoriSatpenWithCount, error)
+	GetKategoriSatpenByID(id uint) (*models.KategoriSatpenWithCount, error)
+
 	// Jenjang Pendidikan
 	GetAllJenjangPendidikan(search string) ([]models.JenjangPendidikan, error)
 	GetJenjangPendidikanByID(id uint) (*models.JenjangPendidikan, error)
@@ -108,10 +116,82 @@ func (r *masterRepository) GetAllPengurusCabang(filters map[string]interface{},
 
 func (r *masterRepository) GetPengurusCabangByID(id uint) (*models.PengurusCabang, error) {
 	var pengurusCabang models.PengurusCabang
-	err := r.db.Preload("Provinsi").First(&pengurusCabang, id).Error
+	err := r.db.Preload("Provinsi").Preload("Profile", latestCabangProfile).First(&pengurusCabang, id).Error
 	return &pengurusCabang, err
 }
 
+// latestProfile preloads only the newest profile row (highest id) of each
+// pengurus in table, keyed by column; a pengurus has one row per masa khidmat
+func latestProfile(table, column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("id IN (SELECT MAX(id) FROM " + table + " GROUP BY " + column + ")")
+	}
+}
+
+var (
+	latestCabangProfile  = latestProfile("profile_pengurus_cabang", "id_pc")
+	latestWilayahProfile = latestProfile("profile_pengurus_wilayah", "id_pw")
+)
+
+// Pengurus Wilayah Methods
+
+// jumlahCabangColumn counts the pengurus cabang of each provinsi
+const jumlahCabangColumn = "(SELECT COUNT(*) FROM pengurus_cabang pc WHERE pc.id_prov = provinsi.id_prov) AS jumlah_cabang"
+
+func (r *masterRepository) GetAllPengurusWilayah(search string) ([]models.PengurusWilayah, error) {
+	var pengurusWilayah []models.PengurusWilayah
+	query := r.db.Model(&models.PengurusWilayah{}).
+		Select("provinsi.*, "+jumlahCabangColumn).
+		Preload("Profile", latestWilayahProfile)
+
+	if search != "" {
+		query = query.Where("nm_prov LIKE ?", "%"+search+"%")
+	}
+
+	query = query.Order("nm_prov ASC")
+	err := query.Find(&pengurusWilayah).Error
+	return pengurusWilayah, err
+}
+
+func (r *masterRepository) GetPengurusWilayahByProvinsi(idProv uint) (*models.PengurusWilayah, error) {
+	var pengurusWilayah models.PengurusWilayah
+	err := r.db.Select("provinsi.*, "+jumlahCabangColumn).
+		Preload("Profile", latestWilayahProfile).
+		Preload("PengurusCabang", func(db *gorm.DB) *gorm.DB {
+			return db.Order("nama_pc ASC")
+		}).
+		First(&pengurusWilayah, idProv).Error
+	return &pengurusWilayah, err
+}
+
+// Kategori Satpen Methods
+
+// jumlahSatpenColumn counts the listed satpen of each kategori
+const jumlahSatpenColumn = "(SELECT COUNT(*) FROM satpen WHERE satpen.id_kategori = kategori_satpen.id_kategori AND satpen.status IN ?) AS jumlah_satpen"
+
+func (r *masterRepository) GetAllKategoriSatpen(search string) ([]models.KategoriSatpenWithCount, error) {
+	var kategori []models.KategoriSatpenWithCount
+	query := r.db.Model(&models.KategoriSatpen{}).
+		Select("kategori_satpen.*, "+jumlahSatpenColumn, models.ListedSatpenStatuses)
+
+	if search != "" {
+		query = query.Where("nm_kategori LIKE ? OR konotasi LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	query = query.Order("nm_kategori ASC")
+	err := query.Find(&kategori).Error
+	return kategori, err
+}
+
+func (r *masterRepository) GetKategoriSatpenByID(id uint) (*models.KategoriSatpenWithCount, error) {
+	var kategori models.KategoriSatpenWithCount
+	err := r.db.Model(&models.KategoriSatpen{}).
+		Select("kategori_satpen.*, "+jumlahSatpenColumn, models.ListedSatpenStatuses).
+		Where("kategori_satpen.id_kategori = ?", id).
+		First(&kategori).Error
+	return &kategori, err
+}
+
 // Jenjang Pendidikan Methods
 func (r *masterRepository) GetAllJenjangPendidikan(search string) ([]models.JenjangPendidikan, error) {
 	var jenjang []models.JenjangPendidikan
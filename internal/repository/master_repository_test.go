@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMasterRepositoryProvinsiKabupaten(t *testing.T) {
+	repo := NewMasterRepository(newTestDB(t))
+
+	provinsi, err := repo.GetAllProvinsi("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range provinsi {
+		names = append(names, p.NmProv)
+	}
+	if got, want := names, []string{"DI Yogyakarta", "Jawa Tengah", "Jawa Timur"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllProvinsi = %v, want %v", got, want)
+	}
+
+	provinsi, err = repo.GetAllProvinsi("timur")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(provinsi) != 1 || provinsi[0].KodeProv != "35" {
+		t.Errorf("GetAllProvinsi(timur) = %+v", provinsi)
+	}
+
+	if _, err := repo.GetProvinsiByID(99); err == nil || err.Error() != "record not found" {
+		t.Errorf("GetProvinsiByID(99) err = %v, want record not found", err)
+	}
+
+	kabupaten, err := repo.GetAllKabupaten(1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kabupaten) != 2 || kabupaten[0].NamaKab != "Kabupaten Malang" || kabupaten[0].Provinsi == nil || kabupaten[0].Provinsi.NmProv != "Jawa Timur" {
+		t.Errorf("GetAllKabupaten(1) = %+v", kabupaten)
+	}
+
+	kab, err := repo.GetKabupatenByID(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kab.NamaKab != "Kabupaten Kudus" || kab.Provinsi == nil || kab.Provinsi.IDProv != 2 {
+		t.Errorf("GetKabupatenByID(3) = %+v", kab)
+	}
+}
+
+func TestMasterRepositoryPengurus(t *testing.T) {
+	repo := NewMasterRepository(newTestDB(t))
+
+	pc, total, err := repo.GetAllPengurusCabang(map[string]interface{}{"provinsi_id": uint(1)}, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(pc) != 1 || pc[0].NamaPC != "PCNU Kabupaten Malang" {
+		t.Errorf("GetAllPengurusCabang page 1 = %+v (total %d)", pc, total)
+	}
+	pc, _, err = repo.GetAllPengurusCabang(map[string]interface{}{"provinsi_id": uint(1)}, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pc) != 1 || pc[0].NamaPC != "PCNU Kota Surabaya" {
+		t.Errorf("GetAllPengurusCabang page 2 = %+v", pc)
+	}
+
+	// The newest of several profiles wins
+	cabang, err := repo.GetPengurusCabangByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cabang.Profile == nil || cabang.Profile.Ketua != "Slamet Santoso" {
+		t.Errorf("Profile = %+v, want the 2023-2028 profile", cabang.Profile)
+	}
+
+	wilayah, err := repo.GetAllPengurusWilayah("jawa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wilayah) != 2 || wilayah[0].NmProv != "Jawa Tengah" || wilayah[0].JumlahCabang != 1 || wilayah[1].JumlahCabang != 2 {
+		t.Errorf("GetAllPengurusWilayah = %+v", wilayah)
+	}
+	if p := wilayah[1].Profile; p == nil || p.Ketua != "Hasan Mubarok" || wilayah[0].Profile != nil {
+		t.Errorf("GetAllPengurusWilayah profiles = %+v, %+v; want only the newest of Jawa Timur", wilayah[0].Profile, p)
+	}
+
+	pw, err := repo.GetPengurusWilayahByProvinsi(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pw.JumlahCabang != 2 || len(pw.PengurusCabang) != 2 || pw.PengurusCabang[0].NamaPC != "PCNU Kabupaten Malang" {
+		t.Errorf("GetPengurusWilayahByProvinsi = %+v", pw)
+	}
+	// The newest profile, with a decimal comma in the free text lintang
+	if pw.Profile == nil || pw.Profile.Ketua != "Hasan Mubarok" || pw.Profile.Coordinates == nil || pw.Profile.Coordinates.Latitude != -7.3363 {
+		t.Errorf("Profile = %+v", pw.Profile)
+	}
+}
+
+func TestMasterRepositoryKategoriJenjang(t *testing.T) {
+	repo := NewMasterRepository(newTestDB(t))
+
+	kategori, err := repo.GetAllKategoriSatpen("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Listed satpen only: A 1, 3, 9; B 2, 7; C 5; D none
+	want := map[string]int64{"A": 3, "B": 2, "C": 1, "D": 0}
+	if len(kategori) != len(want) {
+		t.Fatalf("GetAllKategoriSatpen = %+v", kategori)
+	}
+	for _, k := range kategori {
+		if k.JumlahSatpen != want[k.NmKategori] {
+			t.Errorf("kategori %s has %d satpen, want %d", k.NmKategori, k.JumlahSatpen, want[k.NmKategori])
+		}
+	}
+
+	kategori, err = repo.GetAllKategoriSatpen("baik")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kategori) != 1 || kategori[0].NmKategori != "B" {
+		t.Errorf("GetAllKategoriSatpen(baik) = %+v", kategori)
+	}
+
+	k, err := repo.GetKategoriSatpenByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Konotasi != "Unggul" || k.JumlahSatpen != 3 {
+		t.Errorf("GetKategoriSatpenByID(1) = %+v", k)
+	}
+
+	jenjang, err := repo.GetAllJenjangPendidikan("m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, j := range jenjang {
+		names = append(names, j.NmJenjang)
+	}
+	if got, want := names, []string{"MI", "MTs", "SMK"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllJenjangPendidikan(m) = %v, want %v", got, want)
+	}
+
+	j, err := repo.GetJenjangPendidikanByID(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.NmJenjang != "SMK" || j.Lembaga != "SEKOLAH" {
+		t.Errorf("GetJenjangPendidikanByID(3) = %+v", j)
+	}
+}
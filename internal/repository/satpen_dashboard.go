@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"satpen-api/internal/models"
+	"time"
+)
+
+// CountByJenjangStatus counts the satpen matching filter per jenjang and
+// status, with siswa/guru/tendik totals from their latest PDPTK
+func (r *satpenRepository) CountByJenjangStatus(filter *models.SatpenFilter) ([]models.JenjangStatusCount, error) {
+	var results []models.JenjangStatusCount
+
+	query := r.db.Table("satpen").
+		Select("jenjang_pendidikan.nm_jenjang as jenjang, satpen.status as status, COUNT(*) as count, " +
+			"COALESCE(SUM(pdptk.jml_pd), 0) as siswa, COALESCE(SUM(pdptk.jml_guru), 0) as guru, COALESCE(SUM(pdptk.jml_tendik), 0) as tendik").
+		Joins("INNER JOIN jenjang_pendidikan ON jenjang_pendidikan.id_jenjang = satpen.id_jenjang").
+		Joins(joinLatestPDPTK("LEFT", "pdptk")).
+		Group("jenjang_pendidikan.id_jenjang, jenjang_pendidikan.nm_jenjang, satpen.status")
+
+	query = r.applyFilters(query, filter)
+
+	err := query.Scan(&results).Error
+	return results, err
+}
+
+// FindExpiring returns the satpen matching filter whose registration, valid
+// for validYears from actived_date, expires in [from, until), soonest first
+func (r *satpenRepository) FindExpiring(filter *models.SatpenFilter, validYears int, from, until time.Time) ([]models.ExpiringSatpen, error) {
+	var results []models.ExpiringSatpen
+
+	expiresAt := addYears(r.db, "satpen.actived_date", validYears)
+	query := r.db.Table("satpen").
+		Select("satpen.id_satpen, satpen.npsn, satpen.nm_satpen as nama, jenjang_pendidikan.nm_jenjang as jenjang, satpen.actived_date").
+		Joins("INNER JOIN jenjang_pendidikan ON jenjang_pendidikan.id_jenjang = satpen.id_jenjang").
+		Where("satpen.actived_date IS NOT NULL").
+		Where(expiresAt+" >= ? AND "+expiresAt+" < ?", from, until)
+
+	query = r.applyFilters(query, filter)
+
+	if err := query.Order("satpen.actived_date ASC, satpen.id_satpen ASC").Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].ExpiresAt = addYearsTime(results[i].ActivedDate, validYears)
+	}
+	return results, nil
+}
+
+// addYearsTime adds years to t like DATE_ADD does: a day missing in the
+// target month (29 February) becomes the last day of that month
+func addYearsTime(t time.Time, years int) time.Time {
+	added := t.AddDate(years, 0, 0)
+	if added.Day() != t.Day() {
+		added = added.AddDate(0, 0, -added.Day())
+	}
+	return added
+}
+
+// CountPTK counts the PTK submissions with statusAjuan of the satpen matching
+// filter
+func (r *satpenRepository) CountPTK(filter *models.SatpenFilter, statusAjuan string) (int64, error) {
+	var count int64
+
+	query := r.db.Table("ptk").
+		Joins("INNER JOIN satpen ON satpen.id_satpen = ptk.id_satpen").
+		Where("ptk.status_ajuan = ?", statusAjuan)
+
+	query = r.applyFilters(query, filter)
+
+	err := query.Count(&count).Error
+	return count, err
+}
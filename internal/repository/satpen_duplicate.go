@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"satpen-api/internal/duplicate"
+	"satpen-api/internal/models"
+)
+
+// FindDuplicateRecords returns the fields the duplicate detector compares
+// for every satpen matching filter, ordered by kabupaten
+func (r *satpenRepository) FindDuplicateRecords(filter *models.SatpenFilter) ([]duplicate.Record, error) {
+	var results []duplicate.Record
+
+	query := r.db.Table("satpen").
+		Select("satpen.id_satpen as id, satpen.no_registrasi, satpen.npsn, satpen.nm_satpen as nama, satpen.alamat, " +
+			"satpen.kelurahan, satpen.yayasan, satpen.status, satpen.id_kab, kabupaten.nama_kab as kabupaten").
+		Joins("INNER JOIN kabupaten ON kabupaten.id_kab = satpen.id_kab")
+
+	query = r.applyFilters(query, filter)
+
+	err := query.Order("satpen.id_kab ASC, satpen.id_satpen ASC").Scan(&results).Error
+	return results, err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"satpen-api/internal/audit"
+	"satpen-api/internal/cluster"
+	"satpen-api/internal/geo"
+	"satpen-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// haversineExpr is the distance in km from (?, ?) to the satpen location,
+// bound as lat, lat, lng
+const haversineExpr = "2 * 6371 * ASIN(LEAST(1, SQRT(" +
+	"POW(SIN(RADIANS(satpen.lintang - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(satpen.lintang)) * " +
+	"POW(SIN(RADIANS(satpen.bujur - ?) / 2), 2))))"
+
+// FindNearby returns up to limit satpen within radiusKm of (lat, lng),
+// nearest first, with Distance set. Satpen without a location are skipped.
+func (r *satpenRepository) FindNearby(lat, lng, radiusKm float64, limit int, filter *models.SatpenFilter, proj *Projection) ([]models.Satpen, error) {
+	minLat, maxLat, minLng, maxLng := geo.BoundingBox(lat, lng, radiusKm)
+
+	query := r.preloadRelations(r.db.Model(&models.Satpen{}), proj)
+	query = r.applyFilters(query, filter)
+
+	var satpen []models.Satpen
+	err := query.
+		Select(selectColumns(proj)+", "+haversineExpr+" AS distance_km", lat, lat, lng).
+		// The box lets idx_satpen_location narrow rows before the exact distance
+		Where("satpen.lintang BETWEEN ? AND ?", minLat, maxLat).
+		Where("satpen.bujur BETWEEN ? AND ?", minLng, maxLng).
+		Having("distance_km <= ?", radiusKm).
+		Order("distance_km ASC").
+		Order("satpen.id_satpen ASC").
+		Limit(limit).
+		Find(&satpen).Error
+	return satpen, err
+}
+
+// UpdateLocation sets or, with nil values, clears the location of a satpen
+// and writes entry with the previous and new location
+func (r *satpenRepository) UpdateLocation(id uint, lat, lng *float64, entry *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before models.Satpen
+		if err := tx.Select("id_satpen", "lintang", "bujur").First(&before, "id_satpen = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Satpen{}).
+			Where("id_satpen = ?", id).
+			Updates(map[string]interface{}{"lintang": lat, "bujur": lng}).Error; err != nil {
+			return err
+		}
+
+		location := func(lat, lng *float64) map[string]*float64 {
+			return map[string]*float64{"latitude": lat, "longitude": lng}
+		}
+		if err := audit.Fill(entry, location(before.Lintang, before.Bujur), location(lat, lng)); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// FindClusterPoints returns the located satpen listed by default (same status
+// set as applyFilters) for the cluster index
+func (r *satpenRepository) FindClusterPoints() ([]cluster.Point, error) {
+	var points []cluster.Point
+
+	err := r.db.Table("satpen").
+		Select("satpen.id_satpen as id, satpen.npsn, satpen.nm_satpen as name, jenjang_pendidikan.nm_jenjang as jenjang, satpen.lintang as latitude, satpen.bujur as longitude").
+		Joins("LEFT JOIN jenjang_pendidikan ON jenjang_pendidikan.id_jenjang = satpen.id_jenjang").
+		Where("satpen.status IN (?)", models.ListedSatpenStatuses).
+		Where("satpen.lintang IS NOT NULL AND satpen.bujur IS NOT NULL").
+		Scan(&points).Error
+
+	return points, err
+}
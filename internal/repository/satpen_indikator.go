@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+	"satpen-api/internal/models"
+)
+
+// GroupJenjang groups the indicator ranking per jenjang, next to
+// RegionProvinsi and RegionKabupaten
+const GroupJenjang = "jenjang"
+
+// ErrIndikatorGroup is returned for a group other than provinsi, kabupaten or
+// jenjang
+var ErrIndikatorGroup = errors.New("group_by must be provinsi, kabupaten or jenjang")
+
+// FindIndikatorRows returns every satpen matching filter that has a PDPTK,
+// with the counts of its latest PDPTK and its provinsi, kabupaten or jenjang
+func (r *satpenRepository) FindIndikatorRows(groupBy string, filter *models.SatpenFilter) ([]models.IndikatorRow, error) {
+	var results []models.IndikatorRow
+
+	query := r.db.Table("satpen").
+		Joins(joinLatestPDPTK("INNER", "pdptk"))
+
+	const columns = "satpen.id_satpen, satpen.npsn, satpen.nm_satpen as nama, " +
+		"pdptk.jml_pd, pdptk.jml_guru, pdptk.guru_pr, pdptk.jml_tendik, "
+	switch groupBy {
+	case RegionProvinsi:
+		query = query.Select(columns + "provinsi.id_prov as group_id, provinsi.nm_prov as group_name").
+			Joins("INNER JOIN provinsi ON provinsi.id_prov = satpen.id_prov")
+	case RegionKabupaten:
+		query = query.Select(columns + "kabupaten.id_kab as group_id, kabupaten.nama_kab as group_name").
+			Joins("INNER JOIN kabupaten ON kabupaten.id_kab = satpen.id_kab")
+	case GroupJenjang:
+		query = query.Select(columns + "jenjang_pendidikan.id_jenjang as group_id, jenjang_pendidikan.nm_jenjang as group_name").
+			Joins("INNER JOIN jenjang_pendidikan ON jenjang_pendidikan.id_jenjang = satpen.id_jenjang")
+	default:
+		return nil, ErrIndikatorGroup
+	}
+
+	query = r.applyFilters(query, filter)
+
+	err := query.Order("satpen.id_satpen ASC").Scan(&results).Error
+	return results, err
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"satpen-api/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrKeysetSort is returned when a sort field cannot be used for keyset pagination
+	ErrKeysetSort = errors.New("sort field not supported for cursor pagination")
+	// ErrKeysetValue is returned when a keyset value doesn't fit its sort column
+	ErrKeysetValue = errors.New("invalid keyset value")
+)
+
+// Keyset is the boundary row of a cursor page: the next page starts after it,
+// or before it when Backward is set
+type Keyset struct {
+	Value    string // encoded sort key, see KeysetValue
+	ID       uint
+	Backward bool
+}
+
+type keysetColumn struct {
+	column string
+	// expr never yields NULL so that row comparisons stay total
+	expr  string
+	parse func(value string) (interface{}, error)
+	value func(s *models.Satpen) string
+}
+
+// nullTime stands in for NULL created_at/updated_at, before any real timestamp
+const nullTime = "1970-01-01 00:00:01"
+
+var keysetColumns = map[string]keysetColumn{
+	"nama": {
+		column: "nm_satpen",
+		expr:   "satpen.nm_satpen",
+		parse:  func(value string) (interface{}, error) { return value, nil },
+		value:  func(s *models.Satpen) string { return s.NmSatpen },
+	},
+	"tahun_berdiri": {
+		column: "thn_berdiri",
+		expr:   "COALESCE(satpen.thn_berdiri, 0)",
+		parse: func(value string) (interface{}, error) {
+			return strconv.Atoi(value)
+		},
+		value: func(s *models.Satpen) string { return strconv.Itoa(s.ThnBerdiri) },
+	},
+	"created_at": {
+		column: "created_at",
+		expr:   "COALESCE(satpen.created_at, TIMESTAMP '" + nullTime + "')",
+		parse:  parseKeysetTime,
+		value:  func(s *models.Satpen) string { return formatKeysetTime(s.CreatedAt) },
+	},
+	"updated_at": {
+		column: "updated_at",
+		expr:   "COALESCE(satpen.updated_at, TIMESTAMP '" + nullTime + "')",
+		parse:  parseKeysetTime,
+		value:  func(s *models.Satpen) string { return formatKeysetTime(s.UpdatedAt) },
+	},
+}
+
+// ParseKeysetSort splits sort ("-created_at", "nama") into its field and
+// direction. An empty sort means -created_at.
+func ParseKeysetSort(sort string) (field string, desc bool, err error) {
+	if sort == "" {
+		sort = "-created_at"
+	}
+	desc = strings.HasPrefix(sort, "-")
+	field = strings.TrimPrefix(sort, "-")
+	if _, ok := keysetColumns[field]; !ok {
+		return "", false, ErrKeysetSort
+	}
+	return field, desc, nil
+}
+
+// KeysetValue encodes the sort key of s for use in a Keyset
+func KeysetValue(sort string, s *models.Satpen) (string, error) {
+	field, _, err := ParseKeysetSort(sort)
+	if err != nil {
+		return "", err
+	}
+	return keysetColumns[field].value(s), nil
+}
+
+// FindAllKeyset returns up to limit rows after (or before) the keyset in sort
+// order, without OFFSET. A nil keyset starts at the first row. Rows are always
+// returned in sort order, also when paging backward.
+func (r *satpenRepository) FindAllKeyset(filter *models.SatpenFilter, limit int, sort string, keyset *Keyset, proj *Projection) ([]models.Satpen, error) {
+	field, desc, err := ParseKeysetSort(sort)
+	if err != nil {
+		return nil, err
+	}
+	column := keysetColumns[field]
+
+	query := r.preloadRelations(r.db.Model(&models.Satpen{}), proj)
+	query = r.applyFilters(query, filter)
+	// The sort column must be loaded to build the next cursor
+	query = r.applySelect(query, filter, proj, column.column)
+
+	// Walking backward reverses the scan direction; results are flipped below
+	scanDesc := desc
+	if keyset != nil && keyset.Backward {
+		scanDesc = !desc
+	}
+
+	if keyset != nil {
+		value, err := column.parse(keyset.Value)
+		if err != nil {
+			return nil, ErrKeysetValue
+		}
+		op := ">"
+		if scanDesc {
+			op = "<"
+		}
+		query = query.Where(
+			fmt.Sprintf("(%s %s ? OR (%s = ? AND satpen.id_satpen %s ?))", column.expr, op, column.expr, op),
+			value, value, keyset.ID,
+		)
+	}
+
+	direction := "ASC"
+	if scanDesc {
+		direction = "DESC"
+	}
+	query = query.Order(column.expr + " " + direction).
+		Order("satpen.id_satpen " + direction).
+		Limit(limit)
+
+	var satpen []models.Satpen
+	if err := query.Find(&satpen).Error; err != nil {
+		return nil, err
+	}
+
+	if keyset != nil && keyset.Backward {
+		for i, j := 0, len(satpen)-1; i < j; i, j = i+1, j-1 {
+			satpen[i], satpen[j] = satpen[j], satpen[i]
+		}
+	}
+	return satpen, nil
+}
+
+// Count returns the number of satpen matching filters
+func (r *satpenRepository) Count(filter *models.SatpenFilter) (int64, error) {
+	var total int64
+	query := r.applyFilters(r.db.Model(&models.Satpen{}), filter)
+	err := query.Count(&total).Error
+	return total, err
+}
+
+func parseKeysetTime(value string) (interface{}, error) {
+	if value == "" {
+		return nullTime, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func formatKeysetTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
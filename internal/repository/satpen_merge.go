@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"satpen-api/internal/audit"
+	"satpen-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MergeSatpen re-points the pdptk, ptk and timeline_reg rows of loserID to
+// survivorID and deletes the losing satpen, in one transaction that also
+// writes the audit entry for the survivor and a copy for the loser, so the
+// history of either id shows the merge. The survivor keeps its own PDPTK where
+// both have one for the same tapel; the loser's is dropped. With preview nothing is written and the
+// result only counts what would move.
+func (r *satpenRepository) MergeSatpen(survivorID, loserID uint, preview bool, entry *models.AuditLog) (*models.MergeResult, error) {
+	result := &models.MergeResult{
+		SurvivorID:   survivorID,
+		LoserID:      loserID,
+		Preview:      preview,
+		PDPTKMoved:   []string{},
+		PDPTKDropped: []string{},
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx
+		if !preview {
+			// A new session, so the second First does not inherit the first's condition
+			query = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Session(&gorm.Session{})
+		}
+		var survivor, loser models.Satpen
+		if err := query.First(&survivor, "id_satpen = ?", survivorID).Error; err != nil {
+			return err
+		}
+		if err := query.First(&loser, "id_satpen = ?", loserID).Error; err != nil {
+			return err
+		}
+
+		var survivorTapels []string
+		if err := tx.Model(&models.PDPTK{}).Where("id_satpen = ?", survivorID).Pluck("tapel", &survivorTapels).Error; err != nil {
+			return err
+		}
+		taken := make(map[string]bool, len(survivorTapels))
+		for _, tapel := range survivorTapels {
+			taken[tapel] = true
+		}
+
+		var loserPDPTK []models.PDPTK
+		if err := tx.Select("id", "tapel").Where("id_satpen = ?", loserID).Order("tapel ASC").Find(&loserPDPTK).Error; err != nil {
+			return err
+		}
+		var dropped []int
+		for _, p := range loserPDPTK {
+			if taken[p.Tapel] {
+				dropped = append(dropped, p.ID)
+				result.PDPTKDropped = append(result.PDPTKDropped, p.Tapel)
+			} else {
+				result.PDPTKMoved = append(result.PDPTKMoved, p.Tapel)
+			}
+		}
+
+		if err := tx.Table("ptk").Where("id_satpen = ?", loserID).Count(&result.PTKMoved).Error; err != nil {
+			return err
+		}
+		if err := tx.Table("timeline_reg").Where("id_satpen = ?", loserID).Count(&result.TimelineMoved).Error; err != nil {
+			return err
+		}
+
+		if preview {
+			return nil
+		}
+
+		if len(dropped) > 0 {
+			if err := tx.Where("id IN ?", dropped).Delete(&models.PDPTK{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, table := range []string{"pdptk", "ptk", "timeline_reg"} {
+			if err := tx.Table(table).Where("id_satpen = ?", loserID).Update("id_satpen", survivorID).Error; err != nil {
+				return err
+			}
+		}
+		// Children are re-pointed, so the ON DELETE CASCADE has nothing left to remove
+		if err := tx.Delete(&models.Satpen{}, "id_satpen = ?", loserID).Error; err != nil {
+			return err
+		}
+
+		before := map[string]interface{}{"survivor": survivor, "loser": loser}
+		after := map[string]interface{}{"survivor": survivor, "loser": nil, "merge": result}
+		if err := audit.Fill(entry, before, after); err != nil {
+			return err
+		}
+		loserEntry := *entry
+		loserEntry.EntityID = loserID
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&loserEntry).Error; err != nil {
+			return err
+		}
+		result.AuditID = entry.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
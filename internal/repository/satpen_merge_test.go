@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"satpen-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// mergeCounts are the rows a merge of 5 into 1 touches
+type mergeCounts struct {
+	Satpen5, PDPTK1, PDPTK5, PTK1, PTK5, Timeline1, Timeline5, Audit int64
+}
+
+func countMergeRows(t *testing.T, db *gorm.DB) mergeCounts {
+	t.Helper()
+	var c mergeCounts
+	counts := []struct {
+		table string
+		where string
+		arg   uint
+		dest  *int64
+	}{
+		{"satpen", "id_satpen = ?", 5, &c.Satpen5},
+		{"pdptk", "id_satpen = ?", 1, &c.PDPTK1},
+		{"pdptk", "id_satpen = ?", 5, &c.PDPTK5},
+		{"ptk", "id_satpen = ?", 1, &c.PTK1},
+		{"ptk", "id_satpen = ?", 5, &c.PTK5},
+		{"timeline_reg", "id_satpen = ?", 1, &c.Timeline1},
+		{"timeline_reg", "id_satpen = ?", 5, &c.Timeline5},
+		{"audit_log", "id > ?", 0, &c.Audit},
+	}
+	for _, count := range counts {
+		if err := db.Table(count.table).Where(count.where, count.arg).Count(count.dest).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func newMergeEntry() *models.AuditLog {
+	return &models.AuditLog{Actor: "admin", Action: models.AuditActionMerge, EntityType: "satpen", EntityID: 1}
+}
+
+func TestMergeSatpenPreview(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+	before := countMergeRows(t, repo.db)
+
+	// Satpen 1 has PDPTK 20231 and 20241, satpen 5 has 20231 and 20232
+	result, err := repo.MergeSatpen(1, 5, true, newMergeEntry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &models.MergeResult{
+		SurvivorID:    1,
+		LoserID:       5,
+		Preview:       true,
+		PDPTKMoved:    []string{"20232"},
+		PDPTKDropped:  []string{"20231"},
+		PTKMoved:      1,
+		TimelineMoved: 2,
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("preview = %+v, want %+v", result, want)
+	}
+	if after := countMergeRows(t, repo.db); after != before {
+		t.Errorf("preview wrote rows: before %+v, after %+v", before, after)
+	}
+}
+
+func TestMergeSatpenCommit(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	result, err := repo.MergeSatpen(1, 5, false, newMergeEntry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.PDPTKMoved, []string{"20232"}) || !reflect.DeepEqual(result.PDPTKDropped, []string{"20231"}) ||
+		result.PTKMoved != 1 || result.TimelineMoved != 2 || result.AuditID == 0 {
+		t.Errorf("merge = %+v", result)
+	}
+
+	want := mergeCounts{PDPTK1: 3, PTK1: 4, Timeline1: 3, Audit: 2}
+	if got := countMergeRows(t, repo.db); got != want {
+		t.Errorf("rows after merge = %+v, want %+v", got, want)
+	}
+
+	// The survivor keeps its own 20231 PDPTK; the loser's 20231 (id 5) is gone
+	var pdptk []models.PDPTK
+	if err := repo.db.Where("id_satpen = ?", 1).Order("tapel ASC").Find(&pdptk).Error; err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, p := range pdptk {
+		ids = append(ids, p.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 6, 2}) {
+		t.Errorf("pdptk of satpen 1 = %v, want [1 6 2]", ids)
+	}
+
+	// One entry per satpen, so filtering audit_log on either id finds the merge
+	var entries []models.AuditLog
+	if err := repo.db.Order("entity_id ASC").Find(&entries).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != result.AuditID || entries[0].EntityID != 1 || entries[1].EntityID != 5 {
+		t.Fatalf("audit entries = %+v, want satpen 1 (audit_id %d) and 5", entries, result.AuditID)
+	}
+	for _, entry := range entries {
+		if entry.Action != models.AuditActionMerge || entry.Actor != "admin" || len(entry.Before) == 0 || len(entry.After) == 0 {
+			t.Errorf("audit entry = %+v", entry)
+		}
+	}
+}
+
+func TestMergeSatpenRollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		survivor uint
+		loser    uint
+	}{
+		{"missing survivor", 99, 5},
+		{"missing loser", 1, 99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestSatpenRepository(t)
+			before := countMergeRows(t, repo.db)
+
+			result, err := repo.MergeSatpen(tt.survivor, tt.loser, false, newMergeEntry())
+			if !errors.Is(err, gorm.ErrRecordNotFound) || result != nil {
+				t.Fatalf("merge = %+v, %v; want ErrRecordNotFound", result, err)
+			}
+			if after := countMergeRows(t, repo.db); after != before {
+				t.Errorf("rows changed: before %+v, after %+v", before, after)
+			}
+		})
+	}
+}
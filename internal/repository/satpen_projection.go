@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"satpen-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// satpenRelations are preloaded when no Projection is given
+var satpenRelations = []string{"Provinsi", "Kabupaten", "Jenjang", "Kategori", "PengurusCabang", "PDPTK"}
+
+// Projection limits which satpen columns are selected and which relations are
+// preloaded. A nil Projection loads every column and relation.
+type Projection struct {
+	Columns  []string // satpen columns; empty selects all
+	Preloads []string // relation names as in models.Satpen (Provinsi, PDPTK, ...)
+}
+
+// preloadRelations preloads the relations of proj, or all of them
+func (r *satpenRepository) preloadRelations(query *gorm.DB, proj *Projection) *gorm.DB {
+	relations := satpenRelations
+	if proj != nil {
+		relations = proj.Preloads
+	}
+
+	for _, relation := range relations {
+		if relation == "PDPTK" {
+			query = query.Preload("PDPTK", func(db *gorm.DB) *gorm.DB {
+				// Latest PDPTK data of each satpen in the batch
+				return db.Where(latestPDPTK("pdptk"))
+			})
+			continue
+		}
+		query = query.Preload(relation)
+	}
+	return query
+}
+
+// applySelect selects the projected columns (always including id_satpen and
+// any extra columns the caller needs, e.g. the keyset sort column) plus the
+// FULLTEXT relevance score when searching. Must be applied after Count.
+func (r *satpenRepository) applySelect(query *gorm.DB, filter *models.SatpenFilter, proj *Projection, extra ...string) *gorm.DB {
+	columns := selectColumns(proj, extra...)
+	if expr, term, ok := fulltextMatch(filter); ok {
+		return query.Select(columns+", "+expr+" AS relevance", term)
+	}
+	// Always explicit: sort joins must not add their columns to the result
+	return query.Select(columns)
+}
+
+// selectColumns returns the satpen column list of proj for a SELECT
+func selectColumns(proj *Projection, extra ...string) string {
+	if proj == nil || len(proj.Columns) == 0 {
+		return "satpen.*"
+	}
+	seen := map[string]bool{"id_satpen": true}
+	columns := "satpen.id_satpen"
+	for _, column := range append(append([]string{}, proj.Columns...), extra...) {
+		if column == "" || seen[column] {
+			continue
+		}
+		seen[column] = true
+		columns += ", satpen." + column
+	}
+	return columns
+}
+
+// findOne loads a single satpen matching the conditions with proj applied
+func (r *satpenRepository) findOne(proj *Projection, conds ...interface{}) (*models.Satpen, error) {
+	var satpen models.Satpen
+	query := r.preloadRelations(r.db.Model(&models.Satpen{}), proj)
+	query = r.applySelect(query, nil, proj)
+
+	if err := query.First(&satpen, conds...).Error; err != nil {
+		return nil, err
+	}
+	return &satpen, nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"satpen-api/internal/models"
+	"satpen-api/internal/quality"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// qualityColumns are the satpen columns the data quality rules read
+var qualityColumns = []string{"id_satpen", "id_prov", "id_kab", "id_pc", "npsn", "nm_satpen", "kepsek", "telpon", "email", "thn_berdiri"}
+
+// GetCurrentTapel returns the latest tahun pelajaran code as used by Dapodik
+func (r *satpenRepository) GetCurrentTapel() (string, error) {
+	return currentTapel(r.db)
+}
+
+// qualityCondition is the SQL form of a data quality rule, true when a
+// satpen fails it
+type qualityCondition struct {
+	sql  string
+	args []interface{}
+}
+
+// qualityConditions returns the SQL form of every quality.Rules entry, in
+// rule order. The text rules share their patterns with the quality package.
+func qualityConditions(tapel string, now time.Time) ([]qualityCondition, error) {
+	conds := make([]qualityCondition, 0, len(quality.Rules))
+	for _, rule := range quality.Rules {
+		var cond qualityCondition
+		switch rule.ID {
+		case "kepsek_empty":
+			cond = qualityCondition{"COALESCE(satpen.kepsek, '') REGEXP ?", []interface{}{quality.BlankPattern}}
+		case "telpon_invalid":
+			cond = qualityCondition{"NOT (COALESCE(satpen.telpon, '') REGEXP ?) AND NOT (COALESCE(satpen.telpon, '') REGEXP ?)",
+				[]interface{}{quality.BlankPattern, quality.PhonePattern}}
+		case "email_invalid":
+			cond = qualityCondition{"NOT (COALESCE(satpen.email, '') REGEXP ?) AND NOT (COALESCE(satpen.email, '') REGEXP ?)",
+				[]interface{}{quality.BlankPattern, quality.EmailPattern}}
+		case "npsn_invalid":
+			cond = qualityCondition{"NOT (COALESCE(satpen.npsn, '') REGEXP ?)", []interface{}{quality.NPSNPattern}}
+		case "thn_berdiri_future":
+			cond = qualityCondition{"COALESCE(satpen.thn_berdiri, 0) > ?", []interface{}{now.Year()}}
+		case "pdptk_missing":
+			cond = qualityCondition{"1 = 0", nil}
+			if tapel != "" {
+				cond = qualityCondition{"NOT EXISTS (SELECT 1 FROM pdptk WHERE pdptk.id_satpen = satpen.id_satpen AND pdptk.tapel = ?)", []interface{}{tapel}}
+			}
+		case "pengurus_cabang_provinsi":
+			cond = qualityCondition{"EXISTS (SELECT 1 FROM pengurus_cabang pc WHERE pc.id_pc = satpen.id_pc AND pc.id_prov <> satpen.id_prov)", nil}
+		default:
+			return nil, fmt.Errorf("no SQL for data quality rule %s", rule.ID)
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// qualityFailed is the summed weight of the failed rules of a satpen; the
+// score falls as it grows
+func qualityFailed(conds []qualityCondition) (string, []interface{}) {
+	parts := make([]string, len(conds))
+	var args []interface{}
+	for i, cond := range conds {
+		parts[i] = fmt.Sprintf("CASE WHEN %s THEN %d ELSE 0 END", cond.sql, quality.Rules[i].Weight())
+		args = append(args, cond.args...)
+	}
+	return "(" + strings.Join(parts, " + ") + ")", args
+}
+
+// qualityFailsAny is true when a satpen fails any of rules, every rule when
+// empty
+func qualityFailsAny(conds []qualityCondition, rules []string) (string, []interface{}) {
+	selected := make(map[string]bool)
+	for _, rule := range rules {
+		selected[rule] = true
+	}
+	var parts []string
+	var args []interface{}
+	for i, cond := range conds {
+		if len(selected) == 0 || selected[quality.Rules[i].ID] {
+			parts = append(parts, "("+cond.sql+")")
+			args = append(args, cond.args...)
+		}
+	}
+	if len(parts) == 0 {
+		return "1 = 0", nil
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", args
+}
+
+// CountQualityIssues runs the data quality rules in SQL over the satpen
+// matching filter: how many were checked, their summed score and the
+// failures per rule. WithIssues counts the satpen failing any of rules.
+func (r *satpenRepository) CountQualityIssues(filter *models.SatpenFilter, tapel string, now time.Time, rules []string) (*models.DataQualitySummary, error) {
+	conds, err := qualityConditions(tapel, now)
+	if err != nil {
+		return nil, err
+	}
+
+	total := quality.TotalWeight()
+	failed, args := qualityFailed(conds)
+	anyFails, anyArgs := qualityFailsAny(conds, rules)
+	columns := []string{
+		"COUNT(*)",
+		"SUM(CASE WHEN " + anyFails + " THEN 1 ELSE 0 END)",
+		fmt.Sprintf("SUM(ROUND(100.0 * (%d - %s) / %d))", total, failed, total),
+	}
+	args = append(anyArgs, args...)
+	for _, cond := range conds {
+		columns = append(columns, "SUM(CASE WHEN "+cond.sql+" THEN 1 ELSE 0 END)")
+		args = append(args, cond.args...)
+	}
+
+	query := r.applyFilters(r.db.Model(&models.Satpen{}), filter)
+	row := query.Select(strings.Join(columns, ", "), args...).Row()
+
+	var checked int64
+	sums := make([]sql.NullFloat64, 2+len(conds))
+	dest := []interface{}{&checked}
+	for i := range sums {
+		dest = append(dest, &sums[i])
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	summary := &models.DataQualitySummary{
+		Tapel:      tapel,
+		Checked:    int(checked),
+		WithIssues: int(sums[0].Float64),
+		ByRule:     make(map[string]int64, len(conds)),
+	}
+	if checked > 0 {
+		summary.AverageScore = models.Round2(sums[1].Float64 / float64(checked))
+	}
+	for i, rule := range quality.Rules {
+		summary.ByRule[rule.ID] = int64(sums[2+i].Float64)
+	}
+	return summary, nil
+}
+
+// FindQualityIssues returns a page of the satpen matching filter that fail
+// any of rules (every rule when empty), lowest score first then by id, with
+// their PDPTK of tapel for quality.Check
+func (r *satpenRepository) FindQualityIssues(filter *models.SatpenFilter, tapel string, now time.Time, rules []string, page, limit int) ([]quality.Record, error) {
+	conds, err := qualityConditions(tapel, now)
+	if err != nil {
+		return nil, err
+	}
+	anyFails, anyArgs := qualityFailsAny(conds, rules)
+	failed, failedArgs := qualityFailed(conds)
+
+	var satpen []models.Satpen
+	query := r.applyFilters(r.qualityQuery(), filter).
+		Where(anyFails, anyArgs...).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: failed + " DESC, satpen.id_satpen ASC", Vars: failedArgs, WithoutParentheses: true}}).
+		Offset((page - 1) * limit).
+		Limit(limit)
+	if err := query.Find(&satpen).Error; err != nil {
+		return nil, err
+	}
+
+	return r.qualityRecords(satpen, tapel)
+}
+
+// FindQualityRecord loads one satpen of any status for the data quality rules
+func (r *satpenRepository) FindQualityRecord(id uint, tapel string) (*quality.Record, error) {
+	var satpen models.Satpen
+	if err := r.qualityQuery().First(&satpen, "satpen.id_satpen = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	records, err := r.qualityRecords([]models.Satpen{satpen}, tapel)
+	if err != nil {
+		return nil, err
+	}
+	return &records[0], nil
+}
+
+func (r *satpenRepository) qualityQuery() *gorm.DB {
+	return r.db.Model(&models.Satpen{}).
+		Select(selectColumns(&Projection{Columns: qualityColumns})).
+		Preload("Provinsi").
+		Preload("Kabupaten").
+		Preload("PengurusCabang")
+}
+
+// qualityRecords pairs satpen with their PDPTK of tapel, if any
+func (r *satpenRepository) qualityRecords(satpen []models.Satpen, tapel string) ([]quality.Record, error) {
+	pdptk := make(map[uint]*models.PDPTK)
+	if tapel != "" && len(satpen) > 0 {
+		ids := make([]uint, len(satpen))
+		for i, s := range satpen {
+			ids[i] = s.IDSatpen
+		}
+
+		var rows []models.PDPTK
+		if err := r.db.Where("tapel = ? AND id_satpen IN ?", tapel, ids).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			if rows[i].IDSatpen != nil {
+				pdptk[*rows[i].IDSatpen] = &rows[i]
+			}
+		}
+	}
+
+	records := make([]quality.Record, len(satpen))
+	for i, s := range satpen {
+		records[i] = quality.Record{Satpen: s, PDPTK: pdptk[s.IDSatpen], Tapel: tapel}
+	}
+	return records, nil
+}
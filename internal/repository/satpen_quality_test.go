@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"satpen-api/internal/models"
+	"satpen-api/internal/quality"
+)
+
+// newQualityTestRepository makes the listed fixture satpen fail a few data
+// quality rules; 1 and 7 pass all of them
+func newQualityTestRepository(t *testing.T) *satpenRepository {
+	t.Helper()
+	repo := newTestSatpenRepository(t)
+	updates := []string{
+		"UPDATE satpen SET kepsek = 'Kepala Sekolah'",
+		"UPDATE satpen SET telpon = '(0341) 458-123', email = 'mi@example.com' WHERE id_satpen = 1",
+		"UPDATE satpen SET kepsek = NULL, telpon = '12ab' WHERE id_satpen = 2",
+		"UPDATE satpen SET npsn = '2050700', email = 'admin@localhost' WHERE id_satpen = 3",
+		"UPDATE satpen SET thn_berdiri = 2099 WHERE id_satpen = 4",
+		"UPDATE satpen SET id_pc = 1 WHERE id_satpen = 5",
+		"UPDATE satpen SET telpon = '+62 291 123456', email = ' smk@nu.or.id ' WHERE id_satpen = 7",
+		"UPDATE satpen SET kepsek = '  ', telpon = '' WHERE id_satpen = 9",
+	}
+	for _, update := range updates {
+		if err := repo.db.Exec(update).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+var qualityNow = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// checkInGo runs quality.Check over every satpen matching filter, as the
+// reference for the SQL form of the rules
+func checkInGo(t *testing.T, repo *satpenRepository, filter *models.SatpenFilter, tapel string) map[uint]models.DataQuality {
+	t.Helper()
+	var satpen []models.Satpen
+	if err := repo.applyFilters(repo.qualityQuery(), filter).Find(&satpen).Error; err != nil {
+		t.Fatal(err)
+	}
+	records, err := repo.qualityRecords(satpen, tapel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make(map[uint]models.DataQuality)
+	for i := range records {
+		results[records[i].Satpen.IDSatpen] = quality.Check(&records[i], qualityNow)
+	}
+	return results
+}
+
+func TestQualityIssuesMatchRules(t *testing.T) {
+	repo := newQualityTestRepository(t)
+
+	tests := []struct {
+		name   string
+		filter *models.SatpenFilter
+		tapel  string
+		rules  []string
+		want   []uint // lowest score first
+	}{
+		{"every rule", nil, "20241", nil, []uint{3, 4, 5, 2, 9}},
+		{"selected rules", nil, "20241", []string{"email_invalid", "kepsek_empty"}, []uint{3, 2, 9}},
+		{"no tapel", nil, "", nil, []uint{3, 4, 5, 2, 9}},
+		{"filtered", &models.SatpenFilter{ProvinsiID: 2}, "20241", nil, []uint{5}},
+		{"other statuses", &models.SatpenFilter{Status: []string{"revisi", "proses dokumen"}}, "20241", []string{"pdptk_missing"}, []uint{8, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reference := checkInGo(t, repo, tt.filter, tt.tapel)
+
+			summary, err := repo.CountQualityIssues(tt.filter, tt.tapel, qualityNow, tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			records, err := repo.FindQualityIssues(tt.filter, tt.tapel, qualityNow, tt.rules, 1, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := qualityIDs(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindQualityIssues = %v, want %v", got, tt.want)
+			}
+
+			// The SQL summary and order agree with quality.Check
+			selected := make(map[string]bool)
+			for _, rule := range tt.rules {
+				selected[rule] = true
+			}
+			byRule := make(map[string]int64)
+			for _, rule := range quality.Rules {
+				byRule[rule.ID] = 0
+			}
+			var failing []uint
+			scores := 0
+			for id, result := range reference {
+				scores += result.Score
+				matched := false
+				for _, issue := range result.Issues {
+					byRule[issue.Rule]++
+					matched = matched || len(selected) == 0 || selected[issue.Rule]
+				}
+				if matched {
+					failing = append(failing, id)
+				}
+			}
+			sort.Slice(failing, func(i, j int) bool {
+				a, b := reference[failing[i]].Score, reference[failing[j]].Score
+				return a < b || (a == b && failing[i] < failing[j])
+			})
+
+			if !reflect.DeepEqual(qualityIDs(records), failing) {
+				t.Errorf("SQL order %v, Go order %v", qualityIDs(records), failing)
+			}
+			if summary.Checked != len(reference) || summary.WithIssues != len(failing) || summary.Tapel != tt.tapel {
+				t.Errorf("summary = %+v, want %d checked, %d with issues", summary, len(reference), len(failing))
+			}
+			if !reflect.DeepEqual(summary.ByRule, byRule) {
+				t.Errorf("by_rule = %v, want %v", summary.ByRule, byRule)
+			}
+			if want := models.Round2(float64(scores) / float64(len(reference))); summary.AverageScore != want {
+				t.Errorf("average_score = %v, want %v", summary.AverageScore, want)
+			}
+		})
+	}
+}
+
+func TestFindQualityIssuesPage(t *testing.T) {
+	repo := newQualityTestRepository(t)
+
+	records, err := repo.FindQualityIssues(nil, "20241", qualityNow, nil, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := qualityIDs(records), []uint{5, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("page 2 = %v, want %v", got, want)
+	}
+	// Records carry what quality.Check reads
+	if s := records[0].Satpen; s.Provinsi == nil || s.Kabupaten == nil || s.PengurusCabang == nil || records[0].PDPTK != nil {
+		t.Errorf("record = %+v", records[0])
+	}
+	if records[1].PDPTK == nil || records[1].PDPTK.Tapel != "20241" {
+		t.Errorf("satpen 2 PDPTK = %+v, want its 20241 row", records[1].PDPTK)
+	}
+
+	records, err = repo.FindQualityIssues(nil, "20241", qualityNow, nil, 4, 2)
+	if err != nil || len(records) != 0 {
+		t.Errorf("past the last page: %v, %v", qualityIDs(records), err)
+	}
+}
+
+func qualityIDs(records []quality.Record) []uint {
+	ids := []uint{}
+	for _, r := range records {
+		ids = append(ids, r.Satpen.IDSatpen)
+	}
+	return ids
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"satpen-api/internal/models"
+)
+
+// Region levels accepted by CountByRegion (provinsi, kabupaten) and
+// CountByPlace (kecamatan, kelurahan)
+const (
+	RegionProvinsi  = "provinsi"
+	RegionKabupaten = "kabupaten"
+	RegionKecamatan = "kecamatan"
+	RegionKelurahan = "kelurahan"
+)
+
+// ErrRegionLevel is returned for a level the method does not handle
+var ErrRegionLevel = errors.New("level must be provinsi or kabupaten")
+
+// latestPDPTK is the one rule for "the latest PDPTK of a satpen": the
+// condition that the PDPTK row alias has the highest tapel of its satpen. The
+// subquery is correlated, so it only reads that satpen's rows through
+// idx_pdptk_id_satpen_tapel. The preload, the jumlah_siswa filter, the sort
+// and the statistics are all built from it.
+func latestPDPTK(alias string) string {
+	return alias + ".tapel = (SELECT MAX(m.tapel) FROM pdptk m WHERE m.id_satpen = " + alias + ".id_satpen)"
+}
+
+// joinLatestPDPTK joins the latest PDPTK row of each satpen as alias, with
+// kind LEFT or INNER
+func joinLatestPDPTK(kind, alias string) string {
+	return kind + " JOIN pdptk " + alias + " ON " + alias + ".id_satpen = satpen.id_satpen AND " + latestPDPTK(alias)
+}
+
+// CountByRegion counts the satpen matching filter per provinsi or kabupaten,
+// with siswa/guru totals and the mean location of located satpen. Regions
+// without matching satpen are left out.
+func (r *satpenRepository) CountByRegion(level string, filter *models.SatpenFilter) ([]models.RegionCount, error) {
+	var results []models.RegionCount
+
+	const aggregates = "COUNT(*) as count, COUNT(satpen.lintang) as located, " +
+		"COALESCE(SUM(pdptk.jml_pd), 0) as siswa, COALESCE(SUM(pdptk.jml_guru), 0) as guru, " +
+		"AVG(satpen.lintang) as latitude, AVG(satpen.bujur) as longitude"
+
+	query := r.db.Table("satpen").
+		Joins(joinLatestPDPTK("LEFT", "pdptk"))
+
+	switch level {
+	case RegionProvinsi:
+		query = query.
+			Select("provinsi.id_prov as id, provinsi.kode_prov as kode, provinsi.map as map, provinsi.id_prov as id_prov, provinsi.nm_prov as nama, " + aggregates).
+			Joins("INNER JOIN provinsi ON provinsi.id_prov = satpen.id_prov").
+			Group("provinsi.id_prov, provinsi.kode_prov, provinsi.map, provinsi.nm_prov").
+			Order("provinsi.nm_prov ASC")
+	case RegionKabupaten:
+		query = query.
+			Select("kabupaten.id_kab as id, kabupaten.id_prov as id_prov, kabupaten.nama_kab as nama, " + aggregates).
+			Joins("INNER JOIN kabupaten ON kabupaten.id_kab = satpen.id_kab").
+			Group("kabupaten.id_kab, kabupaten.id_prov, kabupaten.nama_kab").
+			Order("kabupaten.nama_kab ASC")
+	default:
+		return nil, ErrRegionLevel
+	}
+
+	query = r.applyFilters(query, filter)
+
+	err := query.Scan(&results).Error
+	return results, err
+}
+
+// CountByPlace counts the satpen matching filter per raw kecamatan, or per
+// raw kecamatan and kelurahan, with siswa/guru totals. The names are free
+// text; callers merge their spelling variants.
+func (r *satpenRepository) CountByPlace(level string, filter *models.SatpenFilter) ([]models.PlaceCount, error) {
+	var results []models.PlaceCount
+
+	query := r.db.Table("satpen").
+		Joins(joinLatestPDPTK("LEFT", "pdptk"))
+
+	const aggregates = "COUNT(*) as count, COALESCE(SUM(pdptk.jml_pd), 0) as siswa, COALESCE(SUM(pdptk.jml_guru), 0) as guru"
+	switch level {
+	case RegionKecamatan:
+		query = query.Select("satpen.kecamatan as kecamatan, " + aggregates).
+			Group("satpen.kecamatan")
+	case RegionKelurahan:
+		query = query.Select("satpen.kecamatan as kecamatan, satpen.kelurahan as kelurahan, " + aggregates).
+			Group("satpen.kecamatan, satpen.kelurahan")
+	default:
+		return nil, ErrRegionLevel
+	}
+
+	query = r.applyFilters(query, filter)
+
+	err := query.Scan(&results).Error
+	return results, err
+}
@@ -1,21 +1,48 @@
 package repository
 
 import (
+	"satpen-api/internal/cluster"
+	"satpen-api/internal/duplicate"
 	"satpen-api/internal/models"
+	"satpen-api/internal/quality"
+	"satpen-api/internal/suggest"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SatpenRepository interface {
-	FindAll(filters map[string]interface{}, page, limit int, sort string) ([]models.Satpen, int64, error)
-	FindAllForExport(filters map[string]interface{}, sort string) ([]models.Satpen, error)
-	FindByID(id uint) (*models.Satpen, error)
-	FindByNPSN(npsn string) (*models.Satpen, error)
-	GetStatistics(filters map[string]interface{}) (*models.SatpenStatistics, error)
-	CountByJenjang(filters map[string]interface{}) ([]models.JenjangCount, error)
-	CountByAkreditasi(filters map[string]interface{}) ([]models.AkreditasiCount, error)
-	GetTopProvinsi(limit int) ([]models.ProvinsiStats, error)
+	FindAll(filter *models.SatpenFilter, page, limit int, sort string, proj *Projection) ([]models.Satpen, int64, error)
+	FindAllKeyset(filter *models.SatpenFilter, limit int, sort string, keyset *Keyset, proj *Projection) ([]models.Satpen, error)
+	Count(filter *models.SatpenFilter) (int64, error)
+	FindAllForExport(filter *models.SatpenFilter, sort string, proj *Projection) ([]models.Satpen, error)
+	FindByID(id uint, proj *Projection) (*models.Satpen, error)
+	FindByNPSN(npsn string, proj *Projection) (*models.Satpen, error)
+	FindNearby(lat, lng, radiusKm float64, limit int, filter *models.SatpenFilter, proj *Projection) ([]models.Satpen, error)
+	UpdateLocation(id uint, lat, lng *float64, entry *models.AuditLog) error
+	FindClusterPoints() ([]cluster.Point, error)
+	GetStatistics(filter *models.SatpenFilter, topN int) (*models.SatpenStatistics, error)
+	CountByJenjang(filter *models.SatpenFilter) ([]models.JenjangCount, error)
+	CountByAkreditasi(filter *models.SatpenFilter) ([]models.AkreditasiCount, error)
+	CountByRegion(level string, filter *models.SatpenFilter) ([]models.RegionCount, error)
+	CountByPlace(level string, filter *models.SatpenFilter) ([]models.PlaceCount, error)
+	CountByPeriod(event, interval string, filter *models.SatpenFilter, until time.Time, validYears int) ([]models.PeriodCount, error)
+	CountByJenjangStatus(filter *models.SatpenFilter) ([]models.JenjangStatusCount, error)
+	FindExpiring(filter *models.SatpenFilter, validYears int, from, until time.Time) ([]models.ExpiringSatpen, error)
+	CountPTK(filter *models.SatpenFilter, statusAjuan string) (int64, error)
+	FindIndikatorRows(groupBy string, filter *models.SatpenFilter) ([]models.IndikatorRow, error)
+	GetCurrentTapel() (string, error)
+	CountQualityIssues(filter *models.SatpenFilter, tapel string, now time.Time, rules []string) (*models.DataQualitySummary, error)
+	FindQualityIssues(filter *models.SatpenFilter, tapel string, now time.Time, rules []string, page, limit int) ([]quality.Record, error)
+	FindQualityRecord(id uint, tapel string) (*quality.Record, error)
+	FindDuplicateRecords(filter *models.SatpenFilter) ([]duplicate.Record, error)
+	MergeSatpen(survivorID, loserID uint, preview bool, entry *models.AuditLog) (*models.MergeResult, error)
+	GetTopProvinsi(filter *models.SatpenFilter, limit int) ([]models.ProvinsiStats, error)
+	GetTopKabupaten(filter *models.SatpenFilter, limit int) ([]models.KabupatenStats, error)
+	GetTopPengurusCabang(filter *models.SatpenFilter, limit int) ([]models.PengurusCabangStats, error)
+	FindSuggestDocuments() ([]suggest.Document, error)
 }
 
 type satpenRepository struct {
@@ -26,23 +53,14 @@ func NewSatpenRepository(db *gorm.DB) SatpenRepository {
 	return &satpenRepository{db: db}
 }
 
-func (r *satpenRepository) FindAll(filters map[string]interface{}, page, limit int, sort string) ([]models.Satpen, int64, error) {
+func (r *satpenRepository) FindAll(filter *models.SatpenFilter, page, limit int, sort string, proj *Projection) ([]models.Satpen, int64, error) {
 	var satpen []models.Satpen
 	var total int64
 
-	query := r.db.Model(&models.Satpen{}).
-		Preload("Provinsi").
-		Preload("Kabupaten").
-		Preload("Jenjang").
-		Preload("Kategori").
-		Preload("PengurusCabang").
-		Preload("PDPTK", func(db *gorm.DB) *gorm.DB {
-			// Get latest PDPTK data
-			return db.Order("tapel DESC").Limit(1)
-		})
+	query := r.preloadRelations(r.db.Model(&models.Satpen{}), proj)
 
 	// Apply filters
-	query = r.applyFilters(query, filters)
+	query = r.applyFilters(query, filter)
 
 	// Count total
 	if err := query.Count(&total).Error; err != nil {
@@ -53,130 +71,67 @@ func (r *satpenRepository) FindAll(filters map[string]interface{}, page, limit i
 	offset := (page - 1) * limit
 	query = query.Offset(offset).Limit(limit)
 
-	// Sorting
-	if sort != "" {
-		// Handle descending sort (prefix with -)
-		if strings.HasPrefix(sort, "-") {
-			sortField := strings.TrimPrefix(sort, "-")
-			// Map sort fields to database columns
-			sortField = r.mapSortField(sortField)
-			query = query.Order(sortField + " DESC")
-		} else {
-			sortField := r.mapSortField(sort)
-			query = query.Order(sortField + " ASC")
-		}
-	} else {
-		query = query.Order("satpen.created_at DESC")
-	}
+	// Columns, relevance score and sorting
+	query = r.applySelect(query, filter, proj)
+	query = r.applySort(query, sort, filter)
 
 	err := query.Find(&satpen).Error
 	return satpen, total, err
 }
 
-func (r *satpenRepository) FindAllForExport(filters map[string]interface{}, sort string) ([]models.Satpen, error) {
+func (r *satpenRepository) FindAllForExport(filter *models.SatpenFilter, sort string, proj *Projection) ([]models.Satpen, error) {
 	var satpen []models.Satpen
 
-	query := r.db.Model(&models.Satpen{}).
-		Preload("Provinsi").
-		Preload("Kabupaten").
-		Preload("Jenjang").
-		Preload("Kategori").
-		Preload("PengurusCabang").
-		Preload("PDPTK", func(db *gorm.DB) *gorm.DB {
-			return db.Order("tapel DESC").Limit(1)
-		})
-
-	query = r.applyFilters(query, filters)
-
-	if sort != "" {
-		if strings.HasPrefix(sort, "-") {
-			sortField := r.mapSortField(strings.TrimPrefix(sort, "-"))
-			query = query.Order(sortField + " DESC")
-		} else {
-			query = query.Order(r.mapSortField(sort) + " ASC")
-		}
-	} else {
-		query = query.Order("satpen.created_at DESC")
-	}
+	query := r.preloadRelations(r.db.Model(&models.Satpen{}), proj)
+
+	query = r.applyFilters(query, filter)
+	query = r.applySelect(query, filter, proj)
+	query = r.applySort(query, sort, filter)
 
 	err := query.Find(&satpen).Error
 	return satpen, err
 }
 
-func (r *satpenRepository) FindByID(id uint) (*models.Satpen, error) {
-	var satpen models.Satpen
-	err := r.db.Preload("Provinsi").
-		Preload("Kabupaten").
-		Preload("Jenjang").
-		Preload("Kategori").
-		Preload("PengurusCabang").
-		Preload("PDPTK", func(db *gorm.DB) *gorm.DB {
-			return db.Order("tapel DESC").Limit(1)
-		}).
-		First(&satpen, id).Error
-
-	if err != nil {
-		return nil, err
-	}
-	return &satpen, nil
+func (r *satpenRepository) FindByID(id uint, proj *Projection) (*models.Satpen, error) {
+	return r.findOne(proj, id)
 }
 
-func (r *satpenRepository) FindByNPSN(npsn string) (*models.Satpen, error) {
-	var satpen models.Satpen
-	err := r.db.Preload("Provinsi").
-		Preload("Kabupaten").
-		Preload("Jenjang").
-		Preload("Kategori").
-		Preload("PengurusCabang").
-		Preload("PDPTK", func(db *gorm.DB) *gorm.DB {
-			return db.Order("tapel DESC").Limit(1)
-		}).
-		Where("npsn = ?", npsn).
-		First(&satpen).Error
-
-	if err != nil {
-		return nil, err
-	}
-	return &satpen, nil
+func (r *satpenRepository) FindByNPSN(npsn string, proj *Projection) (*models.Satpen, error) {
+	return r.findOne(proj, "npsn = ?", npsn)
 }
 
-func (r *satpenRepository) GetStatistics(filters map[string]interface{}) (*models.SatpenStatistics, error) {
+// GetStatistics summarises the satpen matching filter. Every figure, the top
+// lists of topN entries included, applies the same filter.
+func (r *satpenRepository) GetStatistics(filter *models.SatpenFilter, topN int) (*models.SatpenStatistics, error) {
 	stats := &models.SatpenStatistics{}
 
-	query := r.db.Model(&models.Satpen{})
-	query = r.applyFilters(query, filters)
-
-	// Total satpen
-	if err := query.Count(&stats.TotalSatpen).Error; err != nil {
-		return nil, err
+	// Totals, with siswa and guru from the latest PDPTK of each satpen
+	var totals struct {
+		TotalSatpen         int64
+		TotalProvinsi       int64
+		TotalKabupaten      int64
+		TotalPengurusCabang int64
+		TotalSiswa          int64
+		TotalGuru           int64
 	}
-
-	// Total siswa and guru from PDPTK (latest data)
-	var sums struct {
-		TotalSiswa int64
-		TotalGuru  int64
-	}
-
-	subQuery := r.db.Table("pdptk").
-		Select("id_satpen, MAX(tapel) as max_tapel").
-		Group("id_satpen")
-
-	if err := r.db.Table("pdptk").
-		Select("COALESCE(SUM(jml_pd), 0) as total_siswa, COALESCE(SUM(jml_guru), 0) as total_guru").
-		Joins("INNER JOIN (?) as latest ON pdptk.id_satpen = latest.id_satpen AND pdptk.tapel = latest.max_tapel", subQuery).
-		Scan(&sums).Error; err != nil {
-		return nil, err
-	}
-	stats.TotalSiswa = sums.TotalSiswa
-	stats.TotalGuru = sums.TotalGuru
-
-	// Total provinsi
-	if err := r.db.Model(&models.Provinsi{}).Count(&stats.TotalProvinsi).Error; err != nil {
+	query := r.db.Table("satpen").
+		Select("COUNT(*) as total_satpen, COUNT(DISTINCT satpen.id_prov) as total_provinsi, " +
+			"COUNT(DISTINCT satpen.id_kab) as total_kabupaten, COUNT(DISTINCT satpen.id_pc) as total_pengurus_cabang, " +
+			"COALESCE(SUM(pdptk.jml_pd), 0) as total_siswa, COALESCE(SUM(pdptk.jml_guru), 0) as total_guru").
+		Joins(joinLatestPDPTK("LEFT", "pdptk"))
+	query = r.applyFilters(query, filter)
+	if err := query.Scan(&totals).Error; err != nil {
 		return nil, err
 	}
+	stats.TotalSatpen = totals.TotalSatpen
+	stats.TotalProvinsi = totals.TotalProvinsi
+	stats.TotalKabupaten = totals.TotalKabupaten
+	stats.TotalPengurusCabang = totals.TotalPengurusCabang
+	stats.TotalSiswa = totals.TotalSiswa
+	stats.TotalGuru = totals.TotalGuru
 
 	// By Jenjang
-	jenjangCounts, err := r.CountByJenjang(filters)
+	jenjangCounts, err := r.CountByJenjang(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -190,133 +145,241 @@ func (r *satpenRepository) GetStatistics(filters map[string]interface{}) (*model
 	}
 
 	// By Akreditasi (based on kategori)
-	akreditasiCounts, err := r.CountByAkreditasi(filters)
+	akreditasiCounts, err := r.CountByAkreditasi(filter)
 	if err != nil {
 		return nil, err
 	}
 	stats.ByAkreditasi = make(map[string]int64)
 	for _, ac := range akreditasiCounts {
-		stats.ByAkreditasi[ac.Akreditasi] = ac.Count
+		stats.ByAkreditasi[ac.Label()] = ac.Count
 	}
 
-	// Top Provinsi
-	topProvinsi, err := r.GetTopProvinsi(5)
-	if err != nil {
+	// Top lists
+	if stats.TopProvinsi, err = r.GetTopProvinsi(filter, topN); err != nil {
+		return nil, err
+	}
+	if stats.TopKabupaten, err = r.GetTopKabupaten(filter, topN); err != nil {
+		return nil, err
+	}
+	if stats.TopPengurusCabang, err = r.GetTopPengurusCabang(filter, topN); err != nil {
 		return nil, err
 	}
-	stats.TopProvinsi = topProvinsi
 
 	return stats, nil
 }
 
-func (r *satpenRepository) CountByJenjang(filters map[string]interface{}) ([]models.JenjangCount, error) {
+func (r *satpenRepository) CountByJenjang(filter *models.SatpenFilter) ([]models.JenjangCount, error) {
 	var results []models.JenjangCount
 
 	query := r.db.Table("satpen").
 		Select("jenjang_pendidikan.nm_jenjang as jenjang, COUNT(*) as count, COALESCE(SUM(pdptk.jml_pd), 0) as siswa, COALESCE(SUM(pdptk.jml_guru), 0) as guru").
 		Joins("INNER JOIN jenjang_pendidikan ON jenjang_pendidikan.id_jenjang = satpen.id_jenjang").
-		Joins("LEFT JOIN (SELECT id_satpen, jml_pd, jml_guru FROM pdptk WHERE (id_satpen, tapel) IN (SELECT id_satpen, MAX(tapel) FROM pdptk GROUP BY id_satpen)) as pdptk ON pdptk.id_satpen = satpen.id_satpen").
+		Joins(joinLatestPDPTK("LEFT", "pdptk")).
 		Group("jenjang_pendidikan.id_jenjang, jenjang_pendidikan.nm_jenjang")
 
-	query = r.applyFilters(query, filters)
+	query = r.applyFilters(query, filter)
 
 	err := query.Scan(&results).Error
 	return results, err
 }
 
-func (r *satpenRepository) CountByAkreditasi(filters map[string]interface{}) ([]models.AkreditasiCount, error) {
+func (r *satpenRepository) CountByAkreditasi(filter *models.SatpenFilter) ([]models.AkreditasiCount, error) {
 	var results []models.AkreditasiCount
 
 	query := r.db.Table("satpen").
-		Select("COALESCE(kategori_satpen.nm_kategori, 'Belum Terakreditasi') as akreditasi, COUNT(*) as count").
+		Select("COALESCE(kategori_satpen.nm_kategori, 'Belum Terakreditasi') as akreditasi, COALESCE(kategori_satpen.konotasi, '') as konotasi, COUNT(*) as count").
 		Joins("LEFT JOIN kategori_satpen ON kategori_satpen.id_kategori = satpen.id_kategori").
-		Group("kategori_satpen.nm_kategori")
+		Group("kategori_satpen.nm_kategori, kategori_satpen.konotasi")
 
-	query = r.applyFilters(query, filters)
+	query = r.applyFilters(query, filter)
 
 	err := query.Scan(&results).Error
 	return results, err
 }
 
-func (r *satpenRepository) GetTopProvinsi(limit int) ([]models.ProvinsiStats, error) {
-	var results []models.ProvinsiStats
+func (r *satpenRepository) GetTopProvinsi(filter *models.SatpenFilter, limit int) ([]models.ProvinsiStats, error) {
+	results := []models.ProvinsiStats{}
+	err := r.countTop(&results, filter, limit, "provinsi.id_prov as id, provinsi.nm_prov as provinsi",
+		"INNER JOIN provinsi ON provinsi.id_prov = satpen.id_prov", "provinsi.id_prov, provinsi.nm_prov")
+	return results, err
+}
 
-	err := r.db.Table("satpen").
-		Select("provinsi.nm_prov as provinsi, COUNT(*) as count").
-		Joins("INNER JOIN provinsi ON provinsi.id_prov = satpen.id_prov").
-		Group("provinsi.id_prov, provinsi.nm_prov").
-		Order("count DESC").
-		Limit(limit).
-		Scan(&results).Error
+func (r *satpenRepository) GetTopKabupaten(filter *models.SatpenFilter, limit int) ([]models.KabupatenStats, error) {
+	results := []models.KabupatenStats{}
+	err := r.countTop(&results, filter, limit, "kabupaten.id_kab as id, kabupaten.nama_kab as kabupaten",
+		"INNER JOIN kabupaten ON kabupaten.id_kab = satpen.id_kab", "kabupaten.id_kab, kabupaten.nama_kab")
+	return results, err
+}
 
+func (r *satpenRepository) GetTopPengurusCabang(filter *models.SatpenFilter, limit int) ([]models.PengurusCabangStats, error) {
+	results := []models.PengurusCabangStats{}
+	err := r.countTop(&results, filter, limit, "pengurus_cabang.id_pc as id, pengurus_cabang.nama_pc as pengurus_cabang",
+		"INNER JOIN pengurus_cabang ON pengurus_cabang.id_pc = satpen.id_pc", "pengurus_cabang.id_pc, pengurus_cabang.nama_pc")
 	return results, err
 }
 
-func (r *satpenRepository) applyFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+// countTop scans into dest the limit groups with the most satpen matching
+// filter, with their siswa/guru totals. Ties are ordered by group.
+func (r *satpenRepository) countTop(dest interface{}, filter *models.SatpenFilter, limit int, columns, join, group string) error {
+	query := r.db.Table("satpen").
+		Select(columns + ", COUNT(*) as count, COALESCE(SUM(pdptk.jml_pd), 0) as siswa, COALESCE(SUM(pdptk.jml_guru), 0) as guru").
+		Joins(join).
+		Joins(joinLatestPDPTK("LEFT", "pdptk")).
+		Group(group).
+		Order("count DESC, " + group).
+		Limit(limit)
+
+	query = r.applyFilters(query, filter)
+
+	return query.Scan(dest).Error
+}
+
+// FindSuggestDocuments returns the names of all listed satpen (same default
+// status set as applyFilters) for the autocomplete index
+func (r *satpenRepository) FindSuggestDocuments() ([]suggest.Document, error) {
+	var docs []suggest.Document
+
+	err := r.db.Table("satpen").
+		Select("satpen.id_satpen as id, satpen.npsn, satpen.nm_satpen as name, jenjang_pendidikan.nm_jenjang as jenjang, kabupaten.nama_kab as kabupaten").
+		Joins("LEFT JOIN jenjang_pendidikan ON jenjang_pendidikan.id_jenjang = satpen.id_jenjang").
+		Joins("LEFT JOIN kabupaten ON kabupaten.id_kab = satpen.id_kab").
+		Where("satpen.status IN (?)", models.ListedSatpenStatuses).
+		Scan(&docs).Error
+
+	return docs, err
+}
+
+func (r *satpenRepository) applyFilters(query *gorm.DB, filter *models.SatpenFilter) *gorm.DB {
+	if filter == nil {
+		filter = &models.SatpenFilter{}
+	}
+
 	// Filter by jenjang (by name)
-	if jenjang, ok := filters["jenjang"].(string); ok && jenjang != "" {
-		query = query.Where("satpen.id_jenjang IN (SELECT id_jenjang FROM jenjang_pendidikan WHERE nm_jenjang = ?)", jenjang)
+	if len(filter.Jenjang) > 0 {
+		query = query.Where("satpen.id_jenjang IN (SELECT id_jenjang FROM jenjang_pendidikan WHERE nm_jenjang IN ?)", filter.Jenjang)
 	}
 
-	// Filter by provinsi (by name or ID)
-	if provinsi, ok := filters["provinsi"].(string); ok && provinsi != "" {
-		query = query.Where("satpen.id_prov IN (SELECT id_prov FROM provinsi WHERE nm_prov LIKE ? OR id_prov = ?)", "%"+provinsi+"%", provinsi)
+	// Filter by provinsi, kabupaten and pengurus cabang (by name or ID)
+	if len(filter.Provinsi) > 0 {
+		query = query.Where(nameOrIDIn("satpen.id_prov", "provinsi", "id_prov", "nm_prov", filter.Provinsi))
+	}
+	if len(filter.Kabupaten) > 0 {
+		query = query.Where(nameOrIDIn("satpen.id_kab", "kabupaten", "id_kab", "nama_kab", filter.Kabupaten))
+	}
+	if len(filter.PengurusCabang) > 0 {
+		query = query.Where(nameOrIDIn("satpen.id_pc", "pengurus_cabang", "id_pc", "nama_pc", filter.PengurusCabang))
+	}
+	if filter.ProvinsiID > 0 {
+		query = query.Where("satpen.id_prov = ?", filter.ProvinsiID)
+	}
+	if filter.KabupatenID > 0 {
+		query = query.Where("satpen.id_kab = ?", filter.KabupatenID)
+	}
+	if filter.PengurusCabangID > 0 {
+		query = query.Where("satpen.id_pc = ?", filter.PengurusCabangID)
 	}
 
-	// Filter by kabupaten (by name or ID)
-	if kabupaten, ok := filters["kabupaten"].(string); ok && kabupaten != "" {
-		query = query.Where("satpen.id_kab IN (SELECT id_kab FROM kabupaten WHERE nama_kab LIKE ? OR id_kab = ?)", "%"+kabupaten+"%", kabupaten)
+	if len(filter.Kecamatan) > 0 {
+		query = query.Where("satpen.kecamatan IN ?", filter.Kecamatan)
+	}
+	if len(filter.Kelurahan) > 0 {
+		query = query.Where("satpen.kelurahan IN ?", filter.Kelurahan)
+	}
+	if filter.Yayasan != "" {
+		query = query.Where("satpen.yayasan LIKE ?", "%"+filter.Yayasan+"%")
 	}
 
-	// Search by name or address
-	if search, ok := filters["search"].(string); ok && search != "" {
-		searchTerm := "%" + search + "%"
+	// Search: FULLTEXT (natural/boolean mode) or LIKE on name and address
+	if expr, term, ok := fulltextMatch(filter); ok {
+		query = query.Where(expr, term)
+	} else if filter.Search != "" {
+		searchTerm := "%" + filter.Search + "%"
 		query = query.Where("satpen.nm_satpen LIKE ? OR satpen.alamat LIKE ?", searchTerm, searchTerm)
 	}
 
 	// Filter by akreditasi (kategori)
-	if akreditasi, ok := filters["akreditasi"].(string); ok && akreditasi != "" {
-		query = query.Where("satpen.id_kategori IN (SELECT id_kategori FROM kategori_satpen WHERE nm_kategori = ?)", akreditasi)
+	if len(filter.Akreditasi) > 0 {
+		query = query.Where("satpen.id_kategori IN (SELECT id_kategori FROM kategori_satpen WHERE nm_kategori IN ?)", filter.Akreditasi)
 	}
 
-	// Filter by status (default: setujui = aktif)
-	if status, ok := filters["status"].(string); ok && status != "" {
-		if status == "aktif" {
-			query = query.Where("satpen.status = ?", "setujui")
-		} else if status == "non-aktif" {
-			query = query.Where("satpen.status IN (?)", []string{"expired", "revisi", "permohonan"})
-		} else {
-			query = query.Where("satpen.status = ?", status)
-		}
+	// Filter by status (default: setujui, expired and perpanjangan)
+	if len(filter.Status) > 0 {
+		query = query.Where("satpen.status IN ?", expandStatuses(filter.Status))
 	} else {
-		// Default: only show approved (aktif)
-		query = query.Where("satpen.status IN (?)", []string{"setujui", "expired", "perpanjangan"})
+		query = query.Where("satpen.status IN (?)", models.ListedSatpenStatuses)
 	}
 
 	// Filter by verified
-	if verified, ok := filters["verified"].(bool); ok {
-		if verified {
+	if filter.Verified != nil {
+		if *filter.Verified {
 			query = query.Where("satpen.status = ?", "setujui")
 		} else {
 			query = query.Where("satpen.status != ?", "setujui")
 		}
 	}
 
+	if filter.HasLocation != nil {
+		if *filter.HasLocation {
+			query = query.Where("satpen.lintang IS NOT NULL AND satpen.bujur IS NOT NULL")
+		} else {
+			query = query.Where("(satpen.lintang IS NULL OR satpen.bujur IS NULL)")
+		}
+	}
+
+	// Ranges
+	if filter.TahunBerdiriMin != nil {
+		query = query.Where("satpen.thn_berdiri >= ?", *filter.TahunBerdiriMin)
+	}
+	if filter.TahunBerdiriMax != nil {
+		query = query.Where("satpen.thn_berdiri <= ?", *filter.TahunBerdiriMax)
+	}
+	if filter.TglRegistrasiFrom != nil {
+		query = query.Where("satpen.tgl_registrasi >= ?", *filter.TglRegistrasiFrom)
+	}
+	if filter.TglRegistrasiTo != nil {
+		// The whole "to" day is included
+		query = query.Where("satpen.tgl_registrasi < ?", filter.TglRegistrasiTo.AddDate(0, 0, 1))
+	}
+
+	// Jumlah siswa from the latest PDPTK; satpen without PDPTK data count as 0
+	if filter.JumlahSiswaMin != nil || filter.JumlahSiswaMax != nil {
+		siswa := "COALESCE((SELECT latest.jml_pd FROM pdptk latest WHERE latest.id_satpen = satpen.id_satpen AND " + latestPDPTK("latest") + " LIMIT 1), 0)"
+		if filter.JumlahSiswaMin != nil {
+			query = query.Where(siswa+" >= ?", *filter.JumlahSiswaMin)
+		}
+		if filter.JumlahSiswaMax != nil {
+			query = query.Where(siswa+" <= ?", *filter.JumlahSiswaMax)
+		}
+	}
+
 	return query
 }
 
-func (r *satpenRepository) mapSortField(field string) string {
-	// Map API sort fields to database columns
-	mapping := map[string]string{
-		"nama":          "satpen.nm_satpen",
-		"jumlah_siswa":  "pdptk.jml_pd",
-		"tahun_berdiri": "satpen.thn_berdiri",
-		"created_at":    "satpen.created_at",
-		"updated_at":    "satpen.updated_at",
+// nameOrIDIn matches column against the rows of a master table whose ID equals
+// one of values or whose name contains one of them
+func nameOrIDIn(column, table, idColumn, nameColumn string, values []string) clause.Expr {
+	conds := make([]string, 0, len(values))
+	args := make([]interface{}, 0, 2*len(values))
+	for _, value := range values {
+		conds = append(conds, nameColumn+" LIKE ? OR "+idColumn+" = ?")
+		args = append(args, "%"+value+"%", value)
 	}
+	sql := column + " IN (SELECT " + idColumn + " FROM " + table + " WHERE " + strings.Join(conds, " OR ") + ")"
+	return gorm.Expr(sql, args...)
+}
 
-	if mapped, ok := mapping[field]; ok {
-		return mapped
+// expandStatuses maps the aliases aktif and non-aktif to raw statuses
+func expandStatuses(statuses []string) []string {
+	expanded := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		switch status {
+		case "aktif":
+			expanded = append(expanded, "setujui")
+		case "non-aktif":
+			expanded = append(expanded, "expired", "revisi", "permohonan")
+		default:
+			expanded = append(expanded, status)
+		}
 	}
-	return "satpen." + field
+	return expanded
 }
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"satpen-api/internal/models"
+)
+
+// listed are the fixture satpen with a status listed by default
+var listed = []uint{1, 2, 3, 4, 5, 7, 9}
+
+func TestApplyFilters(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	tests := []struct {
+		name   string
+		filter *models.SatpenFilter
+		want   []uint
+	}{
+		{"nil filter lists setujui, expired and perpanjangan", nil, listed},
+		{"jenjang by name", &models.SatpenFilter{Jenjang: []string{"MI"}}, []uint{1, 4}},
+		{"jenjang ignores case", &models.SatpenFilter{Jenjang: []string{"mi", "SMK"}}, []uint{1, 3, 4, 7}},
+		{"provinsi by name", &models.SatpenFilter{Provinsi: []string{"Jawa Tengah"}}, []uint{5, 7}},
+		{"provinsi by ID", &models.SatpenFilter{Provinsi: []string{"2"}}, []uint{5, 7}},
+		{"provinsi by part of the name", &models.SatpenFilter{Provinsi: []string{"jawa"}}, listed},
+		{"kabupaten by name", &models.SatpenFilter{Kabupaten: []string{"Malang"}}, []uint{1, 2, 9}},
+		{"kabupaten by name or ID", &models.SatpenFilter{Kabupaten: []string{"Malang", "3"}}, []uint{1, 2, 5, 7, 9}},
+		{"pengurus cabang by name", &models.SatpenFilter{PengurusCabang: []string{"Surabaya"}}, []uint{3, 4}},
+		{"provinsi ID", &models.SatpenFilter{ProvinsiID: 1}, []uint{1, 2, 3, 4, 9}},
+		{"kabupaten ID", &models.SatpenFilter{KabupatenID: 3}, []uint{5, 7}},
+		{"pengurus cabang ID", &models.SatpenFilter{PengurusCabangID: 2}, []uint{3, 4}},
+		{"kecamatan", &models.SatpenFilter{Kecamatan: []string{"Lawang"}}, []uint{2, 9}},
+		{"kelurahan", &models.SatpenFilter{Kelurahan: []string{"Jagir", "Demaan"}}, []uint{3, 5}},
+		{"yayasan", &models.SatpenFilter{Yayasan: "ma'arif kudus"}, []uint{5, 7}},
+		{"search name", &models.SatpenFilter{Search: "Ma'arif"}, []uint{1, 3, 5}},
+		{"search alamat", &models.SatpenFilter{Search: "wahid hasyim", SearchMode: SearchModeLike}, []uint{7}},
+		{"akreditasi", &models.SatpenFilter{Akreditasi: []string{"A"}}, []uint{1, 3, 9}},
+		{"akreditasi any of", &models.SatpenFilter{Akreditasi: []string{"B", "C"}}, []uint{2, 5, 7}},
+		{"status aktif", &models.SatpenFilter{Status: []string{"aktif"}}, []uint{1, 2, 5, 9}},
+		{"status non-aktif", &models.SatpenFilter{Status: []string{"non-aktif"}}, []uint{3, 6, 7, 8}},
+		{"raw status", &models.SatpenFilter{Status: []string{"proses dokumen", "perpanjangan"}}, []uint{4, 10}},
+		{"verified", &models.SatpenFilter{Verified: boolPtr(true)}, []uint{1, 2, 5, 9}},
+		{"not verified", &models.SatpenFilter{Verified: boolPtr(false)}, []uint{3, 4, 7}},
+		{"has location", &models.SatpenFilter{HasLocation: boolPtr(true)}, []uint{1, 2, 4, 5, 9}},
+		{"without location", &models.SatpenFilter{HasLocation: boolPtr(false)}, []uint{3, 7}},
+		{"tahun berdiri min", &models.SatpenFilter{TahunBerdiriMin: intPtr(2000)}, []uint{3, 4, 7}},
+		{"tahun berdiri max", &models.SatpenFilter{TahunBerdiriMax: intPtr(1990)}, []uint{1, 2, 5}},
+		{"registered from", &models.SatpenFilter{TglRegistrasiFrom: datePtr(2021, 1, 1)}, []uint{2, 4, 5, 9}},
+		{"registered to includes the whole day", &models.SatpenFilter{TglRegistrasiTo: datePtr(2021, 5, 3)}, []uint{1, 2, 3, 7}},
+		{"registered between", &models.SatpenFilter{TglRegistrasiFrom: datePtr(2021, 1, 1), TglRegistrasiTo: datePtr(2021, 12, 31)}, []uint{2, 5}},
+		{"jumlah siswa of the latest pdptk", &models.SatpenFilter{JumlahSiswaMin: intPtr(300)}, []uint{2, 3, 7}},
+		{"satpen without pdptk have 0 siswa", &models.SatpenFilter{JumlahSiswaMax: intPtr(200)}, []uint{1, 4}},
+		{
+			"filters combine",
+			&models.SatpenFilter{Jenjang: []string{"MTs"}, Provinsi: []string{"Jawa Timur"}, Verified: boolPtr(true)},
+			[]uint{2, 9},
+		},
+		{"no match", &models.SatpenFilter{Kecamatan: []string{"Depok"}}, []uint{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			satpen, err := repo.FindAllForExport(tt.filter, "", &Projection{})
+			if err != nil {
+				t.Fatalf("FindAllForExport: %v", err)
+			}
+			if got := sortedIDs(satpen); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			count, err := repo.Count(tt.filter)
+			if err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+			if count != int64(len(tt.want)) {
+				t.Errorf("Count = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	tests := []struct {
+		sort string
+		want []uint
+	}{
+		{"", []uint{9, 4, 5, 2, 1, 3, 7}}, // -created_at
+		{"nama", []uint{1, 4, 9, 5, 2, 3, 7}},
+		{"-npsn", []uint{9, 7, 5, 4, 3, 2, 1}},
+		{"tahun_berdiri", []uint{5, 1, 2, 9, 7, 3, 4}},
+		{"tgl_registrasi", []uint{7, 3, 1, 2, 5, 4, 9}},
+		{"-jumlah_siswa", []uint{7, 3, 2, 5, 9, 1, 4}},
+		{"jumlah_guru", []uint{4, 1, 9, 5, 2, 3, 7}},
+		{"provinsi,-tahun_berdiri", []uint{7, 5, 4, 3, 9, 2, 1}},
+		{"kabupaten,npsn", []uint{5, 7, 1, 2, 9, 3, 4}},
+		{"akreditasi,nama", []uint{4, 1, 9, 3, 2, 7, 5}}, // no kategori first
+		{"jenjang", []uint{1, 4, 2, 5, 9, 3, 7}},         // ties by id
+		{"-jenjang,-jumlah_siswa", []uint{7, 3, 2, 5, 9, 1, 4}},
+		{"relevance", []uint{1, 2, 3, 4, 5, 7, 9}}, // ignored without fulltext search
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			satpen, err := repo.FindAllForExport(nil, tt.sort, &Projection{Columns: []string{"nm_satpen"}})
+			if err != nil {
+				t.Fatalf("FindAllForExport: %v", err)
+			}
+			if got := satpenIDs(satpen); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid field", func(t *testing.T) {
+		_, err := repo.FindAllForExport(nil, "nama,bogus", nil)
+		if !errors.Is(err, ErrSortField) {
+			t.Errorf("err = %v, want ErrSortField", err)
+		}
+	})
+}
+
+func TestFindAllPagination(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	// Sorted by nama: 1, 4, 9, 5, 2, 3, 7
+	tests := []struct {
+		page, limit int
+		want        []uint
+	}{
+		{1, 3, []uint{1, 4, 9}},
+		{2, 3, []uint{5, 2, 3}},
+		{3, 3, []uint{7}},
+		{4, 3, []uint{}},
+		{1, 10, []uint{1, 4, 9, 5, 2, 3, 7}},
+	}
+
+	for _, tt := range tests {
+		satpen, total, err := repo.FindAll(nil, tt.page, tt.limit, "nama", nil)
+		if err != nil {
+			t.Fatalf("page %d: %v", tt.page, err)
+		}
+		if total != 7 {
+			t.Errorf("page %d: total = %d, want 7", tt.page, total)
+		}
+		if got := satpenIDs(satpen); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("page %d of %d: got %v, want %v", tt.page, tt.limit, got, tt.want)
+		}
+	}
+
+	t.Run("total applies the filter", func(t *testing.T) {
+		satpen, total, err := repo.FindAll(&models.SatpenFilter{ProvinsiID: 1}, 2, 2, "npsn", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 5 {
+			t.Errorf("total = %d, want 5", total)
+		}
+		if got, want := satpenIDs(satpen), []uint{3, 4}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("relations are preloaded", func(t *testing.T) {
+		satpen, _, err := repo.FindAll(nil, 1, 10, "nama", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(satpen) != 7 {
+			t.Fatalf("got %d satpen, want the whole listing on one page", len(satpen))
+		}
+
+		// Every row gets its own latest PDPTK; satpen 1 also has an older
+		// 20231 row and satpen 4 has none
+		want := map[uint][2]uint{1: {180, 12}, 2: {320, 20}, 3: {410, 30}, 4: {0, 0}, 5: {260, 18}, 7: {500, 35}, 9: {210, 14}}
+		for _, s := range satpen {
+			if got := [2]uint{s.JumlahSiswa, s.JumlahGuru}; got != want[s.IDSatpen] {
+				t.Errorf("satpen %d: siswa/guru = %v, want %v", s.IDSatpen, got, want[s.IDSatpen])
+			}
+			if (s.PDPTK == nil) != (s.IDSatpen == 4) {
+				t.Errorf("satpen %d: PDPTK = %+v", s.IDSatpen, s.PDPTK)
+			}
+		}
+
+		s := satpen[0]
+		if s.Provinsi == nil || s.Provinsi.NmProv != "Jawa Timur" {
+			t.Errorf("Provinsi = %+v", s.Provinsi)
+		}
+		if s.Jenjang == nil || s.Jenjang.NmJenjang != "MI" {
+			t.Errorf("Jenjang = %+v", s.Jenjang)
+		}
+		if s.Akreditasi != "A" || !s.IsVerified {
+			t.Errorf("Akreditasi = %q, IsVerified = %v", s.Akreditasi, s.IsVerified)
+		}
+		if s.Coordinates == nil || s.Coordinates.Latitude != -7.89 {
+			t.Errorf("Coordinates = %+v", s.Coordinates)
+		}
+	})
+
+	t.Run("projection", func(t *testing.T) {
+		satpen, _, err := repo.FindAll(nil, 1, 1, "nama", &Projection{Columns: []string{"npsn", "id_kab"}, Preloads: []string{"Kabupaten"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := satpen[0]
+		if s.NPSN != "20507001" || s.NmSatpen != "" {
+			t.Errorf("NPSN = %q, NmSatpen = %q: want only the projected columns", s.NPSN, s.NmSatpen)
+		}
+		if s.Kabupaten == nil || s.Provinsi != nil {
+			t.Errorf("Kabupaten = %+v, Provinsi = %+v: want only the Kabupaten preload", s.Kabupaten, s.Provinsi)
+		}
+	})
+}
+
+func TestFindAllKeyset(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+	proj := &Projection{Columns: []string{"nm_satpen", "thn_berdiri"}}
+
+	// Walks the pages of sort and returns the IDs of every page
+	walk := func(sort string, limit int) [][]uint {
+		var pages [][]uint
+		var keyset *Keyset
+		for {
+			satpen, err := repo.FindAllKeyset(nil, limit, sort, keyset, proj)
+			if err != nil {
+				t.Fatalf("FindAllKeyset(%q): %v", sort, err)
+			}
+			if len(satpen) == 0 {
+				return pages
+			}
+			pages = append(pages, satpenIDs(satpen))
+			last := &satpen[len(satpen)-1]
+			value, err := KeysetValue(sort, last)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keyset = &Keyset{Value: value, ID: last.IDSatpen}
+		}
+	}
+
+	if got, want := walk("nama", 3), [][]uint{{1, 4, 9}, {5, 2, 3}, {7}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nama: got %v, want %v", got, want)
+	}
+	if got, want := walk("-tahun_berdiri", 4), [][]uint{{4, 3, 7, 9}, {2, 1, 5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("-tahun_berdiri: got %v, want %v", got, want)
+	}
+
+	t.Run("backward", func(t *testing.T) {
+		// The page before MTs Ma'arif Kudus (5), still in sort order
+		satpen, err := repo.FindAllKeyset(nil, 2, "nama", &Keyset{Value: "MTs Ma'arif Kudus", ID: 5, Backward: true}, proj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := satpenIDs(satpen), []uint{4, 9}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unsupported sort", func(t *testing.T) {
+		if _, err := repo.FindAllKeyset(nil, 2, "provinsi", nil, proj); !errors.Is(err, ErrKeysetSort) {
+			t.Errorf("err = %v, want ErrKeysetSort", err)
+		}
+	})
+}
+
+func TestFindByIDAndNPSN(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	satpen, err := repo.FindByID(5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if satpen.NPSN != "20507005" || satpen.Kabupaten == nil || satpen.Kabupaten.NamaKab != "Kabupaten Kudus" {
+		t.Errorf("FindByID(5) = %+v", satpen)
+	}
+	// The latest PDPTK is preloaded
+	if satpen.JumlahSiswa != 260 || satpen.JumlahGuru != 18 {
+		t.Errorf("JumlahSiswa = %d, JumlahGuru = %d, want 260 and 18", satpen.JumlahSiswa, satpen.JumlahGuru)
+	}
+
+	satpen, err = repo.FindByNPSN("20507009", &Projection{Columns: []string{"nm_satpen"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if satpen.IDSatpen != 9 || satpen.NmSatpen != "MTs Islamiyah Lawang" {
+		t.Errorf("FindByNPSN = %+v", satpen)
+	}
+
+	// The service maps this error to 404 by its message
+	if _, err := repo.FindByID(99, nil); err == nil || err.Error() != "record not found" {
+		t.Errorf("FindByID(99) err = %v, want record not found", err)
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"satpen-api/internal/models"
+	"strings"
+	"unicode"
+)
+
+// Search modes accepted in SatpenFilter.SearchMode
+const (
+	SearchModeLike    = "like"
+	SearchModeNatural = "natural"
+	SearchModeBoolean = "boolean"
+)
+
+// fulltextColumns must match the column list of idx_satpen_fulltext
+const fulltextColumns = "satpen.nm_satpen, satpen.alamat, satpen.yayasan, satpen.kecamatan, satpen.kelurahan"
+
+// IsValidSearchMode reports whether mode is a supported search mode
+func IsValidSearchMode(mode string) bool {
+	switch mode {
+	case SearchModeLike, SearchModeNatural, SearchModeBoolean:
+		return true
+	}
+	return false
+}
+
+// fulltextMatch returns the MATCH ... AGAINST expression and its bound term for
+// the search in filter. ok is false when the search should fall back to LIKE.
+func fulltextMatch(filter *models.SatpenFilter) (expr string, term string, ok bool) {
+	if filter == nil || filter.Search == "" {
+		return "", "", false
+	}
+	search, mode := filter.Search, filter.SearchMode
+
+	switch mode {
+	case SearchModeNatural:
+		term = strings.Join(searchTokens(search, 1), " ")
+		if term == "" {
+			return "", "", false
+		}
+		return "MATCH(" + fulltextColumns + ") AGAINST(? IN NATURAL LANGUAGE MODE)", term, true
+	case SearchModeBoolean:
+		minTokenSize := filter.SearchMinTokenSize
+		if minTokenSize < 1 {
+			minTokenSize = 3
+		}
+		tokens := searchTokens(search, minTokenSize)
+		if len(tokens) == 0 {
+			return "", "", false
+		}
+		// Every word is required and matched as a prefix: "maarif sura" -> "+maarif* +sura*"
+		for i, token := range tokens {
+			tokens[i] = "+" + token + "*"
+		}
+		return "MATCH(" + fulltextColumns + ") AGAINST(? IN BOOLEAN MODE)", strings.Join(tokens, " "), true
+	}
+	return "", "", false
+}
+
+// searchTokens splits user input into plain words, dropping FULLTEXT operators
+// (+ - < > ( ) ~ * " @) and words shorter than minLen runes, which InnoDB
+// does not index anyway.
+func searchTokens(search string, minLen int) []string {
+	fields := strings.FieldsFunc(search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		// A single apostrophe inside a word ("Ma'arif") is part of the word for
+		// the FULLTEXT parser; leading/trailing ones are not
+		word := strings.Trim(field, "'")
+		if len([]rune(word)) >= minLen {
+			tokens = append(tokens, strings.ToLower(word))
+		}
+	}
+	return tokens
+}
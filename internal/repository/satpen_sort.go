@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"satpen-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrSortField is returned for sort fields outside the allowlist
+var ErrSortField = errors.New("invalid sort field")
+
+// SortKey is one key of a comma separated sort ("provinsi,-jumlah_siswa")
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+type sortField struct {
+	expr string
+	join string // needed to reach expr, shared by fields on the same table
+}
+
+// latestPDPTKJoin joins the most recent PDPTK row of each satpen
+var latestPDPTKJoin = joinLatestPDPTK("LEFT", "sort_pdptk")
+
+// sortFields is the allowlist of API sort fields. Joined tables use sort_
+// aliases so they never clash with joins of the caller.
+var sortFields = map[string]sortField{
+	"nama":           {expr: "satpen.nm_satpen"},
+	"npsn":           {expr: "satpen.npsn"},
+	"tahun_berdiri":  {expr: "satpen.thn_berdiri"},
+	"tgl_registrasi": {expr: "satpen.tgl_registrasi"},
+	"created_at":     {expr: "satpen.created_at"},
+	"updated_at":     {expr: "satpen.updated_at"},
+	"provinsi": {
+		expr: "sort_provinsi.nm_prov",
+		join: "LEFT JOIN provinsi sort_provinsi ON sort_provinsi.id_prov = satpen.id_prov",
+	},
+	"kabupaten": {
+		expr: "sort_kabupaten.nama_kab",
+		join: "LEFT JOIN kabupaten sort_kabupaten ON sort_kabupaten.id_kab = satpen.id_kab",
+	},
+	"jenjang": {
+		expr: "sort_jenjang.nm_jenjang",
+		join: "LEFT JOIN jenjang_pendidikan sort_jenjang ON sort_jenjang.id_jenjang = satpen.id_jenjang",
+	},
+	"akreditasi": {
+		expr: "sort_kategori.nm_kategori",
+		join: "LEFT JOIN kategori_satpen sort_kategori ON sort_kategori.id_kategori = satpen.id_kategori",
+	},
+	"jumlah_siswa": {expr: "COALESCE(sort_pdptk.jml_pd, 0)", join: latestPDPTKJoin},
+	"jumlah_guru":  {expr: "COALESCE(sort_pdptk.jml_guru, 0)", join: latestPDPTKJoin},
+	// Only meaningful with a FULLTEXT search, always most relevant first
+	"relevance": {expr: "relevance"},
+}
+
+// ParseSort validates a comma separated sort against the allowlist. Each key
+// may be prefixed with - for descending order. Unknown fields are all listed
+// in the returned error.
+func ParseSort(sort string) ([]SortKey, error) {
+	var keys []SortKey
+	var invalid []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortFields[key.Field]; !ok {
+			invalid = append(invalid, key.Field)
+			continue
+		}
+		if seen[key.Field] {
+			continue
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSortField, strings.Join(invalid, ", "))
+	}
+	return keys, nil
+}
+
+// applySort orders by sort, joining related tables as needed. An empty sort
+// means relevance while searching in fulltext mode and -created_at otherwise.
+// satpen.id_satpen is always the final tie-breaker so pages are stable.
+func (r *satpenRepository) applySort(query *gorm.DB, sort string, filter *models.SatpenFilter) *gorm.DB {
+	_, _, fulltext := fulltextMatch(filter)
+
+	keys, err := ParseSort(sort)
+	if err != nil {
+		// Callers validate sort first; make an invalid one fail the query
+		query.AddError(err)
+		return query
+	}
+	if len(keys) == 0 {
+		if fulltext {
+			keys = []SortKey{{Field: "relevance"}}
+		} else {
+			keys = []SortKey{{Field: "created_at", Desc: true}}
+		}
+	}
+
+	joined := make(map[string]bool)
+	for _, key := range keys {
+		field := sortFields[key.Field]
+		if key.Field == "relevance" {
+			if fulltext {
+				query = query.Order("relevance DESC")
+			}
+			continue
+		}
+		if field.join != "" && !joined[field.join] {
+			joined[field.join] = true
+			query = query.Joins(field.join)
+		}
+		direction := " ASC"
+		if key.Desc {
+			direction = " DESC"
+		}
+		query = query.Order(field.expr + direction)
+	}
+	return query.Order("satpen.id_satpen ASC")
+}
@@ -0,0 +1,455 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"satpen-api/internal/models"
+)
+
+// Latest PDPTK of the listed fixture satpen (siswa/guru): 1 180/12,
+// 2 320/20, 3 410/30, 4 none, 5 260/18, 7 500/35, 9 210/14
+
+func TestGetStatistics(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	stats, err := repo.GetStatistics(nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &models.SatpenStatistics{
+		TotalSatpen:         7,
+		TotalProvinsi:       2,
+		TotalKabupaten:      3,
+		TotalPengurusCabang: 3,
+		TotalSiswa:          1880,
+		TotalGuru:           129,
+		ByJenjang: map[string]models.JenjangStats{
+			"MI":  {Count: 2, Siswa: 180, Guru: 12},
+			"MTs": {Count: 3, Siswa: 790, Guru: 52},
+			"SMK": {Count: 2, Siswa: 910, Guru: 65},
+		},
+		ByAkreditasi: map[string]int64{
+			"A (Unggul)":          3,
+			"B (Baik)":            2,
+			"C (Cukup)":           1,
+			"Belum Terakreditasi": 1,
+		},
+		TopProvinsi: []models.ProvinsiStats{
+			{ID: 1, Provinsi: "Jawa Timur", Count: 5, Siswa: 1120, Guru: 76},
+			{ID: 2, Provinsi: "Jawa Tengah", Count: 2, Siswa: 760, Guru: 53},
+		},
+		// Kota Surabaya and Kabupaten Kudus tie; the lower ID wins
+		TopKabupaten: []models.KabupatenStats{
+			{ID: 1, Kabupaten: "Kabupaten Malang", Count: 3, Siswa: 710, Guru: 46},
+			{ID: 2, Kabupaten: "Kota Surabaya", Count: 2, Siswa: 410, Guru: 30},
+		},
+		TopPengurusCabang: []models.PengurusCabangStats{
+			{ID: 1, PengurusCabang: "PCNU Kabupaten Malang", Count: 3, Siswa: 710, Guru: 46},
+			{ID: 2, PengurusCabang: "PCNU Kota Surabaya", Count: 2, Siswa: 410, Guru: 30},
+		},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("got  %+v\nwant %+v", stats, want)
+	}
+
+	t.Run("filter applies to every figure", func(t *testing.T) {
+		stats, err := repo.GetStatistics(&models.SatpenFilter{Provinsi: []string{"Jawa Tengah"}}, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalSatpen != 2 || stats.TotalSiswa != 760 || stats.TotalGuru != 53 || stats.TotalKabupaten != 1 {
+			t.Errorf("totals = %+v", stats)
+		}
+		if len(stats.ByJenjang) != 2 || stats.ByJenjang["MTs"].Count != 1 || stats.ByJenjang["SMK"].Siswa != 500 {
+			t.Errorf("ByJenjang = %+v", stats.ByJenjang)
+		}
+		if len(stats.TopProvinsi) != 1 || len(stats.TopKabupaten) != 1 || len(stats.TopPengurusCabang) != 1 {
+			t.Errorf("top lists = %+v, %+v, %+v", stats.TopProvinsi, stats.TopKabupaten, stats.TopPengurusCabang)
+		}
+	})
+
+	t.Run("no matching satpen", func(t *testing.T) {
+		stats, err := repo.GetStatistics(&models.SatpenFilter{Kecamatan: []string{"Depok"}}, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalSatpen != 0 || stats.TotalSiswa != 0 || len(stats.ByJenjang) != 0 {
+			t.Errorf("stats = %+v", stats)
+		}
+		if stats.TopProvinsi == nil || len(stats.TopProvinsi) != 0 {
+			t.Errorf("TopProvinsi = %#v, want an empty list", stats.TopProvinsi)
+		}
+	})
+}
+
+func TestGetTopKabupatenLimit(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	top, err := repo.GetTopKabupaten(nil, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.KabupatenStats{
+		{ID: 1, Kabupaten: "Kabupaten Malang", Count: 3, Siswa: 710, Guru: 46},
+		{ID: 2, Kabupaten: "Kota Surabaya", Count: 2, Siswa: 410, Guru: 30},
+		{ID: 3, Kabupaten: "Kabupaten Kudus", Count: 2, Siswa: 760, Guru: 53},
+	}
+	if !reflect.DeepEqual(top, want) {
+		t.Errorf("got %+v, want %+v", top, want)
+	}
+}
+
+func TestCountByRegion(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	t.Run("provinsi", func(t *testing.T) {
+		counts, err := repo.CountByRegion(RegionProvinsi, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// DI Yogyakarta has no satpen and is left out
+		if len(counts) != 2 {
+			t.Fatalf("got %d regions, want 2: %+v", len(counts), counts)
+		}
+		jateng, jatim := counts[0], counts[1]
+		if jateng.Nama != "Jawa Tengah" || jateng.Kode != "33" || jateng.Map != "id-jt" || jateng.Count != 2 ||
+			jateng.Located != 1 || jateng.Siswa != 760 || jateng.Guru != 53 {
+			t.Errorf("Jawa Tengah = %+v", jateng)
+		}
+		if jatim.Nama != "Jawa Timur" || jatim.Count != 5 || jatim.Located != 4 || jatim.Siswa != 1120 {
+			t.Errorf("Jawa Timur = %+v", jatim)
+		}
+		// Mean of the located satpen only
+		if !near(jatim.Latitude, -7.715) || !near(jatim.Longitude, 112.6975) {
+			t.Errorf("Jawa Timur location = %v, %v", deref(jatim.Latitude), deref(jatim.Longitude))
+		}
+	})
+
+	t.Run("kabupaten", func(t *testing.T) {
+		counts, err := repo.CountByRegion(RegionKabupaten, &models.SatpenFilter{HasLocation: boolPtr(false)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []struct {
+			nama  string
+			count int64
+		}{{"Kabupaten Kudus", 1}, {"Kota Surabaya", 1}}
+		if len(counts) != len(want) {
+			t.Fatalf("got %+v", counts)
+		}
+		for i, w := range want {
+			if counts[i].Nama != w.nama || counts[i].Count != w.count || counts[i].Located != 0 || counts[i].Latitude != nil {
+				t.Errorf("counts[%d] = %+v, want %s with %d satpen and no location", i, counts[i], w.nama, w.count)
+			}
+		}
+		if counts[0].IDProv != 2 {
+			t.Errorf("IDProv = %d, want 2", counts[0].IDProv)
+		}
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		if _, err := repo.CountByRegion(RegionKecamatan, nil); !errors.Is(err, ErrRegionLevel) {
+			t.Errorf("err = %v, want ErrRegionLevel", err)
+		}
+	})
+}
+
+func TestCountByPlace(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	counts, err := repo.CountByPlace(RegionKecamatan, &models.SatpenFilter{ProvinsiID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]models.PlaceCount)
+	for _, c := range counts {
+		got[c.Kecamatan] = c
+	}
+	want := map[string]models.PlaceCount{
+		"Singosari": {Kecamatan: "Singosari", Count: 1, Siswa: 180, Guru: 12},
+		"Lawang":    {Kecamatan: "Lawang", Count: 2, Siswa: 530, Guru: 34},
+		"Wonokromo": {Kecamatan: "Wonokromo", Count: 2, Siswa: 410, Guru: 30},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	counts, err = repo.CountByPlace(RegionKelurahan, &models.SatpenFilter{Kecamatan: []string{"Wonokromo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 {
+		t.Fatalf("got %+v, want Jagir and Ngagel", counts)
+	}
+	for _, c := range counts {
+		if c.Kecamatan != "Wonokromo" || c.Count != 1 || (c.Kelurahan != "Jagir" && c.Kelurahan != "Ngagel") {
+			t.Errorf("count = %+v", c)
+		}
+	}
+}
+
+func TestCountByPeriod(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+	until := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		event, interval string
+		filter          *models.SatpenFilter
+		want            []models.PeriodCount
+	}{
+		{
+			"registrations per year", EventRegistered, IntervalYear, nil,
+			[]models.PeriodCount{period(2018, 1, 1), period(2019, 1, 1), period(2020, 1, 1), period(2021, 1, 2), period(2022, 1, 1), period(2024, 1, 1)},
+		},
+		{
+			"registrations per month", EventRegistered, IntervalMonth, &models.SatpenFilter{TglRegistrasiFrom: datePtr(2021, 1, 1)},
+			[]models.PeriodCount{period(2021, 5, 1), period(2021, 9, 1), period(2022, 1, 1), period(2024, 2, 1)},
+		},
+		{
+			"approvals per quarter", EventApproved, IntervalQuarter, &models.SatpenFilter{ProvinsiID: 1},
+			[]models.PeriodCount{period(2019, 3, 1), period(2020, 1, 1), period(2021, 2, 1), period(2022, 1, 1), period(2024, 1, 1)},
+		},
+		{
+			// actived_date + 4 years before until: satpen 7, 3 and 1
+			"expiries per year", EventExpired, IntervalYear, nil,
+			[]models.PeriodCount{period(2022, 1, 1), period(2023, 1, 1), period(2024, 1, 1)},
+		},
+		{
+			"pending registrations", EventRegistered, IntervalYear, &models.SatpenFilter{Status: []string{"permohonan", "revisi", "proses dokumen"}},
+			[]models.PeriodCount{period(2023, 1, 2), period(2024, 1, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.CountByPeriod(tt.event, tt.interval, tt.filter, until, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("until is exclusive", func(t *testing.T) {
+		got, err := repo.CountByPeriod(EventRegistered, IntervalYear, nil, time.Date(2020, 2, 10, 9, 0, 0, 0, time.UTC), 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []models.PeriodCount{period(2018, 1, 1), period(2019, 1, 1)}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		if _, err := repo.CountByPeriod("deleted", IntervalYear, nil, until, 4); !errors.Is(err, ErrTrendEvent) {
+			t.Errorf("err = %v, want ErrTrendEvent", err)
+		}
+		if _, err := repo.CountByPeriod(EventRegistered, "week", nil, until, 4); !errors.Is(err, ErrInterval) {
+			t.Errorf("err = %v, want ErrInterval", err)
+		}
+	})
+}
+
+func TestCountByJenjangStatus(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	counts, err := repo.CountByJenjangStatus(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]models.JenjangStatusCount)
+	for _, c := range counts {
+		got[c.Jenjang+"/"+c.Status] = c
+	}
+	want := map[string]models.JenjangStatusCount{
+		"MI/setujui":      {Jenjang: "MI", Status: "setujui", Count: 1, Siswa: 180, Guru: 12, Tendik: 3},
+		"MI/perpanjangan": {Jenjang: "MI", Status: "perpanjangan", Count: 1},
+		"MTs/setujui":     {Jenjang: "MTs", Status: "setujui", Count: 3, Siswa: 790, Guru: 52, Tendik: 9},
+		"SMK/expired":     {Jenjang: "SMK", Status: "expired", Count: 2, Siswa: 910, Guru: 65, Tendik: 14},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestFindExpiring(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	expiring, err := repo.FindExpiring(nil, 4, from, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		id        uint
+		expiresAt string
+	}{{1, "2024-03-01"}, {2, "2025-06-01"}, {5, "2025-10-01"}}
+	if len(expiring) != len(want) {
+		t.Fatalf("got %+v", expiring)
+	}
+	for i, w := range want {
+		e := expiring[i]
+		if e.ID != w.id || e.ExpiresAt.Format("2006-01-02") != w.expiresAt {
+			t.Errorf("expiring[%d] = %d expiring %s, want %d expiring %s", i, e.ID, e.ExpiresAt.Format("2006-01-02"), w.id, w.expiresAt)
+		}
+	}
+	if e := expiring[2]; e.NPSN != "20507005" || e.Nama != "MTs Ma'arif Kudus" || e.Jenjang != "MTs" || e.ActivedDate.Format("2006-01-02") != "2021-10-01" {
+		t.Errorf("expiring[2] = %+v", e)
+	}
+
+	expiring, err = repo.FindExpiring(&models.SatpenFilter{ProvinsiID: 2}, 4, from, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expiring) != 1 || expiring[0].ID != 5 {
+		t.Errorf("filtered = %+v, want satpen 5", expiring)
+	}
+}
+
+func TestCountPTK(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	tests := []struct {
+		filter *models.SatpenFilter
+		status string
+		want   int64
+	}{
+		{nil, "verifikasi", 3}, // the PTK of permohonan satpen 6 is left out
+		{nil, "approve", 1},
+		{&models.SatpenFilter{ProvinsiID: 2}, "verifikasi", 1},
+		{&models.SatpenFilter{Status: []string{"permohonan"}}, "verifikasi", 1},
+		{nil, "dikeluarkan", 0},
+	}
+	for _, tt := range tests {
+		got, err := repo.CountPTK(tt.filter, tt.status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("CountPTK(%+v, %s) = %d, want %d", tt.filter, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestFindIndikatorRows(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	rows, err := repo.FindIndikatorRows(RegionProvinsi, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Satpen 4 has no PDPTK
+	var ids []uint
+	for _, row := range rows {
+		ids = append(ids, row.IDSatpen)
+	}
+	if want := []uint{1, 2, 3, 5, 7, 9}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	want := models.IndikatorRow{
+		IDSatpen: 1, NPSN: "20507001", Nama: "MI Ma'arif NU 01 Singosari",
+		GroupID: 1, GroupName: "Jawa Timur", JmlPD: 180, JmlGuru: 12, GuruPR: 7, JmlTendik: 3,
+	}
+	if rows[0] != want {
+		t.Errorf("rows[0] = %+v, want %+v", rows[0], want)
+	}
+
+	for _, group := range []struct{ by, name string }{{RegionKabupaten, "Kabupaten Kudus"}, {GroupJenjang, "MTs"}} {
+		rows, err := repo.FindIndikatorRows(group.by, &models.SatpenFilter{ProvinsiID: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 2 || rows[0].IDSatpen != 5 || rows[0].GroupName != group.name {
+			t.Errorf("%s: rows = %+v", group.by, rows)
+		}
+	}
+
+	if _, err := repo.FindIndikatorRows("kecamatan", nil); !errors.Is(err, ErrIndikatorGroup) {
+		t.Errorf("err = %v, want ErrIndikatorGroup", err)
+	}
+}
+
+func TestFindAllIndikator(t *testing.T) {
+	repo := newTestSatpenRepository(t)
+
+	rows, err := repo.FindIndikatorRows(RegionProvinsi, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranked := make(map[uint]models.IndikatorRow)
+	for _, row := range rows {
+		ranked[row.IDSatpen] = row
+	}
+
+	// The list computes the same indicators as the ranking, from the latest
+	// PDPTK of every row on the page
+	satpen, _, err := repo.FindAll(nil, 1, 10, "nama", &Projection{Preloads: []string{"PDPTK"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(satpen) != 7 {
+		t.Fatalf("got %d satpen, want 7", len(satpen))
+	}
+	for _, s := range satpen {
+		row, ok := ranked[s.IDSatpen]
+		if !ok {
+			if s.Indikator != nil {
+				t.Errorf("satpen %d has no PDPTK but Indikator = %+v", s.IDSatpen, s.Indikator)
+			}
+			continue
+		}
+		want := models.NewIndikator(row.JmlPD, row.JmlGuru, row.GuruPR, row.JmlTendik)
+		if !reflect.DeepEqual(s.Indikator, want) {
+			t.Errorf("satpen %d: Indikator = %+v, want %+v", s.IDSatpen, s.Indikator, want)
+		}
+	}
+
+	// Satpen 7: 500 siswa, 35 guru of which 15 perempuan, 8 tendik (two decimals)
+	for _, s := range satpen {
+		if s.IDSatpen != 7 {
+			continue
+		}
+		if s.Indikator == nil || !near(s.Indikator.RasioSiswaGuru, 14.29) ||
+			!near(s.Indikator.PersenGuruPerempuan, 42.86) || !near(s.Indikator.TendikPer100Siswa, 1.6) {
+			t.Errorf("satpen 7: Indikator = %+v", s.Indikator)
+		}
+	}
+}
+
+func period(year, part int, count int64) models.PeriodCount {
+	return models.PeriodCount{Year: year, Part: part, Count: count}
+}
+
+func near(v *float64, want float64) bool {
+	return v != nil && math.Abs(*v-want) < 1e-6
+}
+
+func deref(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func TestAddYearsTime(t *testing.T) {
+	tests := []struct{ from, want string }{
+		{"2021-10-01", "2025-10-01"},
+		{"2020-02-29", "2024-02-29"},
+		{"2096-02-29", "2100-02-28"}, // 2100 is no leap year
+	}
+	for _, tt := range tests {
+		from, _ := time.Parse("2006-01-02", tt.from)
+		if got := addYearsTime(from, 4).Format("2006-01-02"); got != tt.want {
+			t.Errorf("addYearsTime(%s, 4) = %s, want %s", tt.from, got, tt.want)
+		}
+	}
+}
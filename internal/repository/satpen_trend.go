@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"satpen-api/internal/models"
+	"time"
+)
+
+// Trend intervals
+const (
+	IntervalMonth   = "month"
+	IntervalQuarter = "quarter"
+	IntervalYear    = "year"
+)
+
+// Trend events counted by CountByPeriod
+const (
+	EventRegistered = "registered" // tgl_registrasi
+	EventApproved   = "approved"   // actived_date
+	EventExpired    = "expired"    // actived_date + the registration validity
+)
+
+var (
+	// ErrInterval is returned for an interval other than month, quarter or year
+	ErrInterval = errors.New("interval must be month, quarter or year")
+	// ErrTrendEvent is returned for an unknown event
+	ErrTrendEvent = errors.New("unknown trend event")
+)
+
+// CountByPeriod counts the events of the satpen matching filter that happen
+// before until, per interval. Expiries are validYears after actived_date.
+func (r *satpenRepository) CountByPeriod(event, interval string, filter *models.SatpenFilter, until time.Time, validYears int) ([]models.PeriodCount, error) {
+	var results []models.PeriodCount
+
+	var column string
+	switch event {
+	case EventRegistered:
+		column = "satpen.tgl_registrasi"
+	case EventApproved:
+		column = "satpen.actived_date"
+	case EventExpired:
+		column = addYears(r.db, "satpen.actived_date", validYears)
+	default:
+		return nil, ErrTrendEvent
+	}
+
+	var part string
+	switch interval {
+	case IntervalMonth:
+		part = "MONTH(" + column + ")"
+	case IntervalQuarter:
+		part = "QUARTER(" + column + ")"
+	case IntervalYear:
+		part = "1"
+	default:
+		return nil, ErrInterval
+	}
+
+	query := r.db.Table("satpen").
+		Select("YEAR("+column+") as year, "+part+" as part, COUNT(*) as count").
+		Where(column+" IS NOT NULL AND "+column+" < ?", until).
+		Group("year, part").
+		Order("year, part")
+
+	query = r.applyFilters(query, filter)
+
+	err := query.Scan(&results).Error
+	return results, err
+}
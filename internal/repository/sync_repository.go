@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"satpen-api/internal/audit"
+	"satpen-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SyncRepository interface {
+	GetCurrentTapel() (string, error)
+	FindSatpen(id uint) (*models.Satpen, error)
+	FindSatpenForSync(tapel string, staleBefore time.Time, limit int) ([]models.Satpen, error)
+	SavePDPTK(pdptk *models.PDPTK, entry *models.AuditLog) error
+	MarkSyncFailed(idSatpen uint, tapel string, entry *models.AuditLog) error
+}
+
+type syncRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncRepository(db *gorm.DB) SyncRepository {
+	return &syncRepository{db: db}
+}
+
+// GetCurrentTapel returns the latest tahun pelajaran code as used by Dapodik
+func (r *syncRepository) GetCurrentTapel() (string, error) {
+	return currentTapel(r.db)
+}
+
+func currentTapel(db *gorm.DB) (string, error) {
+	var tapel models.TahunPelajaran
+	err := db.Where("tapel_dapo IS NOT NULL").
+		Order("tapel_dapo DESC").
+		First(&tapel).Error
+	return tapel.TapelDapo, err
+}
+
+func (r *syncRepository) FindSatpen(id uint) (*models.Satpen, error) {
+	var satpen models.Satpen
+	err := r.db.Select("id_satpen", "npsn", "nm_satpen", "status").First(&satpen, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &satpen, nil
+}
+
+// FindSatpenForSync returns active schools whose PDPTK for tapel is missing or
+// was last synchronised before staleBefore, least recently synced first
+func (r *syncRepository) FindSatpenForSync(tapel string, staleBefore time.Time, limit int) ([]models.Satpen, error) {
+	var satpen []models.Satpen
+
+	err := r.db.Model(&models.Satpen{}).
+		Select("satpen.id_satpen, satpen.npsn, satpen.nm_satpen, satpen.status").
+		Joins("LEFT JOIN pdptk ON pdptk.id_satpen = satpen.id_satpen AND pdptk.tapel = ?", tapel).
+		Where("satpen.status IN (?)", []string{"setujui", "perpanjangan"}).
+		Where("satpen.npsn <> ''").
+		Where("pdptk.id IS NULL OR pdptk.last_sinkron IS NULL OR pdptk.last_sinkron < ?", staleBefore).
+		Order("pdptk.last_sinkron IS NOT NULL, pdptk.last_sinkron ASC, satpen.id_satpen ASC").
+		Limit(limit).
+		Find(&satpen).Error
+
+	return satpen, err
+}
+
+// SavePDPTK inserts or updates the PDPTK row identified by (id_satpen, tapel)
+// and writes entry for it with the previous and new values
+func (r *syncRepository) SavePDPTK(pdptk *models.PDPTK, entry *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.PDPTK
+		err := tx.Where("id_satpen = ? AND tapel = ?", pdptk.IDSatpen, pdptk.Tapel).First(&existing).Error
+		var before *models.PDPTK
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(pdptk).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			previous := existing
+			before = &previous
+			pdptk.ID = existing.ID
+			if err := tx.Model(&existing).Select("*").Omit("id").Updates(pdptk).Error; err != nil {
+				return err
+			}
+		}
+
+		entry.EntityID = uint(pdptk.ID)
+		if err := audit.Fill(entry, before, pdptk); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// MarkSyncFailed flags the PDPTK row for (id_satpen, tapel), if any, as failed
+// without touching last_sinkron so the last successful pull stays visible.
+// entry is written only when the flag changes.
+func (r *syncRepository) MarkSyncFailed(idSatpen uint, tapel string, entry *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.PDPTK
+		err := tx.Select("id", "status_sinkron").Where("id_satpen = ? AND tapel = ?", idSatpen, tapel).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && existing.StatusSinkron == models.StatusSinkronGagal) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.PDPTK{}).
+			Where("id = ?", existing.ID).
+			Update("status_sinkron", models.StatusSinkronGagal).Error; err != nil {
+			return err
+		}
+
+		entry.EntityID = uint(existing.ID)
+		before := map[string]int{"status_sinkron": existing.StatusSinkron}
+		after := map[string]int{"status_sinkron": models.StatusSinkronGagal}
+		if err := audit.Fill(entry, before, after); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
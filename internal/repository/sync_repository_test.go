@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"satpen-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestFindSatpenForSync(t *testing.T) {
+	repo := NewSyncRepository(newTestDB(t))
+
+	tapel, err := repo.GetCurrentTapel()
+	if err != nil || tapel != "20241" {
+		t.Fatalf("GetCurrentTapel = %q, %v; want 20241", tapel, err)
+	}
+
+	tests := []struct {
+		name        string
+		staleBefore time.Time
+		limit       int
+		want        []uint
+	}{
+		// Setujui and perpanjangan only: 4 and 5 have no 20241 row, 1, 2
+		// and 9 were synced on 2024-08-01
+		{"missing only", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), 10, []uint{4, 5}},
+		{"missing first, then least recently synced", time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), 10, []uint{4, 5, 1, 2, 9}},
+		{"limit", time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), 3, []uint{4, 5, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			satpen, err := repo.FindSatpenForSync(tapel, tt.staleBefore, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := satpenIDs(satpen); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindSatpenForSync = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSavePDPTK(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewSyncRepository(db)
+	syncedAt := time.Date(2024, 9, 2, 8, 0, 0, 0, time.UTC)
+
+	newPDPTK := func(idSatpen uint, pd int) *models.PDPTK {
+		return &models.PDPTK{IDSatpen: &idSatpen, Tapel: "20241", PDLK: pd, JmlPD: pd, GuruLK: 3, JmlGuru: 3, LastSinkron: &syncedAt, StatusSinkron: models.StatusSinkronBerhasil}
+	}
+
+	// Satpen 4 has no 20241 row: inserted, audited without before
+	inserted := newPDPTK(4, 90)
+	entry := &models.AuditLog{Actor: "dapodik-sync", Action: models.AuditActionSync, EntityType: "pdptk", CreatedAt: syncedAt}
+	if err := repo.SavePDPTK(inserted, entry); err != nil {
+		t.Fatal(err)
+	}
+	if inserted.ID == 0 || entry.EntityID != uint(inserted.ID) || entry.Before != nil || entry.After == nil {
+		t.Errorf("insert: pdptk id %d, audit %+v", inserted.ID, entry)
+	}
+	if got, want := auditEntityIDs(t, db, models.AuditActionSync), []uint{uint(inserted.ID)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("insert: audit entries for pdptk %v, want %v", got, want)
+	}
+
+	// Satpen 1 has one: updated in place, keeping its id
+	updated := newPDPTK(1, 200)
+	entry = &models.AuditLog{Actor: "dapodik-sync", Action: models.AuditActionSync, EntityType: "pdptk", CreatedAt: syncedAt}
+	if err := repo.SavePDPTK(updated, entry); err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != 2 || entry.EntityID != 2 {
+		t.Errorf("update: pdptk id %d, audit entity %d; want the existing row 2", updated.ID, entry.EntityID)
+	}
+	if got, want := auditEntityIDs(t, db, models.AuditActionSync), []uint{uint(inserted.ID), 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("update: audit entries for pdptk %v, want %v", got, want)
+	}
+	var changes map[string]json.RawMessage
+	if err := json.Unmarshal(entry.Changes, &changes); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := changes["jumlah_siswa"]; !ok {
+		t.Errorf("changes = %s, want jumlah_siswa", entry.Changes)
+	}
+
+	var rows []models.PDPTK
+	if err := db.Where("id_satpen = ? AND tapel = ?", 1, "20241").Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].JmlPD != 200 || rows[0].StatusSinkron != models.StatusSinkronBerhasil || rows[0].LastSinkron == nil || !rows[0].LastSinkron.Equal(syncedAt) {
+		t.Errorf("stored = %+v, want one updated row", rows)
+	}
+}
+
+func TestMarkSyncFailed(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewSyncRepository(db)
+
+	// mark flags the 20241 row of idSatpen and checks the sync_failed
+	// entries written so far
+	mark := func(idSatpen uint, want []uint) {
+		t.Helper()
+		entry := &models.AuditLog{Actor: "dapodik-sync", Action: models.AuditActionSyncFailed, EntityType: "pdptk"}
+		if err := repo.MarkSyncFailed(idSatpen, "20241", entry); err != nil {
+			t.Fatal(err)
+		}
+		if got := auditEntityIDs(t, db, models.AuditActionSyncFailed); !reflect.DeepEqual(got, want) {
+			t.Errorf("after marking satpen %d: audit entries for pdptk %v, want %v", idSatpen, got, want)
+		}
+	}
+	mark(1, []uint{2})
+	mark(1, []uint{2}) // already failed: no second entry
+	mark(4, []uint{2}) // no row to flag
+
+	var pdptk models.PDPTK
+	if err := db.Where("id_satpen = ? AND tapel = ?", 1, "20241").First(&pdptk).Error; err != nil {
+		t.Fatal(err)
+	}
+	if pdptk.StatusSinkron != models.StatusSinkronGagal {
+		t.Errorf("status_sinkron = %d, want %d", pdptk.StatusSinkron, models.StatusSinkronGagal)
+	}
+	// The last successful pull stays visible
+	if pdptk.LastSinkron == nil || !pdptk.LastSinkron.Equal(time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("last_sinkron = %v, want it unchanged", pdptk.LastSinkron)
+	}
+
+	var untouched int64
+	db.Model(&models.PDPTK{}).Where("id_satpen = ?", 4).Count(&untouched)
+	if untouched != 0 {
+		t.Errorf("satpen 4 has %d pdptk rows, want none created", untouched)
+	}
+}
+
+// auditEntityIDs returns the entity_id of the pdptk audit entries of action,
+// in insertion order
+func auditEntityIDs(t *testing.T, db *gorm.DB, action string) []uint {
+	t.Helper()
+	var ids []uint
+	if err := db.Model(&models.AuditLog{}).Where("entity_type = ? AND action = ?", "pdptk", action).
+		Order("id ASC").Pluck("entity_id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
@@ -0,0 +1,93 @@
+-- Fixtures of the repository tests. Satpen 1-5, 7 and 9 have a listed status
+-- (setujui, expired, perpanjangan); 6, 8 and 10 are pending registrations.
+-- Satpen 4, 8 and 10 have no PDPTK. Times are UTC in the text format the
+-- SQLite driver binds time.Time arguments in, so comparisons with them are
+-- exact.
+
+INSERT INTO provinsi (id_prov, map, kode_prov, nm_prov) VALUES
+  (1, 'id-ji', '35', 'Jawa Timur'),
+  (2, 'id-jt', '33', 'Jawa Tengah'),
+  (3, 'id-yo', '34', 'DI Yogyakarta');
+
+INSERT INTO kabupaten (id_kab, id_prov, nama_kab) VALUES
+  (1, 1, 'Kabupaten Malang'),
+  (2, 1, 'Kota Surabaya'),
+  (3, 2, 'Kabupaten Kudus'),
+  (4, 3, 'Kabupaten Sleman');
+
+INSERT INTO pengurus_cabang (id_pc, id_prov, kode_kab, nama_pc) VALUES
+  (1, 1, '3507', 'PCNU Kabupaten Malang'),
+  (2, 1, '3578', 'PCNU Kota Surabaya'),
+  (3, 2, '3319', 'PCNU Kudus'),
+  (4, 3, '3404', 'PCNU Sleman');
+
+INSERT INTO profile_pengurus_cabang (id, id_pc, alamat, ketua, masa_khidmat) VALUES
+  (1, 1, 'Jl. Kauman 1', 'Ahmad Hidayat', '2018-2023'),
+  (2, 1, 'Jl. Kauman 3', 'Slamet Santoso', '2023-2028');
+
+INSERT INTO profile_pengurus_wilayah (id, id_pw, alamat, ketua, lintang, bujur) VALUES
+  (1, 1, 'Jl. Raya Darmo 96', 'Abdul Halim', NULL, NULL),
+  (2, 1, 'Jl. Masjid Al-Akbar 9', 'Hasan Mubarok', '-7,3363', '112.7155');
+
+INSERT INTO jenjang_pendidikan (id_jenjang, nm_jenjang, keterangan, lembaga) VALUES
+  (1, 'MI', 'Madrasah Ibtidaiyah', 'MADRASAH'),
+  (2, 'MTs', 'Madrasah Tsanawiyah', 'MADRASAH'),
+  (3, 'SMK', 'Sekolah Menengah Kejuruan', 'SEKOLAH');
+
+INSERT INTO kategori_satpen (id_kategori, nm_kategori, konotasi, keterangan) VALUES
+  (1, 'A', 'Unggul', 'Terakreditasi A'),
+  (2, 'B', 'Baik', 'Terakreditasi B'),
+  (3, 'C', 'Cukup', 'Terakreditasi C'),
+  (4, 'D', 'Kurang', 'Belum memenuhi standar');
+
+INSERT INTO tahun_pelajaran (id, tapel_dapo, nama_tapel) VALUES
+  (1, '20231', '2023/2024 Ganjil'),
+  (2, '20232', '2023/2024 Genap'),
+  (3, '20241', '2024/2025 Ganjil');
+
+INSERT INTO satpen (id_satpen, id_user, id_prov, id_kab, id_pc, id_kategori, id_jenjang, npsn, no_registrasi, no_urut,
+  nm_satpen, yayasan, thn_berdiri, kecamatan, kelurahan, alamat, tgl_registrasi, actived_date, status, created_at, updated_at, lintang, bujur) VALUES
+  (1, 101, 1, 1, 1, 1, 1, '20507001', 'REG-0001', '0001', 'MI Ma''arif NU 01 Singosari', 'Yayasan Pendidikan Ma''arif Malang', 1985,
+    'Singosari', 'Pagentan', 'Jl. Raya Singosari 12', '2020-02-10 09:00:00+00:00', '2020-03-01 10:00:00+00:00', 'setujui', '2020-02-10 09:00:00+00:00', '2020-03-01 10:00:00+00:00', -7.89, 112.66),
+  (2, 102, 1, 1, 1, 2, 2, '20507002', 'REG-0002', '0002', 'MTs NU Lawang', 'Yayasan Pendidikan Ma''arif Malang', 1990,
+    'Lawang', 'Bedali', 'Jl. Diponegoro 7', '2021-05-03 09:00:00+00:00', '2021-06-01 10:00:00+00:00', 'setujui', '2021-05-03 09:00:00+00:00', '2021-06-01 10:00:00+00:00', -7.83, 112.69),
+  (3, 103, 1, 2, 2, 1, 3, '20507003', 'REG-0003', '0003', 'SMK Ma''arif Surabaya', 'Yayasan Pendidikan Ma''arif Surabaya', 2005,
+    'Wonokromo', 'Jagir', 'Jl. Jagir Wonokromo 100', '2019-07-15 09:00:00+00:00', '2019-08-01 10:00:00+00:00', 'expired', '2019-07-15 09:00:00+00:00', '2019-08-01 10:00:00+00:00', NULL, NULL),
+  (4, 104, 1, 2, 2, NULL, 1, '20507004', 'REG-0004', '0004', 'MI Nurul Huda Wonokromo', 'Yayasan Nurul Huda', 2010,
+    'Wonokromo', 'Ngagel', 'Jl. Ngagel Jaya 3', '2022-01-20 09:00:00+00:00', '2022-02-14 10:00:00+00:00', 'perpanjangan', '2022-01-20 09:00:00+00:00', '2022-02-14 10:00:00+00:00', -7.30, 112.74),
+  (5, 105, 2, 3, 3, 3, 2, '20507005', 'REG-0005', '0005', 'MTs Ma''arif Kudus', 'Yayasan Pendidikan Ma''arif Kudus', 1978,
+    'Kota Kudus', 'Demaan', 'Jl. Sunan Kudus 21', '2021-09-09 09:00:00+00:00', '2021-10-01 10:00:00+00:00', 'setujui', '2021-09-09 09:00:00+00:00', '2021-10-01 10:00:00+00:00', -6.80, 110.84),
+  (6, 106, 2, 3, 3, 2, 1, '20507006', 'REG-0006', '0006', 'MI Darul Ulum Jati', 'Yayasan Darul Ulum', 2015,
+    'Jati', 'Jepang Pakis', 'Jl. Pesantren 4', '2023-03-03 09:00:00+00:00', NULL, 'permohonan', '2023-03-03 09:00:00+00:00', '2023-03-03 09:00:00+00:00', NULL, NULL),
+  (7, 107, 2, 3, 3, 2, 3, '20507007', 'REG-0007', '0007', 'SMK NU Kudus', 'Yayasan Pendidikan Ma''arif Kudus', 2000,
+    'Kota Kudus', 'Panjunan', 'Jl. KH. Wahid Hasyim 5', '2018-04-04 09:00:00+00:00', '2018-05-01 10:00:00+00:00', 'expired', '2018-04-04 09:00:00+00:00', '2018-05-01 10:00:00+00:00', NULL, NULL),
+  (8, 108, 1, 1, 1, 2, 1, '20507008', 'REG-0008', '0008', 'MI Al-Hikmah Singosari', 'Yayasan Al-Hikmah', 1999,
+    'Singosari', 'Pagentan', 'Jl. Kenanga 8', '2023-11-11 09:00:00+00:00', NULL, 'revisi', '2023-11-11 09:00:00+00:00', '2023-11-11 09:00:00+00:00', NULL, NULL),
+  (9, 109, 1, 1, 1, 1, 2, '20507009', 'REG-0009', '0009', 'MTs Islamiyah Lawang', 'Yayasan Islamiyah Lawang', 1995,
+    'Lawang', 'Kalirejo', 'Jl. Pahlawan 9', '2024-02-02 09:00:00+00:00', '2024-03-01 10:00:00+00:00', 'setujui', '2024-02-02 09:00:00+00:00', '2024-03-01 10:00:00+00:00', -7.84, 112.70),
+  (10, 110, 2, 3, 3, 3, 1, '20507010', 'REG-0010', '0010', 'MI Sabilul Huda', 'Yayasan Sabilul Huda', 2012,
+    'Jati', 'Loram', 'Jl. Melati 10', '2024-06-06 09:00:00+00:00', NULL, 'proses dokumen', '2024-06-06 09:00:00+00:00', '2024-06-06 09:00:00+00:00', NULL, NULL);
+
+-- The latest tapel counts: satpen 1 has an older row that must be ignored
+INSERT INTO pdptk (id, id_satpen, tapel, pd_lk, pd_pr, jml_pd, guru_lk, guru_pr, jml_guru, tendik_lk, tendik_pr, jml_tendik, last_sinkron, status_sinkron) VALUES
+  (1, 1, '20231', 70, 80, 150, 4, 6, 10, 1, 1, 2, '2023-08-01 10:00:00+00:00', 1),
+  (2, 1, '20241', 85, 95, 180, 5, 7, 12, 2, 1, 3, '2024-08-01 10:00:00+00:00', 1),
+  (3, 2, '20241', 160, 160, 320, 9, 11, 20, 2, 2, 4, '2024-08-01 10:00:00+00:00', 1),
+  (4, 3, '20232', 250, 160, 410, 18, 12, 30, 4, 2, 6, '2024-02-01 10:00:00+00:00', 1),
+  (5, 5, '20231', 120, 130, 250, 8, 10, 18, 2, 1, 3, '2023-08-01 10:00:00+00:00', 1),
+  (6, 5, '20232', 125, 135, 260, 8, 10, 18, 2, 1, 3, '2024-02-01 10:00:00+00:00', 1),
+  (7, 6, '20241', 40, 50, 90, 2, 4, 6, 1, 0, 1, '2024-08-01 10:00:00+00:00', 1),
+  (8, 7, '20241', 300, 200, 500, 20, 15, 35, 5, 3, 8, '2024-08-01 10:00:00+00:00', 1),
+  (9, 9, '20241', 100, 110, 210, 5, 9, 14, 1, 1, 2, '2024-08-01 10:00:00+00:00', 1);
+
+INSERT INTO ptk (id, id_satpen, nik, nama_ptk, status_ajuan) VALUES
+  (1, 1, '3507010101800001', 'Siti Hasanah', 'verifikasi'),
+  (2, 1, '3507010101800002', 'Agus Santoso', 'verifikasi'),
+  (3, 1, '3507010101800003', 'Imam Fauzi', 'approve'),
+  (4, 5, '3319010101800004', 'Nur Khasanah', 'verifikasi'),
+  (5, 6, '3319010101800005', 'Eko Setiawan', 'verifikasi');
+
+INSERT INTO timeline_reg (id_timeline, id_satpen, status_verifikasi, tgl_status) VALUES
+  (1, 1, 'setujui', '2021-03-01 09:00:00'),
+  (2, 5, 'permohonan', '2022-01-10 09:00:00'),
+  (3, 5, 'revisi', '2022-01-20 09:00:00');
@@ -0,0 +1,183 @@
+-- SQLite version of the tables the repositories read, following
+-- internal/database/migrations. Enum columns become TEXT with a CHECK and the
+-- _ci collations NOCASE, so filters match the same rows as on MySQL.
+
+CREATE TABLE provinsi (
+  id_prov INTEGER PRIMARY KEY,
+  map VARCHAR(10) DEFAULT NULL,
+  kode_prov VARCHAR(10) NOT NULL,
+  nm_prov VARCHAR(100) COLLATE NOCASE NOT NULL,
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE kabupaten (
+  id_kab INTEGER PRIMARY KEY,
+  id_prov INTEGER NOT NULL REFERENCES provinsi (id_prov),
+  nama_kab VARCHAR(255) COLLATE NOCASE NOT NULL,
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE pengurus_cabang (
+  id_pc INTEGER PRIMARY KEY,
+  id_prov INTEGER NOT NULL REFERENCES provinsi (id_prov),
+  kode_kab VARCHAR(10) NOT NULL,
+  nama_pc VARCHAR(255) COLLATE NOCASE NOT NULL,
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE profile_pengurus_cabang (
+  id INTEGER PRIMARY KEY,
+  id_pc INTEGER NOT NULL REFERENCES pengurus_cabang (id_pc),
+  alamat VARCHAR(255) DEFAULT NULL,
+  kelurahan VARCHAR(100) DEFAULT NULL,
+  kecamatan VARCHAR(100) DEFAULT NULL,
+  kabupaten VARCHAR(100) DEFAULT NULL,
+  lintang VARCHAR(50) DEFAULT NULL,
+  bujur VARCHAR(50) DEFAULT NULL,
+  website VARCHAR(50) DEFAULT NULL,
+  ketua VARCHAR(100) DEFAULT NULL,
+  telp_ketua VARCHAR(15) DEFAULT NULL,
+  wakil_ketua VARCHAR(100) DEFAULT NULL,
+  telp_wakil VARCHAR(15) DEFAULT NULL,
+  bendahara VARCHAR(100) DEFAULT NULL,
+  telp_bendahara VARCHAR(15) DEFAULT NULL,
+  sekretaris VARCHAR(100) DEFAULT NULL,
+  telp_sekretaris VARCHAR(15) DEFAULT NULL,
+  masa_khidmat VARCHAR(50) DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE profile_pengurus_wilayah (
+  id INTEGER PRIMARY KEY,
+  id_pw INTEGER NOT NULL REFERENCES provinsi (id_prov),
+  alamat VARCHAR(255) DEFAULT NULL,
+  kelurahan VARCHAR(100) DEFAULT NULL,
+  kecamatan VARCHAR(100) DEFAULT NULL,
+  kabupaten VARCHAR(100) DEFAULT NULL,
+  lintang VARCHAR(50) DEFAULT NULL,
+  bujur VARCHAR(50) DEFAULT NULL,
+  website VARCHAR(50) DEFAULT NULL,
+  ketua VARCHAR(100) DEFAULT NULL,
+  telp_ketua VARCHAR(15) DEFAULT NULL,
+  wakil_ketua VARCHAR(100) DEFAULT NULL,
+  telp_wakil VARCHAR(15) DEFAULT NULL,
+  bendahara VARCHAR(100) DEFAULT NULL,
+  telp_bendahara VARCHAR(15) DEFAULT NULL,
+  sekretaris VARCHAR(100) DEFAULT NULL,
+  telp_sekretaris VARCHAR(15) DEFAULT NULL,
+  masa_khidmat VARCHAR(50) DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE jenjang_pendidikan (
+  id_jenjang INTEGER PRIMARY KEY,
+  nm_jenjang VARCHAR(45) COLLATE NOCASE NOT NULL,
+  keterangan VARCHAR(255) DEFAULT NULL,
+  lembaga TEXT DEFAULT NULL CHECK (lembaga IN ('MADRASAH', 'SEKOLAH')),
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE kategori_satpen (
+  id_kategori INTEGER PRIMARY KEY,
+  nm_kategori TEXT COLLATE NOCASE NOT NULL CHECK (nm_kategori IN ('A', 'B', 'C', 'D')),
+  konotasi VARCHAR(100) NOT NULL,
+  keterangan VARCHAR(255) DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE tahun_pelajaran (
+  id INTEGER PRIMARY KEY,
+  tapel_dapo VARCHAR(50) UNIQUE DEFAULT NULL,
+  nama_tapel VARCHAR(50) DEFAULT NULL
+);
+
+CREATE TABLE satpen (
+  id_satpen INTEGER PRIMARY KEY,
+  id_user INTEGER NOT NULL UNIQUE,
+  id_prov INTEGER NOT NULL REFERENCES provinsi (id_prov),
+  id_kab INTEGER NOT NULL REFERENCES kabupaten (id_kab),
+  id_pc INTEGER NOT NULL REFERENCES pengurus_cabang (id_pc),
+  id_kategori INTEGER DEFAULT NULL REFERENCES kategori_satpen (id_kategori),
+  id_jenjang INTEGER NOT NULL REFERENCES jenjang_pendidikan (id_jenjang),
+  npsn VARCHAR(45) NOT NULL UNIQUE,
+  no_registrasi VARCHAR(45) NOT NULL UNIQUE,
+  no_urut VARCHAR(10) NOT NULL UNIQUE,
+  nm_satpen VARCHAR(255) COLLATE NOCASE NOT NULL,
+  yayasan VARCHAR(255) COLLATE NOCASE NOT NULL,
+  kepsek VARCHAR(100) DEFAULT NULL,
+  telpon VARCHAR(15) DEFAULT NULL,
+  fax VARCHAR(15) DEFAULT NULL,
+  email VARCHAR(100) DEFAULT NULL,
+  thn_berdiri INTEGER DEFAULT NULL,
+  kecamatan VARCHAR(255) COLLATE NOCASE NOT NULL,
+  kelurahan VARCHAR(255) COLLATE NOCASE NOT NULL,
+  alamat TEXT COLLATE NOCASE NOT NULL,
+  aset_tanah VARCHAR(45) DEFAULT NULL,
+  nm_pemilik VARCHAR(100) DEFAULT NULL,
+  tgl_registrasi DATETIME NOT NULL,
+  actived_date DATETIME DEFAULT NULL,
+  status TEXT NOT NULL CHECK (status IN ('permohonan', 'revisi', 'proses dokumen', 'setujui', 'expired', 'perpanjangan')),
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL,
+  lintang DECIMAL(10,7) DEFAULT NULL,
+  bujur DECIMAL(11,7) DEFAULT NULL
+);
+
+CREATE TABLE pdptk (
+  id INTEGER PRIMARY KEY,
+  id_satpen INTEGER DEFAULT NULL REFERENCES satpen (id_satpen),
+  tapel VARCHAR(10) DEFAULT NULL REFERENCES tahun_pelajaran (tapel_dapo),
+  pd_lk INTEGER DEFAULT NULL,
+  pd_pr INTEGER DEFAULT NULL,
+  jml_pd INTEGER DEFAULT NULL,
+  guru_lk INTEGER DEFAULT NULL,
+  guru_pr INTEGER DEFAULT NULL,
+  jml_guru INTEGER DEFAULT NULL,
+  tendik_lk INTEGER DEFAULT NULL,
+  tendik_pr INTEGER DEFAULT NULL,
+  jml_tendik INTEGER DEFAULT NULL,
+  last_sinkron DATETIME DEFAULT NULL,
+  status_sinkron INTEGER DEFAULT NULL
+);
+
+-- Only the ptk columns the repositories read
+CREATE TABLE ptk (
+  id INTEGER PRIMARY KEY,
+  id_satpen INTEGER NOT NULL REFERENCES satpen (id_satpen),
+  nik VARCHAR(16) NOT NULL UNIQUE,
+  nama_ptk VARCHAR(255) NOT NULL,
+  status_ajuan TEXT DEFAULT 'verifikasi' CHECK (status_ajuan IN ('verifikasi', 'revisi', 'proses', 'approve', 'dikeluarkan')),
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE timeline_reg (
+  id_timeline INTEGER PRIMARY KEY,
+  id_satpen INTEGER NOT NULL REFERENCES satpen (id_satpen),
+  status_verifikasi VARCHAR(45) NOT NULL,
+  tgl_status DATETIME NOT NULL,
+  keterangan TEXT DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NULL,
+  updated_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE audit_log (
+  id INTEGER PRIMARY KEY,
+  actor VARCHAR(100) NOT NULL,
+  action VARCHAR(50) NOT NULL,
+  entity_type VARCHAR(50) NOT NULL,
+  entity_id INTEGER NOT NULL,
+  before_data TEXT DEFAULT NULL,
+  after_data TEXT DEFAULT NULL,
+  changes TEXT DEFAULT NULL,
+  ip VARCHAR(45) DEFAULT NULL,
+  request_id VARCHAR(64) DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NULL
+);
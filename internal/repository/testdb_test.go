@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"satpen-api/internal/models"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The repository tests run against an in-memory SQLite database with the
+// schema of testdata/schema.sql and the rows of testdata/fixtures.sql. The
+// MySQL functions the queries use, and REGEXP, are registered on every
+// connection, and addYears picks the SQLite syntax. FULLTEXT search (MATCH ... AGAINST), the
+// haversine distance of FindNearby and the TIMESTAMP literals of the
+// created_at/updated_at cursors have no SQLite equivalent and are not
+// covered here.
+
+// testDriver is the database/sql driver of the test database
+const testDriver = "sqlite3_satpen"
+
+func init() {
+	sql.Register(testDriver, &sqlite3.SQLiteDriver{ConnectHook: registerMySQLFunctions})
+}
+
+// registerMySQLFunctions adds YEAR, MONTH and QUARTER, and the regexp
+// function behind SQLite's REGEXP operator. SQLite stores datetimes as
+// "YYYY-MM-DD HH:MM:SS..." text, which is what they receive.
+func registerMySQLFunctions(conn *sqlite3.SQLiteConn) error {
+	functions := map[string]func(string) int{
+		"YEAR":    func(value string) int { return datePart(value, 0, 4) },
+		"MONTH":   func(value string) int { return datePart(value, 5, 7) },
+		"QUARTER": func(value string) int { return (datePart(value, 5, 7) + 2) / 3 },
+	}
+	for name, fn := range functions {
+		if err := conn.RegisterFunc(name, fn, true); err != nil {
+			return err
+		}
+	}
+	return conn.RegisterFunc("regexp", func(pattern, value string) (bool, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(value), nil
+	}, true)
+}
+
+// datePart parses value[from:to] as a number, 0 when it is not one
+func datePart(value string, from, to int) int {
+	if len(value) < to {
+		return 0
+	}
+	n, _ := strconv.Atoi(value[from:to])
+	return n
+}
+
+// newTestDB opens a fresh database with the schema and fixtures loaded
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.New(sqlite.Config{DriverName: testDriver, DSN: ":memory:"}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	// Every connection to :memory: is a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	for _, file := range []string{"testdata/schema.sql", "testdata/fixtures.sql"} {
+		script, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		if err := db.Exec(string(script)).Error; err != nil {
+			t.Fatalf("load %s: %v", file, err)
+		}
+	}
+	return db
+}
+
+// newTestSatpenRepository returns a satpenRepository on a fresh test database
+func newTestSatpenRepository(t *testing.T) *satpenRepository {
+	t.Helper()
+	return &satpenRepository{db: newTestDB(t)}
+}
+
+// satpenIDs returns the IDs of satpen in order
+func satpenIDs(satpen []models.Satpen) []uint {
+	ids := make([]uint, 0, len(satpen))
+	for _, s := range satpen {
+		ids = append(ids, s.IDSatpen)
+	}
+	return ids
+}
+
+// sortedIDs returns the IDs of satpen in ascending order
+func sortedIDs(satpen []models.Satpen) []uint {
+	ids := satpenIDs(satpen)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+func intPtr(v int) *int { return &v }
+
+func datePtr(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
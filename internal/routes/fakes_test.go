@@ -0,0 +1,527 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"satpen-api/internal/audit"
+	"satpen-api/internal/cluster"
+	"satpen-api/internal/duplicate"
+	"satpen-api/internal/geojson"
+	"satpen-api/internal/models"
+	"satpen-api/internal/service"
+	"satpen-api/internal/suggest"
+	"strconv"
+	"time"
+)
+
+// The fakes answer with fixed data and record the arguments the handlers
+// pass. Conventions shared by all of them: ID 99 is not found, and a search
+// (or query) of "fail" makes the service fail with errFake.
+
+const notFoundID = 99
+
+var errFake = errors.New("database unavailable")
+
+var fixedTime = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func fakeSatpen(id uint) models.Satpen {
+	lat, lng := -7.89, 112.66
+	activedDate := fixedTime
+	kategoriID := uint(1)
+	s := models.Satpen{
+		IDSatpen:      id,
+		IDProv:        1,
+		Provinsi:      &models.Provinsi{IDProv: 1, Map: "id-ji", KodeProv: "35", NmProv: "Jawa Timur", CreatedAt: fixedTime, UpdatedAt: fixedTime},
+		IDKab:         1,
+		Kabupaten:     &models.Kabupaten{IDKab: 1, IDProv: 1, NamaKab: "Kabupaten Malang", CreatedAt: fixedTime, UpdatedAt: fixedTime},
+		IDKategori:    &kategoriID,
+		Kategori:      &models.KategoriSatpen{IDKategori: 1, NmKategori: "A", Konotasi: "Unggul", CreatedAt: fixedTime, UpdatedAt: fixedTime},
+		IDJenjang:     1,
+		Jenjang:       &models.JenjangPendidikan{IDJenjang: 1, NmJenjang: "MI", Lembaga: "MADRASAH", CreatedAt: fixedTime, UpdatedAt: fixedTime},
+		NPSN:          "2050700" + strconv.Itoa(int(id)),
+		NoRegistrasi:  "REG-000" + strconv.Itoa(int(id)),
+		NoUrut:        "000" + strconv.Itoa(int(id)),
+		NmSatpen:      "MI Ma'arif NU 0" + strconv.Itoa(int(id)) + " Singosari",
+		Yayasan:       "Yayasan Pendidikan Ma'arif Malang",
+		ThnBerdiri:    1985,
+		Kecamatan:     "Singosari",
+		Kelurahan:     "Pagentan",
+		Alamat:        "Jl. Raya Singosari 12",
+		TglRegistrasi: fixedTime.AddDate(0, -1, 0),
+		ActivedDate:   &activedDate,
+		Status:        "setujui",
+		CreatedAt:     fixedTime.AddDate(0, -1, 0),
+		UpdatedAt:     fixedTime,
+		Lintang:       &lat,
+		Bujur:         &lng,
+		PDPTK:         &models.PDPTK{ID: int(id), Tapel: "20241", PDLK: 85, PDPR: 95, JmlPD: 180, GuruLK: 5, GuruPR: 7, JmlGuru: 12, TendikLK: 2, TendikPR: 1, JmlTendik: 3},
+	}
+	// As loaded from the database
+	_ = s.AfterFind(nil)
+	return s
+}
+
+func fakeStatistics() *models.SatpenStatistics {
+	return &models.SatpenStatistics{
+		TotalSatpen:         2,
+		TotalProvinsi:       1,
+		TotalKabupaten:      1,
+		TotalPengurusCabang: 1,
+		TotalSiswa:          360,
+		TotalGuru:           24,
+		ByJenjang:           map[string]models.JenjangStats{"MI": {Count: 2, Siswa: 360, Guru: 24}},
+		ByAkreditasi:        map[string]int64{"A (Unggul)": 2},
+		TopProvinsi:         []models.ProvinsiStats{{ID: 1, Provinsi: "Jawa Timur", Count: 2, Siswa: 360, Guru: 24}},
+		TopKabupaten:        []models.KabupatenStats{{ID: 1, Kabupaten: "Kabupaten Malang", Count: 2, Siswa: 360, Guru: 24}},
+		TopPengurusCabang:   []models.PengurusCabangStats{{ID: 1, PengurusCabang: "PCNU Kabupaten Malang", Count: 2, Siswa: 360, Guru: 24}},
+	}
+}
+
+func pagination(page, limit int, total int64) *service.PaginationMeta {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return &service.PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   total,
+		ItemsPerPage: limit,
+		HasNext:      page < totalPages,
+		HasPrev:      page > 1,
+	}
+}
+
+type fakeSatpenService struct {
+	filter       *models.SatpenFilter
+	page, limit  int
+	sort         string
+	includeStats bool
+	cursor       string
+	withCount    bool
+	topN         int
+	path         service.DrillDownPath
+	interval     string
+	ctx          context.Context
+}
+
+func (f *fakeSatpenService) GetAllSatpen(filter *models.SatpenFilter, page, limit int, sort string, includeStats bool, fieldSet *service.SatpenFieldSet) ([]models.Satpen, *service.PaginationMeta, *models.SatpenStatistics, error) {
+	f.filter, f.page, f.limit, f.sort, f.includeStats = filter, page, limit, sort, includeStats
+	if filter.Search == "fail" {
+		return nil, nil, nil, errFake
+	}
+	var stats *models.SatpenStatistics
+	if includeStats {
+		stats = fakeStatistics()
+	}
+	return []models.Satpen{fakeSatpen(1), fakeSatpen(2)}, pagination(page, limit, 42), stats, nil
+}
+
+func (f *fakeSatpenService) GetAllSatpenCursor(filter *models.SatpenFilter, cursor string, limit int, sort string, withCount, includeStats bool, fieldSet *service.SatpenFieldSet) ([]models.Satpen, *service.CursorPaginationMeta, *models.SatpenStatistics, error) {
+	f.filter, f.cursor, f.limit, f.sort, f.withCount, f.includeStats = filter, cursor, limit, sort, withCount, includeStats
+	if cursor == "garbage" {
+		return nil, nil, nil, service.ErrInvalidCursor
+	}
+	meta := &service.CursorPaginationMeta{ItemsPerPage: limit, HasNext: true, HasPrev: cursor != "", NextCursor: "eyJpZCI6Mn0"}
+	if withCount {
+		total := int64(42)
+		meta.TotalItems = &total
+	}
+	return []models.Satpen{fakeSatpen(1), fakeSatpen(2)}, meta, nil, nil
+}
+
+func (f *fakeSatpenService) GetSatpenByID(id string, fieldSet *service.SatpenFieldSet) (*models.Satpen, error) {
+	switch id {
+	case strconv.Itoa(notFoundID):
+		return nil, errors.New("satuan pendidikan not found")
+	case "fail":
+		return nil, errFake
+	}
+	s := fakeSatpen(1)
+	s.DataQuality = &models.DataQuality{Score: 100, Issues: []models.DataQualityIssue{}}
+	return &s, nil
+}
+
+func (f *fakeSatpenService) GetNearbySatpen(lat, lng, radiusKm float64, limit int, filter *models.SatpenFilter, fieldSet *service.SatpenFieldSet) ([]models.Satpen, float64, error) {
+	f.filter, f.limit = filter, limit
+	if radiusKm > 50 {
+		return nil, 0, service.ErrRadius
+	}
+	if radiusKm == 0 {
+		radiusKm = 5
+	}
+	s := fakeSatpen(1)
+	distance := 1.25
+	s.Distance = &distance
+	return []models.Satpen{s}, radiusKm, nil
+}
+
+func (f *fakeSatpenService) UpdateSatpenLocation(ctx context.Context, id uint, lat, lng *float64) (*models.Satpen, error) {
+	f.ctx = ctx
+	if id == notFoundID {
+		return nil, errors.New("satuan pendidikan not found")
+	}
+	if (lat == nil) != (lng == nil) {
+		return nil, service.ErrPartialLocation
+	}
+	s := fakeSatpen(id)
+	s.Lintang, s.Bujur, s.Coordinates = lat, lng, nil
+	if lat != nil {
+		s.Coordinates = &models.Coordinates{Latitude: *lat, Longitude: *lng}
+	}
+	return &s, nil
+}
+
+func (f *fakeSatpenService) GetStatistics(filter *models.SatpenFilter, topN int) (*models.SatpenStatistics, error) {
+	f.filter, f.topN = filter, topN
+	if filter.Search == "fail" {
+		return nil, errFake
+	}
+	return fakeStatistics(), nil
+}
+
+func (f *fakeSatpenService) ExportSatpen(filter *models.SatpenFilter, sort string) (*bytes.Buffer, string, error) {
+	f.filter, f.sort = filter, sort
+	return bytes.NewBufferString("PK fake xlsx"), "data-satpen-20240301-100000.xlsx", nil
+}
+
+func (f *fakeSatpenService) ExportSatpenGeoJSON(filter *models.SatpenFilter, sort string, fieldSet *service.SatpenFieldSet) (*geojson.FeatureCollection, string, error) {
+	f.filter, f.sort = filter, sort
+	fc, err := service.SatpenFeatureCollection([]models.Satpen{fakeSatpen(1)}, fieldSet)
+	if err != nil {
+		return nil, "", err
+	}
+	return fc, "data-satpen-20240301-100000.geojson", nil
+}
+
+func (f *fakeSatpenService) GetSatpenRegions(level string, filter *models.SatpenFilter) (*geojson.FeatureCollection, error) {
+	f.filter = filter
+	fc := geojson.NewFeatureCollection()
+	fc.Add(uint(1), geojson.NewPoint(-7.6, 112.7), map[string]interface{}{"level": level, "nama": "Jawa Timur", "count": 2})
+	return fc, nil
+}
+
+func (f *fakeSatpenService) GetDrillDown(path service.DrillDownPath, filter *models.SatpenFilter) (*models.DrillDown, error) {
+	f.path, f.filter = path, filter
+	return &models.DrillDown{
+		Level:       "kabupaten",
+		TotalSatpen: 2,
+		TotalSiswa:  360,
+		TotalGuru:   24,
+		Nodes:       []models.DrillDownNode{{ID: 1, Nama: "Kabupaten Malang", Count: 2, Siswa: 360, Guru: 24}},
+	}, nil
+}
+
+func (f *fakeSatpenService) GetTrend(interval string, from, to *time.Time, filter *models.SatpenFilter) (*models.Trend, error) {
+	f.interval, f.filter = interval, filter
+	return &models.Trend{
+		Interval:      interval,
+		From:          "2024-01-01",
+		To:            "2024-06-30",
+		Registrations: 3,
+		Approvals:     2,
+		Points: []models.TrendPoint{
+			{Period: "2024-Q1", Start: "2024-01-01", Registrations: 2, Approvals: 1, Active: 10},
+			{Period: "2024-Q2", Start: "2024-04-01", Registrations: 1, Approvals: 1, Active: 11},
+		},
+	}, nil
+}
+
+func (f *fakeSatpenService) GetIndikatorRanking(indikator, groupBy string, asc, withFlagged bool, filter *models.SatpenFilter) (*models.IndikatorRanking, error) {
+	f.filter = filter
+	median := 15.0
+	ranking := &models.IndikatorRanking{
+		Indikator: indikator,
+		GroupBy:   groupBy,
+		Overall:   models.IndikatorStats{Count: 2, Median: &median},
+		Groups:    []models.IndikatorGroup{{Rank: 1, ID: 1, Nama: "Jawa Timur", IndikatorStats: models.IndikatorStats{Count: 2, Median: &median}}},
+	}
+	if withFlagged {
+		ranking.Flagged = []models.IndikatorFlagged{{ID: 2, NPSN: "20507002", Nama: "MTs NU Lawang", Group: "Jawa Timur", Value: 32, Flag: "above"}}
+	}
+	return ranking, nil
+}
+
+func (f *fakeSatpenService) GetDataQualityIssues(filter *models.SatpenFilter, rules []string, page, limit int) ([]models.DataQualityItem, *service.PaginationMeta, *models.DataQualitySummary, error) {
+	f.filter, f.page, f.limit = filter, page, limit
+	items := []models.DataQualityItem{{
+		ID:        3,
+		NPSN:      "2050700",
+		Nama:      "SMK Ma'arif Surabaya",
+		Provinsi:  "Jawa Timur",
+		Kabupaten: "Kota Surabaya",
+		DataQuality: models.DataQuality{
+			Score:  75,
+			Issues: []models.DataQualityIssue{{Rule: "npsn_invalid", Severity: "error", Field: "npsn", Message: "NPSN must be 8 digits"}},
+		},
+	}}
+	summary := &models.DataQualitySummary{Checked: 10, WithIssues: 1, AverageScore: 97.5, ByRule: map[string]int64{"npsn_invalid": 1}}
+	return items, pagination(page, limit, 1), summary, nil
+}
+
+func (f *fakeSatpenService) FindDuplicates(filter *models.SatpenFilter, minScore float64, limit int) (*service.DuplicateReport, error) {
+	f.filter, f.limit = filter, limit
+	if minScore == 0 {
+		minScore = 0.8
+	}
+	return &service.DuplicateReport{MinScore: minScore, Checked: 10, Total: 0, Pairs: []duplicate.Pair{}}, nil
+}
+
+func (f *fakeSatpenService) MergeSatpen(ctx context.Context, survivorID, loserID uint, preview bool) (*models.MergeResult, error) {
+	f.ctx = ctx
+	if survivorID == notFoundID || loserID == notFoundID {
+		return nil, errors.New("satuan pendidikan not found")
+	}
+	if survivorID == loserID {
+		return nil, service.ErrMergeSame
+	}
+	result := &models.MergeResult{
+		SurvivorID:    survivorID,
+		LoserID:       loserID,
+		Preview:       preview,
+		PDPTKMoved:    []string{"20231"},
+		PDPTKDropped:  []string{"20241"},
+		PTKMoved:      2,
+		TimelineMoved: 1,
+	}
+	if !preview {
+		result.AuditID = 7
+	}
+	return result, nil
+}
+
+type fakeMasterService struct {
+	search     string
+	provinsiID uint
+	filters    map[string]interface{}
+	page       int
+	limit      int
+}
+
+func fakeProvinsi() models.Provinsi {
+	return models.Provinsi{IDProv: 1, Map: "id-ji", KodeProv: "35", NmProv: "Jawa Timur", CreatedAt: fixedTime, UpdatedAt: fixedTime}
+}
+
+func fakeKabupaten() models.Kabupaten {
+	p := fakeProvinsi()
+	return models.Kabupaten{IDKab: 1, IDProv: 1, Provinsi: &p, NamaKab: "Kabupaten Malang", CreatedAt: fixedTime, UpdatedAt: fixedTime}
+}
+
+func fakePengurusCabang() models.PengurusCabang {
+	updatedAt := fixedTime
+	return models.PengurusCabang{
+		IDPC:    1,
+		IDProv:  1,
+		KodeKab: "3507",
+		NamaPC:  "PCNU Kabupaten Malang",
+		Profile: &models.ProfilePengurusCabang{
+			ID:             2,
+			IDPC:           1,
+			ProfilPengurus: models.ProfilPengurus{Alamat: "Jl. Kauman 3", Ketua: "Slamet Santoso", MasaKhidmat: "2023-2028"},
+			UpdatedAt:      &updatedAt,
+		},
+		CreatedAt: fixedTime,
+		UpdatedAt: fixedTime,
+	}
+}
+
+func fakePengurusWilayah() models.PengurusWilayah {
+	return models.PengurusWilayah{IDProv: 1, KodeProv: "35", NmProv: "Jawa Timur", JumlahCabang: 1, PengurusCabang: []models.PengurusCabang{fakePengurusCabang()}}
+}
+
+func fakeKategori() models.KategoriSatpenWithCount {
+	return models.KategoriSatpenWithCount{
+		KategoriSatpen: models.KategoriSatpen{IDKategori: 1, NmKategori: "A", Konotasi: "Unggul", Keterangan: "Terakreditasi A", CreatedAt: fixedTime, UpdatedAt: fixedTime},
+		JumlahSatpen:   3,
+	}
+}
+
+func fakeJenjang() models.JenjangPendidikan {
+	return models.JenjangPendidikan{IDJenjang: 1, NmJenjang: "MI", Keterangan: "Madrasah Ibtidaiyah", Lembaga: "MADRASAH", CreatedAt: fixedTime, UpdatedAt: fixedTime}
+}
+
+// masterLookup answers the ByID methods: record not found for notFoundID
+func masterLookup[T any](id uint, v T) (*T, error) {
+	if id == notFoundID {
+		return nil, errors.New("record not found")
+	}
+	return &v, nil
+}
+
+func (f *fakeMasterService) GetAllProvinsi(search string) ([]models.Provinsi, error) {
+	f.search = search
+	if search == "fail" {
+		return nil, errFake
+	}
+	return []models.Provinsi{fakeProvinsi()}, nil
+}
+
+func (f *fakeMasterService) GetProvinsiByID(id uint) (*models.Provinsi, error) {
+	return masterLookup(id, fakeProvinsi())
+}
+
+func (f *fakeMasterService) GetAllKabupaten(provinsiID uint, search string) ([]models.Kabupaten, error) {
+	f.provinsiID, f.search = provinsiID, search
+	return []models.Kabupaten{fakeKabupaten()}, nil
+}
+
+func (f *fakeMasterService) GetKabupatenByID(id uint) (*models.Kabupaten, error) {
+	return masterLookup(id, fakeKabupaten())
+}
+
+func (f *fakeMasterService) GetAllPengurusCabang(filters map[string]interface{}, page, limit int) ([]models.PengurusCabang, int64, error) {
+	f.filters, f.page, f.limit = filters, page, limit
+	return []models.PengurusCabang{fakePengurusCabang()}, 25, nil
+}
+
+func (f *fakeMasterService) GetPengurusCabangByID(id uint) (*models.PengurusCabang, error) {
+	return masterLookup(id, fakePengurusCabang())
+}
+
+func (f *fakeMasterService) GetAllPengurusWilayah(search string) ([]models.PengurusWilayah, error) {
+	f.search = search
+	return []models.PengurusWilayah{fakePengurusWilayah()}, nil
+}
+
+func (f *fakeMasterService) GetPengurusWilayahByProvinsi(idProv uint) (*models.PengurusWilayah, error) {
+	return masterLookup(idProv, fakePengurusWilayah())
+}
+
+func (f *fakeMasterService) GetAllKategoriSatpen(search string) ([]models.KategoriSatpenWithCount, error) {
+	f.search = search
+	return []models.KategoriSatpenWithCount{fakeKategori()}, nil
+}
+
+func (f *fakeMasterService) GetKategoriSatpenByID(id uint) (*models.KategoriSatpenWithCount, error) {
+	return masterLookup(id, fakeKategori())
+}
+
+func (f *fakeMasterService) GetAllJenjangPendidikan(search string) ([]models.JenjangPendidikan, error) {
+	f.search = search
+	return []models.JenjangPendidikan{fakeJenjang()}, nil
+}
+
+func (f *fakeMasterService) GetJenjangPendidikanByID(id uint) (*models.JenjangPendidikan, error) {
+	return masterLookup(id, fakeJenjang())
+}
+
+type fakeSyncService struct {
+	ctx context.Context
+}
+
+func (f *fakeSyncService) Start(ctx context.Context) {}
+
+func (f *fakeSyncService) RunOnce(ctx context.Context) (*service.SyncRunResult, error) {
+	return &service.SyncRunResult{}, nil
+}
+
+func (f *fakeSyncService) SyncSatpen(ctx context.Context, id uint) (*service.SyncResult, error) {
+	f.ctx = ctx
+	switch id {
+	case notFoundID:
+		return nil, errors.New("satuan pendidikan not found")
+	case 2:
+		return nil, errors.New("satuan pendidikan has no NPSN")
+	case 3:
+		return &service.SyncResult{IDSatpen: id, NPSN: "20507003", Tapel: "20241", Attempts: 3, Error: "dapodik: status 503"}, nil
+	}
+	s := fakeSatpen(id)
+	return &service.SyncResult{IDSatpen: id, NPSN: s.NPSN, Tapel: "20241", Success: true, Attempts: 1, PDPTK: s.PDPTK}, nil
+}
+
+type fakeSuggestService struct {
+	query string
+	limit int
+}
+
+func (f *fakeSuggestService) Refresh() error { return nil }
+
+func (f *fakeSuggestService) Start(ctx context.Context) {}
+
+func (f *fakeSuggestService) Suggest(query string, limit int) ([]suggest.Match, error) {
+	f.query, f.limit = query, limit
+	if query == "notready" {
+		return nil, errors.New("suggest index not ready")
+	}
+	return []suggest.Match{{
+		Document: suggest.Document{ID: 1, NPSN: "20507001", Name: "MI Ma'arif NU 01 Singosari", Jenjang: "MI", Kabupaten: "Kabupaten Malang"},
+		Score:    1.2,
+	}}, nil
+}
+
+type fakeClusterService struct {
+	bbox    cluster.BBox
+	zoom    int
+	jenjang []string
+}
+
+func (f *fakeClusterService) Refresh() error { return nil }
+
+func (f *fakeClusterService) Start(ctx context.Context) {}
+
+func (f *fakeClusterService) Clusters(bbox cluster.BBox, zoom int, jenjang []string) (*cluster.Result, error) {
+	f.bbox, f.zoom, f.jenjang = bbox, zoom, jenjang
+	point := cluster.Point{ID: 5, NPSN: "20507005", Name: "MTs Ma'arif Kudus", Jenjang: "MTs", Latitude: -6.8, Longitude: 110.84}
+	if zoom >= 14 {
+		// Individual points, more than max_points in the bbox
+		return &cluster.Result{Clusters: []cluster.Cluster{}, Points: []cluster.Point{point}, Truncated: true, Omitted: 4}, nil
+	}
+	return &cluster.Result{
+		Clusters: []cluster.Cluster{{Count: 3, Latitude: -7.85, Longitude: 112.68, BBox: [4]float64{112.66, -7.89, 112.7, -7.83}}},
+		Points:   []cluster.Point{point},
+	}, nil
+}
+
+type fakeDashboardService struct{}
+
+func (f *fakeDashboardService) GetPengurusCabangDashboard(idPC uint) (*models.PengurusCabangDashboard, error) {
+	if idPC == notFoundID {
+		return nil, errors.New("pengurus cabang not found")
+	}
+	pc := fakePengurusCabang()
+	return &models.PengurusCabangDashboard{
+		PengurusCabang: &pc,
+		TotalSatpen:    3,
+		TotalSiswa:     500,
+		TotalGuru:      32,
+		TotalTendik:    7,
+		ByJenjang: map[string]models.DashboardJenjang{
+			"MI": {Count: 2, ByStatus: map[string]int64{"setujui": 1, "revisi": 1}, Siswa: 180, Guru: 12, Tendik: 3},
+		},
+		ByStatus:             map[string]int64{"setujui": 2, "revisi": 1},
+		ByAkreditasi:         map[string]int64{"A (Unggul)": 2},
+		PendingRegistrations: models.PendingRegistrations{Total: 1, ByStatus: map[string]int64{"revisi": 1}},
+		ExpiringSoon: &models.ExpiringSoon{WithinDays: 90, Total: 1, Satpen: []models.ExpiringSatpen{
+			{ID: 1, NPSN: "20507001", Nama: "MI Ma'arif NU 01 Singosari", Jenjang: "MI", ActivedDate: fixedTime.AddDate(-5, 0, 0), ExpiresAt: fixedTime},
+		}},
+		PTKPendingVerification: 2,
+	}, nil
+}
+
+type fakeAuditService struct {
+	filter *models.AuditFilter
+	page   int
+	limit  int
+}
+
+func (f *fakeAuditService) GetAuditLog(filter *models.AuditFilter, page, limit int) ([]models.AuditLog, *service.PaginationMeta, error) {
+	f.filter, f.page, f.limit = filter, page, limit
+	entries := []models.AuditLog{{
+		ID:         7,
+		Actor:      "test",
+		Action:     models.AuditActionUpdateLocation,
+		EntityType: "satpen",
+		EntityID:   1,
+		Changes:    []byte(`{"lintang":{"from":null,"to":-7.89}}`),
+		IP:         "192.0.2.1",
+		RequestID:  "test-request-id",
+		CreatedAt:  fixedTime,
+	}}
+	return entries, pagination(page, limit, 1), nil
+}
+
+// auditActor returns the audit actor a fake saw in its request context
+func auditActor(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	return audit.FromContext(ctx).Actor
+}
@@ -16,9 +16,15 @@ func SetupRoutes(
 	satpenHandler *handler.SatpenHandler,
 	masterHandler *handler.MasterHandler,
 	healthHandler *handler.HealthHandler,
+	syncHandler *handler.SyncHandler,
+	suggestHandler *handler.SuggestHandler,
+	clusterHandler *handler.ClusterHandler,
+	dashboardHandler *handler.DashboardHandler,
+	auditHandler *handler.AuditHandler,
 ) {
 	// Middleware
 	// r.Use(middleware.CORS(cfg))
+	r.Use(middleware.RequestID())
 	r.Use(middleware.Logger(log))
 	r.Use(gin.Recovery())
 
@@ -37,7 +43,14 @@ func SetupRoutes(
 		{
 			satpen.GET("", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), satpenHandler.GetAllSatpen)
 			satpen.GET("/statistics", middleware.RateLimit(cfg, cfg.RateLimit.Statistics), satpenHandler.GetStatistics)
+			satpen.GET("/statistics/drilldown", middleware.RateLimit(cfg, cfg.RateLimit.Statistics), satpenHandler.GetDrillDown)
+			satpen.GET("/statistics/trend", middleware.RateLimit(cfg, cfg.RateLimit.Statistics), satpenHandler.GetStatisticsTrend)
+			satpen.GET("/statistics/indicators", middleware.RateLimit(cfg, cfg.RateLimit.Statistics), satpenHandler.GetIndikatorRanking)
 			satpen.GET("/export", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), satpenHandler.DownloadExcel)
+			satpen.GET("/suggest", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), suggestHandler.Suggest)
+			satpen.GET("/nearby", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), satpenHandler.GetNearbySatpen)
+			satpen.GET("/clusters", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), clusterHandler.GetClusters)
+			satpen.GET("/geojson/regions", middleware.RateLimit(cfg, cfg.RateLimit.Statistics), satpenHandler.GetSatpenRegions)
 			satpen.GET("/:id", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), satpenHandler.GetSatpenByID)
 		}
 
@@ -60,6 +73,14 @@ func SetupRoutes(
 		{
 			pengurusCabang.GET("", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetAllPengurusCabang)
 			pengurusCabang.GET("/:id", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetPengurusCabangByID)
+			pengurusCabang.GET("/:id/dashboard", middleware.RateLimit(cfg, cfg.RateLimit.Statistics), dashboardHandler.GetPengurusCabangDashboard)
+		}
+
+		// Pengurus Wilayah endpoints (keyed by provinsi)
+		pengurusWilayah := v1.Group("/pengurus-wilayah")
+		{
+			pengurusWilayah.GET("", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetAllPengurusWilayah)
+			pengurusWilayah.GET("/:id", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetPengurusWilayahByProvinsi)
 		}
 
 		// Jenjang Pendidikan endpoints
@@ -68,5 +89,31 @@ func SetupRoutes(
 			jenjangPendidikan.GET("", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetAllJenjangPendidikan)
 			jenjangPendidikan.GET("/:id", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetJenjangPendidikanByID)
 		}
+
+		// Kategori Satpen (akreditasi) endpoints
+		kategoriSatpen := v1.Group("/kategori-satpen")
+		{
+			kategoriSatpen.GET("", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetAllKategoriSatpen)
+			kategoriSatpen.GET("/:id", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), masterHandler.GetKategoriSatpenByID)
+		}
+
+		// Data quality endpoints
+		dataQuality := v1.Group("/data-quality")
+		{
+			dataQuality.GET("/rules", middleware.RateLimit(cfg, cfg.RateLimit.Satpen), satpenHandler.GetDataQualityRules)
+			dataQuality.GET("/issues", middleware.RateLimit(cfg, cfg.RateLimit.Statistics), satpenHandler.GetDataQualityIssues)
+		}
+
+		// Admin endpoints (X-API-Key)
+		admin := v1.Group("/admin", middleware.AdminAuth(cfg), middleware.AuditContext())
+		{
+			admin.POST("/satpen/:id/sync", syncHandler.SyncSatpen)
+			admin.PUT("/satpen/:id/location", satpenHandler.UpdateSatpenLocation)
+			admin.GET("/satpen/duplicates", satpenHandler.FindDuplicates)
+			admin.POST("/satpen/merge", satpenHandler.MergeSatpen)
+		}
+
+		// Audit log (X-API-Key)
+		v1.GET("/audit", middleware.AdminAuth(cfg), auditHandler.GetAuditLog)
 	}
 }
@@ -0,0 +1,540 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"satpen-api/internal/config"
+	"satpen-api/internal/database"
+	"satpen-api/internal/handler"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+const (
+	testAdminKey  = "test-admin-key"
+	testRequestID = "test-request-id"
+)
+
+// testServer is the router of SetupRoutes wired to the fake services
+type testServer struct {
+	router    *gin.Engine
+	satpen    *fakeSatpenService
+	master    *fakeMasterService
+	sync      *fakeSyncService
+	suggest   *fakeSuggestService
+	cluster   *fakeClusterService
+	dashboard *fakeDashboardService
+	audit     *fakeAuditService
+	// answered holds "METHOD /route/:pattern" of routes that answered below 400
+	answered map[string]bool
+}
+
+func testConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.App.Name = "Satpen API"
+	cfg.App.Version = "1.0.0"
+	cfg.API.BasePath = "/api/v1"
+	cfg.Monitoring.Enabled = true
+	cfg.Monitoring.HealthCheckPath = "/health"
+	cfg.Admin.Enabled = true
+	cfg.Admin.APIKeys = []config.AdminAPIKey{{Name: "test", Key: testAdminKey}}
+	return cfg
+}
+
+func newTestServer(t *testing.T, cfg *config.Config) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	// The health check pings database.DB
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	s := &testServer{
+		router:    gin.New(),
+		satpen:    &fakeSatpenService{},
+		master:    &fakeMasterService{},
+		sync:      &fakeSyncService{},
+		suggest:   &fakeSuggestService{},
+		cluster:   &fakeClusterService{},
+		dashboard: &fakeDashboardService{},
+		audit:     &fakeAuditService{},
+		answered:  make(map[string]bool),
+	}
+	s.router.Use(func(c *gin.Context) {
+		c.Next()
+		if c.FullPath() != "" && c.Writer.Status() < http.StatusBadRequest {
+			s.answered[c.Request.Method+" "+c.FullPath()] = true
+		}
+	})
+
+	SetupRoutes(s.router, cfg, log,
+		handler.NewSatpenHandler(s.satpen),
+		handler.NewMasterHandler(s.master, log),
+		handler.NewHealthHandler(cfg),
+		handler.NewSyncHandler(s.sync, log),
+		handler.NewSuggestHandler(s.suggest),
+		handler.NewClusterHandler(s.cluster),
+		handler.NewDashboardHandler(s.dashboard),
+		handler.NewAuditHandler(s.audit),
+	)
+	return s
+}
+
+func (s *testServer) do(method, path, body, apiKey string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("X-Request-ID", testRequestID)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+type routeCase struct {
+	name   string // also the golden file name
+	method string
+	path   string
+	body   string
+	apiKey string
+	status int
+	// contentType is the expected media type, application/json when empty
+	contentType string
+	headers     map[string]string
+	// check asserts what the handler passed to the fakes
+	check func(t *testing.T, s *testServer)
+}
+
+const (
+	jsonType    = "application/json"
+	geoJSONType = "application/geo+json"
+	xlsxType    = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var routeCases = []routeCase{
+	// Health
+	{name: "health", method: "GET", path: "/health", status: 200},
+
+	// Satpen listing
+	{name: "satpen_list", method: "GET", path: "/api/v1/satpen", status: 200, check: func(t *testing.T, s *testServer) {
+		f := s.satpen
+		if f.sort != "" || f.page != 1 || f.limit != 20 || f.includeStats {
+			t.Errorf("sort %q, page %d, limit %d, include_stats %v; want the defaults", f.sort, f.page, f.limit, f.includeStats)
+		}
+		if f.filter.Verified != nil || f.filter.HasLocation != nil || f.filter.Status != nil {
+			t.Errorf("filter = %+v, want no verified, has_location or status", f.filter)
+		}
+	}},
+	{name: "satpen_list_filtered", method: "GET", path: "/api/v1/satpen?verified=false&jenjang=MI,%20MTs&status=aktif&sort=-nama&page=3&limit=5&include_stats=true", status: 200, check: func(t *testing.T, s *testServer) {
+		f := s.satpen
+		if f.filter.Verified == nil || *f.filter.Verified {
+			t.Errorf("Verified = %v, want false", f.filter.Verified)
+		}
+		if !reflect.DeepEqual(f.filter.Jenjang, []string{"MI", "MTs"}) || !reflect.DeepEqual(f.filter.Status, []string{"aktif"}) {
+			t.Errorf("Jenjang %q, Status %q", f.filter.Jenjang, f.filter.Status)
+		}
+		if f.sort != "-nama" || f.page != 3 || f.limit != 5 || !f.includeStats {
+			t.Errorf("sort %q, page %d, limit %d, include_stats %v", f.sort, f.page, f.limit, f.includeStats)
+		}
+	}},
+	{name: "satpen_list_fields", method: "GET", path: "/api/v1/satpen?fields=npsn,nama,kabupaten", status: 200},
+	{name: "satpen_list_invalid", method: "GET", path: "/api/v1/satpen?verified=maybe&status=bogus&sort=bogus&format=csv&tahun_berdiri_min=2000&tahun_berdiri_max=1990", status: 400},
+	{name: "satpen_list_invalid_fields", method: "GET", path: "/api/v1/satpen?fields=bogus&include=npsn", status: 400},
+	{name: "satpen_list_cursor", method: "GET", path: "/api/v1/satpen?pagination=cursor&limit=2&count=false", status: 200, check: func(t *testing.T, s *testServer) {
+		if f := s.satpen; f.cursor != "" || f.limit != 2 || f.withCount {
+			t.Errorf("cursor %q, limit %d, count %v", f.cursor, f.limit, f.withCount)
+		}
+	}},
+	{name: "satpen_list_cursor_invalid", method: "GET", path: "/api/v1/satpen?cursor=garbage", status: 400},
+	{name: "satpen_list_geojson", method: "GET", path: "/api/v1/satpen?format=geojson", status: 200, contentType: geoJSONType, check: func(t *testing.T, s *testServer) {
+		if hl := s.satpen.filter.HasLocation; hl == nil || !*hl {
+			t.Errorf("HasLocation = %v, want true for GeoJSON", hl)
+		}
+	}},
+	{name: "satpen_list_error", method: "GET", path: "/api/v1/satpen?search=fail", status: 500},
+
+	// Satpen detail
+	{name: "satpen_detail", method: "GET", path: "/api/v1/satpen/1", status: 200},
+	{name: "satpen_detail_fields", method: "GET", path: "/api/v1/satpen/20507001?fields=npsn,nama&include=jenjang", status: 200},
+	{name: "satpen_detail_not_found", method: "GET", path: "/api/v1/satpen/99", status: 404},
+	{name: "satpen_detail_error", method: "GET", path: "/api/v1/satpen/fail", status: 500},
+
+	// Statistics
+	{name: "statistics", method: "GET", path: "/api/v1/satpen/statistics?provinsi=35&top=3", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.satpen.topN != 3 || !reflect.DeepEqual(s.satpen.filter.Provinsi, []string{"35"}) {
+			t.Errorf("topN %d, Provinsi %q", s.satpen.topN, s.satpen.filter.Provinsi)
+		}
+	}},
+	{name: "statistics_invalid_top", method: "GET", path: "/api/v1/satpen/statistics?top=0", status: 400},
+	{name: "statistics_error", method: "GET", path: "/api/v1/satpen/statistics?search=fail", status: 500},
+	{name: "drilldown", method: "GET", path: "/api/v1/satpen/statistics/drilldown?provinsi_id=1", status: 200, check: func(t *testing.T, s *testServer) {
+		if p := s.satpen.path; p.ProvinsiID != 1 || p.KabupatenID != 0 || p.Kecamatan != "" {
+			t.Errorf("path = %+v", p)
+		}
+	}},
+	{name: "drilldown_invalid", method: "GET", path: "/api/v1/satpen/statistics/drilldown?kecamatan_key=singosari", status: 400},
+	{name: "trend", method: "GET", path: "/api/v1/satpen/statistics/trend?interval=quarter&from=2024-01-01&to=2024-06-30", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.satpen.interval != "quarter" {
+			t.Errorf("interval = %q", s.satpen.interval)
+		}
+	}},
+	{name: "trend_invalid", method: "GET", path: "/api/v1/satpen/statistics/trend?interval=week&from=2024-06-30&to=2024-01-01", status: 400},
+	{name: "indicators", method: "GET", path: "/api/v1/satpen/statistics/indicators?flagged=true", status: 200},
+	{name: "indicators_invalid", method: "GET", path: "/api/v1/satpen/statistics/indicators?indikator=bogus&group_by=kecamatan&order=up", status: 400},
+
+	// Export
+	{name: "export", method: "GET", path: "/api/v1/satpen/export?sort=nama", status: 200, contentType: xlsxType, headers: map[string]string{
+		"Content-Disposition": "attachment; filename=data-satpen-20240301-100000.xlsx",
+		"Cache-Control":       "no-cache",
+	}},
+	{name: "export_geojson", method: "GET", path: "/api/v1/satpen/export?format=geojson", status: 200, contentType: geoJSONType, headers: map[string]string{
+		"Content-Disposition": "attachment; filename=data-satpen-20240301-100000.geojson",
+	}},
+	{name: "export_invalid", method: "GET", path: "/api/v1/satpen/export?format=csv", status: 400},
+
+	// Suggest, nearby, clusters, regions
+	{name: "suggest", method: "GET", path: "/api/v1/satpen/suggest?q=singosar&limit=5", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.suggest.query != "singosar" || s.suggest.limit != 5 {
+			t.Errorf("query %q, limit %d", s.suggest.query, s.suggest.limit)
+		}
+	}},
+	{name: "suggest_missing_query", method: "GET", path: "/api/v1/satpen/suggest?q=%20", status: 400},
+	{name: "suggest_not_ready", method: "GET", path: "/api/v1/satpen/suggest?q=notready", status: 503},
+	{name: "nearby", method: "GET", path: "/api/v1/satpen/nearby?lat=-7.9&lng=112.6", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.satpen.limit != 20 {
+			t.Errorf("limit = %d, want 20", s.satpen.limit)
+		}
+	}},
+	{name: "nearby_geojson", method: "GET", path: "/api/v1/satpen/nearby?lat=-7.9&lng=112.6&radius_km=10&format=geojson", status: 200, contentType: geoJSONType},
+	{name: "nearby_invalid", method: "GET", path: "/api/v1/satpen/nearby?lat=-97&radius_km=x", status: 400},
+	{name: "nearby_radius", method: "GET", path: "/api/v1/satpen/nearby?lat=-7.9&lng=112.6&radius_km=500", status: 400},
+	{name: "clusters", method: "GET", path: "/api/v1/satpen/clusters?bbox=110,-8,113,-6&zoom=8&jenjang=MI,MTs", status: 200, check: func(t *testing.T, s *testServer) {
+		f := s.cluster
+		if f.bbox.MinLng != 110 || f.bbox.MinLat != -8 || f.bbox.MaxLng != 113 || f.bbox.MaxLat != -6 || f.zoom != 8 || len(f.jenjang) != 2 {
+			t.Errorf("bbox %+v, zoom %d, jenjang %q", f.bbox, f.zoom, f.jenjang)
+		}
+	}},
+	{name: "clusters_geojson", method: "GET", path: "/api/v1/satpen/clusters?bbox=110,-8,113,-6&zoom=8&format=geojson", status: 200, contentType: geoJSONType},
+	{name: "clusters_truncated", method: "GET", path: "/api/v1/satpen/clusters?bbox=110.8,-6.9,110.9,-6.7&zoom=16", status: 200},
+	{name: "clusters_invalid", method: "GET", path: "/api/v1/satpen/clusters?bbox=113,-8,110,-6&zoom=30", status: 400},
+	{name: "regions", method: "GET", path: "/api/v1/satpen/geojson/regions?level=kabupaten", status: 200, contentType: geoJSONType},
+	{name: "regions_invalid", method: "GET", path: "/api/v1/satpen/geojson/regions?level=desa", status: 400},
+
+	// Master data
+	{name: "provinsi_list", method: "GET", path: "/api/v1/provinsi?search=jawa", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.master.search != "jawa" {
+			t.Errorf("search = %q", s.master.search)
+		}
+	}},
+	{name: "provinsi_list_error", method: "GET", path: "/api/v1/provinsi?search=fail", status: 500},
+	{name: "provinsi_detail", method: "GET", path: "/api/v1/provinsi/1", status: 200},
+	{name: "provinsi_not_found", method: "GET", path: "/api/v1/provinsi/99", status: 404},
+	{name: "provinsi_invalid_id", method: "GET", path: "/api/v1/provinsi/abc", status: 400},
+	{name: "kabupaten_list", method: "GET", path: "/api/v1/kabupaten?provinsi_id=1&search=malang", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.master.provinsiID != 1 || s.master.search != "malang" {
+			t.Errorf("provinsi_id %d, search %q", s.master.provinsiID, s.master.search)
+		}
+	}},
+	{name: "kabupaten_list_invalid", method: "GET", path: "/api/v1/kabupaten?provinsi_id=x", status: 400},
+	{name: "kabupaten_detail", method: "GET", path: "/api/v1/kabupaten/1", status: 200},
+	{name: "kabupaten_not_found", method: "GET", path: "/api/v1/kabupaten/99", status: 404},
+	{name: "pengurus_cabang_list", method: "GET", path: "/api/v1/pengurus-cabang?provinsi_id=1&page=2&limit=10", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.master.page != 2 || s.master.limit != 10 || s.master.filters["provinsi_id"] != uint(1) {
+			t.Errorf("page %d, limit %d, filters %v", s.master.page, s.master.limit, s.master.filters)
+		}
+	}},
+	{name: "pengurus_cabang_list_limit", method: "GET", path: "/api/v1/pengurus-cabang?page=0&limit=500", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.master.page != 1 || s.master.limit != 20 {
+			t.Errorf("page %d, limit %d; want 1 and 20", s.master.page, s.master.limit)
+		}
+	}},
+	{name: "pengurus_cabang_detail", method: "GET", path: "/api/v1/pengurus-cabang/1", status: 200},
+	{name: "pengurus_cabang_not_found", method: "GET", path: "/api/v1/pengurus-cabang/99", status: 404},
+	{name: "pengurus_cabang_dashboard", method: "GET", path: "/api/v1/pengurus-cabang/1/dashboard", status: 200},
+	{name: "pengurus_cabang_dashboard_not_found", method: "GET", path: "/api/v1/pengurus-cabang/99/dashboard", status: 404},
+	{name: "pengurus_cabang_dashboard_invalid_id", method: "GET", path: "/api/v1/pengurus-cabang/abc/dashboard", status: 400},
+	{name: "pengurus_wilayah_list", method: "GET", path: "/api/v1/pengurus-wilayah", status: 200},
+	{name: "pengurus_wilayah_detail", method: "GET", path: "/api/v1/pengurus-wilayah/1", status: 200},
+	{name: "pengurus_wilayah_not_found", method: "GET", path: "/api/v1/pengurus-wilayah/99", status: 404},
+	{name: "jenjang_list", method: "GET", path: "/api/v1/jenjang-pendidikan?search=MI", status: 200},
+	{name: "jenjang_detail", method: "GET", path: "/api/v1/jenjang-pendidikan/1", status: 200},
+	{name: "jenjang_not_found", method: "GET", path: "/api/v1/jenjang-pendidikan/99", status: 404},
+	{name: "kategori_list", method: "GET", path: "/api/v1/kategori-satpen", status: 200},
+	{name: "kategori_detail", method: "GET", path: "/api/v1/kategori-satpen/1", status: 200},
+	{name: "kategori_not_found", method: "GET", path: "/api/v1/kategori-satpen/99", status: 404},
+
+	// Data quality
+	{name: "data_quality_rules", method: "GET", path: "/api/v1/data-quality/rules", status: 200},
+	{name: "data_quality_issues", method: "GET", path: "/api/v1/data-quality/issues?rule=npsn_invalid", status: 200, check: func(t *testing.T, s *testServer) {
+		if s.satpen.page != 1 || s.satpen.limit != 20 {
+			t.Errorf("page %d, limit %d", s.satpen.page, s.satpen.limit)
+		}
+	}},
+	{name: "data_quality_issues_invalid", method: "GET", path: "/api/v1/data-quality/issues?rule=npsn_invalid,bogus", status: 400},
+
+	// Admin
+	{name: "admin_unauthorized", method: "POST", path: "/api/v1/admin/satpen/1/sync", status: 401},
+	{name: "admin_wrong_key", method: "POST", path: "/api/v1/admin/satpen/1/sync", apiKey: "wrong", status: 401},
+	{name: "sync", method: "POST", path: "/api/v1/admin/satpen/1/sync", apiKey: testAdminKey, status: 200, check: func(t *testing.T, s *testServer) {
+		if actor := auditActor(s.sync.ctx); actor != "test" {
+			t.Errorf("audit actor = %q, want test", actor)
+		}
+	}},
+	{name: "sync_not_found", method: "POST", path: "/api/v1/admin/satpen/99/sync", apiKey: testAdminKey, status: 404},
+	{name: "sync_no_npsn", method: "POST", path: "/api/v1/admin/satpen/2/sync", apiKey: testAdminKey, status: 422},
+	{name: "sync_failed", method: "POST", path: "/api/v1/admin/satpen/3/sync", apiKey: testAdminKey, status: 502},
+	{name: "location", method: "PUT", path: "/api/v1/admin/satpen/1/location", body: `{"latitude":-7.9,"longitude":112.6}`, apiKey: testAdminKey, status: 200, check: func(t *testing.T, s *testServer) {
+		if actor := auditActor(s.satpen.ctx); actor != "test" {
+			t.Errorf("audit actor = %q, want test", actor)
+		}
+	}},
+	{name: "location_clear", method: "PUT", path: "/api/v1/admin/satpen/1/location", body: `{"latitude":null,"longitude":null}`, apiKey: testAdminKey, status: 200},
+	{name: "location_partial", method: "PUT", path: "/api/v1/admin/satpen/1/location", body: `{"latitude":-7.9}`, apiKey: testAdminKey, status: 400},
+	{name: "location_invalid_body", method: "PUT", path: "/api/v1/admin/satpen/1/location", body: `{"latitude":`, apiKey: testAdminKey, status: 400},
+	{name: "location_not_found", method: "PUT", path: "/api/v1/admin/satpen/99/location", body: `{"latitude":-7.9,"longitude":112.6}`, apiKey: testAdminKey, status: 404},
+	{name: "duplicates", method: "GET", path: "/api/v1/admin/satpen/duplicates?kabupaten=Malang&limit=10", apiKey: testAdminKey, status: 200, check: func(t *testing.T, s *testServer) {
+		if s.satpen.limit != 10 || !reflect.DeepEqual(s.satpen.filter.Kabupaten, []string{"Malang"}) {
+			t.Errorf("limit %d, Kabupaten %q", s.satpen.limit, s.satpen.filter.Kabupaten)
+		}
+	}},
+	{name: "duplicates_invalid", method: "GET", path: "/api/v1/admin/satpen/duplicates?min_score=2", apiKey: testAdminKey, status: 400},
+	{name: "merge", method: "POST", path: "/api/v1/admin/satpen/merge", body: `{"survivor_id":1,"loser_id":2}`, apiKey: testAdminKey, status: 200},
+	{name: "merge_preview", method: "POST", path: "/api/v1/admin/satpen/merge", body: `{"survivor_id":1,"loser_id":2,"preview":true}`, apiKey: testAdminKey, status: 200},
+	{name: "merge_same", method: "POST", path: "/api/v1/admin/satpen/merge", body: `{"survivor_id":1,"loser_id":1}`, apiKey: testAdminKey, status: 400},
+	{name: "merge_missing_id", method: "POST", path: "/api/v1/admin/satpen/merge", body: `{"survivor_id":1}`, apiKey: testAdminKey, status: 400},
+	{name: "merge_not_found", method: "POST", path: "/api/v1/admin/satpen/merge", body: `{"survivor_id":1,"loser_id":99}`, apiKey: testAdminKey, status: 404},
+
+	// Audit log
+	{name: "audit", method: "GET", path: "/api/v1/audit?entity_type=satpen&entity_id=1&from=2024-01-01&to=2024-03-31", apiKey: testAdminKey, status: 200, check: func(t *testing.T, s *testServer) {
+		f := s.audit
+		if f.filter.EntityType != "satpen" || f.filter.EntityID != 1 || f.filter.From == nil || f.filter.To == nil || f.page != 1 || f.limit != 20 {
+			t.Errorf("filter %+v, page %d, limit %d", f.filter, f.page, f.limit)
+		}
+	}},
+	{name: "audit_invalid", method: "GET", path: "/api/v1/audit?entity_id=x&from=2024-03-31&to=2024-01-01", apiKey: testAdminKey, status: 400},
+	{name: "audit_unauthorized", method: "GET", path: "/api/v1/audit", status: 401},
+}
+
+func TestRoutes(t *testing.T) {
+	s := newTestServer(t, testConfig())
+
+	for _, tc := range routeCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := s.do(tc.method, tc.path, tc.body, tc.apiKey)
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tc.status, rec.Body)
+			}
+			if got := rec.Header().Get("X-Request-ID"); got != testRequestID {
+				t.Errorf("X-Request-ID = %q, want %q", got, testRequestID)
+			}
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = jsonType
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, contentType) {
+				t.Errorf("Content-Type = %q, want %s", got, contentType)
+			}
+			for key, want := range tc.headers {
+				if got := rec.Header().Get(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+
+			switch {
+			case contentType == geoJSONType:
+				checkFeatureCollection(t, rec.Body.Bytes())
+			case contentType == jsonType && tc.path != "/health":
+				checkEnvelope(t, rec.Code, rec.Body.Bytes())
+			}
+			checkGolden(t, tc.name, rec.Body.Bytes())
+
+			if tc.check != nil {
+				tc.check(t, s)
+			}
+		})
+	}
+
+	// Every route needs at least one case it answers successfully
+	var missing []string
+	for _, route := range s.router.Routes() {
+		if key := route.Method + " " + route.Path; !s.answered[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("routes without a successful case: %s", strings.Join(missing, ", "))
+	}
+}
+
+func TestAdminDisabled(t *testing.T) {
+	cfg := testConfig()
+	cfg.Admin.Enabled = false
+	s := newTestServer(t, cfg)
+
+	for _, route := range s.router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/admin/") && route.Path != "/api/v1/audit" {
+			continue
+		}
+		path := strings.ReplaceAll(route.Path, ":id", "1")
+		rec := s.do(route.Method, path, `{}`, testAdminKey)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: status = %d, want 403", route.Method, path, rec.Code)
+			continue
+		}
+		checkEnvelope(t, rec.Code, rec.Body.Bytes())
+	}
+}
+
+func TestHealthDisabled(t *testing.T) {
+	cfg := testConfig()
+	cfg.Monitoring.Enabled = false
+	s := newTestServer(t, cfg)
+
+	if rec := s.do("GET", "/health", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	s := newTestServer(t, testConfig())
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	for _, sent := range []string{"", strings.Repeat("x", 65)} {
+		req := httptest.NewRequest("GET", "/api/v1/provinsi", nil)
+		if sent != "" {
+			req.Header.Set("X-Request-ID", sent)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		if got := rec.Header().Get("X-Request-ID"); !generated.MatchString(got) {
+			t.Errorf("sent %q: X-Request-ID = %q, want a generated ID", sent, got)
+		}
+	}
+}
+
+// checkEnvelope asserts body is a utils.Response matching the status code
+func checkEnvelope(t *testing.T, status int, body []byte) {
+	t.Helper()
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("body is not a JSON object: %v", err)
+	}
+	for key := range envelope {
+		switch key {
+		case "success", "message", "data", "error", "errors":
+		default:
+			t.Errorf("unexpected envelope member %q", key)
+		}
+	}
+
+	var success bool
+	if err := json.Unmarshal(envelope["success"], &success); err != nil {
+		t.Errorf("success: %v", err)
+	}
+	var message string
+	if err := json.Unmarshal(envelope["message"], &message); err != nil || message == "" {
+		t.Errorf("message = %s, want a non-empty string", envelope["message"])
+	}
+
+	_, hasData := envelope["data"]
+	_, hasError := envelope["error"]
+	_, hasErrors := envelope["errors"]
+	if status < http.StatusBadRequest {
+		if !success || !hasData || hasError || hasErrors {
+			t.Errorf("status %d: success %v, data %v, error %v, errors %v; want success with data only", status, success, hasData, hasError, hasErrors)
+		}
+		return
+	}
+	if success {
+		t.Errorf("status %d: success true, want false", status)
+	}
+	// utils.NotFoundResponse carries the message only
+	if status != http.StatusNotFound && !hasError && !hasErrors {
+		t.Errorf("status %d: no error or errors member", status)
+	}
+}
+
+// checkFeatureCollection asserts body is a GeoJSON FeatureCollection
+func checkFeatureCollection(t *testing.T, body []byte) {
+	t.Helper()
+	var fc struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.Unmarshal(body, &fc); err != nil {
+		t.Fatalf("body is not GeoJSON: %v", err)
+	}
+	if fc.Type != "FeatureCollection" || fc.Features == nil {
+		t.Errorf("type %q with features %v, want a FeatureCollection", fc.Type, fc.Features)
+	}
+}
+
+// checkGolden compares body, indented when it is JSON, with
+// testdata/<name>.golden. go test -update rewrites the file.
+func checkGolden(t *testing.T, name string, body []byte) {
+	t.Helper()
+	got := body
+	var indented bytes.Buffer
+	if json.Indent(&indented, body, "", "  ") == nil {
+		indented.WriteByte('\n')
+		got = indented.Bytes()
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./internal/routes -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("body differs from %s (go test ./internal/routes -update rewrites it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
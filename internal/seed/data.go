@@ -0,0 +1,129 @@
+package seed
+
+// provinsiData lists the provinsi with their BPS code, an approximate centre
+// and spread in degrees for placing satpen, and a weight: the share of
+// Ma'arif schools, heavily on Java
+var provinsiData = []struct {
+	Kode, Nama, Map string
+	Lat, Lng        float64
+	Spread          float64
+	Weight          int
+	Kabupaten       int
+}{
+	{"11", "Aceh", "id-ac", 4.37, 96.75, 1.2, 2, 23},
+	{"12", "Sumatera Utara", "id-su", 2.19, 99.07, 1.3, 3, 33},
+	{"13", "Sumatera Barat", "id-sb", -0.74, 100.80, 0.9, 1, 19},
+	{"14", "Riau", "id-ri", 0.29, 101.71, 1.1, 2, 12},
+	{"15", "Jambi", "id-ja", -1.61, 103.07, 0.9, 2, 11},
+	{"16", "Sumatera Selatan", "id-sl", -3.32, 104.16, 1.1, 3, 17},
+	{"17", "Bengkulu", "id-be", -3.79, 102.27, 0.7, 1, 10},
+	{"18", "Lampung", "id-1024", -4.56, 105.41, 0.8, 5, 15},
+	{"19", "Kepulauan Bangka Belitung", "id-bb", -2.74, 106.44, 0.6, 1, 7},
+	{"21", "Kepulauan Riau", "id-kr", 3.95, 108.14, 0.8, 1, 7},
+	{"31", "DKI Jakarta", "id-jk", -6.21, 106.85, 0.1, 2, 6},
+	{"32", "Jawa Barat", "id-jr", -6.89, 107.64, 0.6, 12, 27},
+	{"33", "Jawa Tengah", "id-jt", -7.15, 110.14, 0.6, 22, 35},
+	{"34", "DI Yogyakarta", "id-yo", -7.80, 110.36, 0.2, 3, 5},
+	{"35", "Jawa Timur", "id-ji", -7.54, 112.24, 0.7, 30, 38},
+	{"36", "Banten", "id-bt", -6.41, 106.06, 0.3, 4, 8},
+	{"51", "Bali", "id-ba", -8.41, 115.19, 0.3, 1, 9},
+	{"52", "Nusa Tenggara Barat", "id-nb", -8.65, 117.36, 0.6, 3, 10},
+	{"53", "Nusa Tenggara Timur", "id-nt", -8.66, 121.08, 1.0, 1, 22},
+	{"61", "Kalimantan Barat", "id-kb", -0.28, 111.48, 1.2, 1, 14},
+	{"62", "Kalimantan Tengah", "id-kt", -1.68, 113.38, 1.2, 1, 14},
+	{"63", "Kalimantan Selatan", "id-ks", -3.09, 115.28, 0.7, 2, 13},
+	{"64", "Kalimantan Timur", "id-ki", 0.54, 116.42, 1.2, 1, 10},
+	{"65", "Kalimantan Utara", "id-ku", 3.07, 116.04, 0.8, 1, 5},
+	{"71", "Sulawesi Utara", "id-sw", 0.62, 123.98, 0.7, 1, 15},
+	{"72", "Sulawesi Tengah", "id-st", -1.43, 121.45, 1.0, 1, 13},
+	{"73", "Sulawesi Selatan", "id-se", -3.67, 119.97, 0.9, 2, 24},
+	{"74", "Sulawesi Tenggara", "id-sg", -4.14, 122.17, 0.8, 1, 17},
+	{"75", "Gorontalo", "id-go", 0.70, 122.45, 0.4, 1, 6},
+	{"76", "Sulawesi Barat", "id-sr", -2.84, 119.23, 0.5, 1, 6},
+	{"81", "Maluku", "id-ma", -3.24, 130.15, 1.2, 1, 11},
+	{"82", "Maluku Utara", "id-la", 1.57, 127.81, 0.9, 1, 10},
+	{"91", "Papua Barat", "id-ib", -1.34, 133.17, 1.0, 1, 13},
+	{"94", "Papua", "id-pa", -4.27, 138.08, 1.5, 1, 29},
+}
+
+// jenjangData are the jenjang pendidikan and the prefix of satpen names,
+// weighted like the real distribution (MI and MTs most common)
+var jenjangData = []struct {
+	Nama, Keterangan, Lembaga string
+	Weight                    int
+	SiswaMin, SiswaMax        int
+}{
+	{"RA", "Raudhatul Athfal", "MADRASAH", 8, 20, 90},
+	{"MI", "Madrasah Ibtidaiyah", "MADRASAH", 26, 60, 500},
+	{"MTs", "Madrasah Tsanawiyah", "MADRASAH", 20, 80, 700},
+	{"MA", "Madrasah Aliyah", "MADRASAH", 10, 60, 600},
+	{"TK", "Taman Kanak-Kanak", "SEKOLAH", 5, 20, 80},
+	{"SD", "Sekolah Dasar", "SEKOLAH", 8, 60, 450},
+	{"SMP", "Sekolah Menengah Pertama", "SEKOLAH", 10, 80, 650},
+	{"SMA", "Sekolah Menengah Atas", "SEKOLAH", 5, 60, 600},
+	{"SMK", "Sekolah Menengah Kejuruan", "SEKOLAH", 8, 80, 900},
+}
+
+// kategoriData are the akreditasi categories
+var kategoriData = []struct {
+	Nama, Konotasi, Keterangan string
+	Weight                     int
+}{
+	{"A", "Unggul", "Terakreditasi A", 20},
+	{"B", "Baik", "Terakreditasi B", 45},
+	{"C", "Cukup", "Terakreditasi C", 20},
+	{"D", "Kurang", "Belum memenuhi standar", 5},
+}
+
+// statusWeights is the distribution of satpen.status
+var statusWeights = []struct {
+	Status string
+	Weight int
+}{
+	{"setujui", 70},
+	{"perpanjangan", 6},
+	{"expired", 8},
+	{"permohonan", 8},
+	{"revisi", 4},
+	{"proses dokumen", 4},
+}
+
+// Syllables of synthetic place names: "Suka" + "mulya" -> "Sukamulya"
+var (
+	placePrefixes = []string{
+		"Suka", "Karang", "Tanjung", "Sumber", "Banyu", "Kali", "Pasir", "Muara",
+		"Sido", "Mekar", "Cipta", "Purwo", "Wono", "Tegal", "Gunung", "Sri",
+		"Jati", "Mulyo", "Rejo", "Sari", "Taman", "Bojong", "Ngadi", "Kedung",
+		"Pa", "Batu", "Lubuk", "Teluk", "Air", "Padang",
+	}
+	placeSuffixes = []string{
+		"jaya", "mulya", "sari", "rejo", "makmur", "agung", "harjo", "wangi",
+		"asri", "baru", "manik", "sono", "kerto", "rahayu", "mukti", "tengah",
+		"kulon", "wetan", "luhur", "anyar",
+	}
+	streetNames = []string{
+		"Raya", "Masjid", "Pesantren", "KH. Hasyim Asy'ari", "KH. Wahid Hasyim",
+		"Diponegoro", "Sudirman", "Pahlawan", "Merdeka", "Pendidikan",
+		"Kenanga", "Melati", "Mawar", "Flamboyan", "Veteran",
+	}
+	firstNames = []string{
+		"Muhammad", "Ahmad", "Abdul", "Siti", "Nur", "Sri", "Agus", "Budi",
+		"Dwi", "Eko", "Fitri", "Hasan", "Imam", "Khoirul", "Lailatul", "Moh.",
+		"Nanik", "Rina", "Slamet", "Umi", "Wahyu", "Yusuf", "Zainal", "Anis",
+	}
+	lastNames = []string{
+		"Hidayat", "Rahman", "Fauzi", "Hasanah", "Mubarok", "Santoso", "Maulana",
+		"Aziz", "Khasanah", "Sholeh", "Hakim", "Wahyuni", "Ridwan", "Setiawan",
+		"Zuhri", "Mustofa", "Rohmah", "Syafi'i", "Ulum", "Arifin",
+	}
+	yayasanNames = []string{
+		"Yayasan Pendidikan Ma'arif", "Yayasan Al-Hikmah", "Yayasan Nurul Huda",
+		"Yayasan Darul Ulum", "Yayasan Miftahul Huda", "Yayasan Bustanul Ulum",
+		"Yayasan Roudlotul Muta'allimin", "Yayasan Hidayatul Mubtadiin",
+		"Yayasan Sabilul Muttaqin", "Yayasan Tarbiyatul Aulad",
+	}
+	satpenNames = []string{
+		"Ma'arif NU", "NU", "Ma'arif", "Nurul Huda", "Al-Hikmah", "Darul Ulum",
+		"Miftahul Huda", "Sabilul Huda", "Islamiyah", "Hidayatul Mubtadiin",
+	}
+)
@@ -0,0 +1,631 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"satpen-api/internal/models"
+)
+
+// passwordPlaceholder is stored as the password of seeded users. It is not a
+// bcrypt hash, so nobody can log in to the main application with them.
+const passwordPlaceholder = "!seed"
+
+// dataset holds the generated rows of every table
+type dataset struct {
+	provinsi  []models.Provinsi
+	profilePW []models.ProfilePengurusWilayah
+	kabupaten []models.Kabupaten
+	pc        []models.PengurusCabang
+	profilePC []models.ProfilePengurusCabang
+	jenjang   []models.JenjangPendidikan
+	kategori  []models.KategoriSatpen
+	tapel     []models.TahunPelajaran
+	users     []userRow
+	satpen    []models.Satpen
+	timeline  []timelineRow
+	pdptk     []pdptkRow
+	ptk       []ptkRow
+}
+
+type table struct {
+	name  string
+	value interface{}
+	rows  int
+}
+
+// tables returns the rows in Tables order
+func (d *dataset) tables() []table {
+	return []table{
+		{"provinsi", &d.provinsi, len(d.provinsi)},
+		{"profile_pengurus_wilayah", &d.profilePW, len(d.profilePW)},
+		{"kabupaten", &d.kabupaten, len(d.kabupaten)},
+		{"pengurus_cabang", &d.pc, len(d.pc)},
+		{"profile_pengurus_cabang", &d.profilePC, len(d.profilePC)},
+		{"jenjang_pendidikan", &d.jenjang, len(d.jenjang)},
+		{"kategori_satpen", &d.kategori, len(d.kategori)},
+		{"tahun_pelajaran", &d.tapel, len(d.tapel)},
+		{"users", &d.users, len(d.users)},
+		{"satpen", &d.satpen, len(d.satpen)},
+		{"timeline_reg", &d.timeline, len(d.timeline)},
+		{"pdptk", &d.pdptk, len(d.pdptk)},
+		{"ptk", &d.ptk, len(d.ptk)},
+	}
+}
+
+// kabInfo is a generated kabupaten with what satpen in it need
+type kabInfo struct {
+	id, idProv uint
+	kode, nama string
+	lat, lng   float64
+	kecamatan  []kecInfo
+}
+
+type kecInfo struct {
+	nama      string
+	kelurahan []string
+}
+
+// semester is one tahun pelajaran semester, e.g. 20241 = 2024/2025 ganjil
+type semester struct {
+	tapel string
+	start time.Time
+}
+
+type generator struct {
+	rng     *rand.Rand
+	opts    Options
+	created time.Time // created_at of the reference rows
+	data    dataset
+
+	kabs      []kabInfo
+	kabByProv [][]int // kabs indexes per provinsi index
+	semesters []semester
+	usedNames map[string]bool
+	npsnSeq   []int // per provinsi index
+}
+
+func generate(opts Options) *dataset {
+	if opts.ValidYears < 1 {
+		opts.ValidYears = 4
+	}
+	g := &generator{
+		rng:       rand.New(rand.NewSource(opts.Seed)),
+		opts:      opts,
+		created:   time.Date(2015, 1, 1, 8, 0, 0, 0, time.Local),
+		usedNames: make(map[string]bool),
+		npsnSeq:   make([]int, len(provinsiData)),
+	}
+	g.genProvinsi()
+	g.genKabupaten()
+	g.genReferences()
+	g.genSemesters()
+	for i := 0; i < opts.Satpen; i++ {
+		if i > 0 && g.rng.Float64() < opts.DuplicateRate {
+			g.genDuplicate(&g.data.satpen[g.rng.Intn(len(g.data.satpen))])
+		} else {
+			g.genSatpen()
+		}
+	}
+	return &g.data
+}
+
+func (g *generator) genProvinsi() {
+	g.kabByProv = make([][]int, len(provinsiData))
+	for i, p := range provinsiData {
+		id := uint(i + 1)
+		g.data.provinsi = append(g.data.provinsi, models.Provinsi{
+			IDProv: id, Map: p.Map, KodeProv: p.Kode, NmProv: p.Nama,
+			CreatedAt: g.created, UpdatedAt: g.created,
+		})
+		g.data.profilePW = append(g.data.profilePW, models.ProfilePengurusWilayah{
+			ID:             i + 1,
+			IDPW:           id,
+			ProfilPengurus: g.profil(p.Nama, p.Lat, p.Lng),
+			CreatedAt:      &g.created,
+			UpdatedAt:      &g.created,
+		})
+	}
+}
+
+func (g *generator) genKabupaten() {
+	for i, p := range provinsiData {
+		kota := p.Kabupaten / 5
+		if kota < 1 {
+			kota = 1
+		}
+		for j := 0; j < p.Kabupaten; j++ {
+			name := g.uniquePlaceName()
+			kode := fmt.Sprintf("%s%02d", p.Kode, j+1-kota)
+			nama := "Kab. " + name
+			if j < kota {
+				kode = fmt.Sprintf("%s%02d", p.Kode, 71+j)
+				nama = "Kota " + name
+			}
+			kab := kabInfo{
+				id:     uint(len(g.kabs) + 1),
+				idProv: uint(i + 1),
+				kode:   kode,
+				nama:   nama,
+				lat:    p.Lat + (g.rng.Float64()*2-1)*p.Spread,
+				lng:    p.Lng + (g.rng.Float64()*2-1)*p.Spread,
+			}
+			for k, n := 0, 4+g.rng.Intn(12); k < n; k++ {
+				kec := kecInfo{nama: g.placeName()}
+				for l, m := 0, 3+g.rng.Intn(8); l < m; l++ {
+					kec.kelurahan = append(kec.kelurahan, g.placeName())
+				}
+				kab.kecamatan = append(kab.kecamatan, kec)
+			}
+			g.kabByProv[i] = append(g.kabByProv[i], len(g.kabs))
+			g.kabs = append(g.kabs, kab)
+
+			g.data.kabupaten = append(g.data.kabupaten, models.Kabupaten{
+				IDKab: kab.id, IDProv: kab.idProv, NamaKab: kab.nama,
+				CreatedAt: g.created, UpdatedAt: g.created,
+			})
+			// One pengurus cabang per kabupaten, sharing its ID
+			g.data.pc = append(g.data.pc, models.PengurusCabang{
+				IDPC: kab.id, IDProv: kab.idProv, KodeKab: kab.kode,
+				NamaPC:    "PC LP Ma'arif NU " + kab.nama,
+				CreatedAt: g.created, UpdatedAt: g.created,
+			})
+			profil := g.profil(kab.nama, kab.lat, kab.lng)
+			profil.Kecamatan = kab.kecamatan[0].nama
+			profil.Kelurahan = kab.kecamatan[0].kelurahan[0]
+			g.data.profilePC = append(g.data.profilePC, models.ProfilePengurusCabang{
+				ID:             int(kab.id),
+				IDPC:           kab.id,
+				ProfilPengurus: profil,
+				CreatedAt:      &g.created,
+				UpdatedAt:      &g.created,
+			})
+		}
+	}
+}
+
+// profil returns the profile of a pengurus in region, located at lat, lng
+func (g *generator) profil(region string, lat, lng float64) models.ProfilPengurus {
+	return models.ProfilPengurus{
+		Alamat:         g.alamat(),
+		Kabupaten:      region,
+		Lintang:        strconv.FormatFloat(lat, 'f', 6, 64),
+		Bujur:          strconv.FormatFloat(lng, 'f', 6, 64),
+		Ketua:          g.personName(),
+		TelpKetua:      g.phone(),
+		WakilKetua:     g.personName(),
+		TelpWakil:      g.phone(),
+		Bendahara:      g.personName(),
+		TelpBendahara:  g.phone(),
+		Sekretaris:     g.personName(),
+		TelpSekretaris: g.phone(),
+		MasaKhidmat:    "2023-2028",
+	}
+}
+
+func (g *generator) genReferences() {
+	for i, j := range jenjangData {
+		g.data.jenjang = append(g.data.jenjang, models.JenjangPendidikan{
+			IDJenjang: uint(i + 1), NmJenjang: j.Nama, Keterangan: j.Keterangan, Lembaga: j.Lembaga,
+			CreatedAt: g.created, UpdatedAt: g.created,
+		})
+	}
+	for i, k := range kategoriData {
+		g.data.kategori = append(g.data.kategori, models.KategoriSatpen{
+			IDKategori: uint(i + 1), NmKategori: k.Nama, Konotasi: k.Konotasi, Keterangan: k.Keterangan,
+			CreatedAt: g.created, UpdatedAt: g.created,
+		})
+	}
+}
+
+// genSemesters creates opts.Semesters tahun pelajaran up to the one running
+// at opts.Until: ganjil from July, genap from January
+func (g *generator) genSemesters() {
+	year, sem := g.opts.Until.Year(), 1
+	if g.opts.Until.Month() < time.July {
+		year, sem = year-1, 2
+	}
+	for i := 0; i < g.opts.Semesters; i++ {
+		start := time.Date(year, time.July, 15, 0, 0, 0, 0, time.Local)
+		if sem == 2 {
+			start = time.Date(year+1, time.January, 8, 0, 0, 0, 0, time.Local)
+		}
+		g.semesters = append([]semester{{tapel: fmt.Sprintf("%d%d", year, sem), start: start}}, g.semesters...)
+		if sem == 2 {
+			sem = 1
+		} else {
+			year, sem = year-1, 2
+		}
+	}
+	for i, s := range g.semesters {
+		nama := fmt.Sprintf("%s/%d Ganjil", s.tapel[:4], atoi(s.tapel[:4])+1)
+		if s.tapel[4] == '2' {
+			nama = fmt.Sprintf("%s/%d Genap", s.tapel[:4], atoi(s.tapel[:4])+1)
+		}
+		g.data.tapel = append(g.data.tapel, models.TahunPelajaran{ID: i + 1, TapelDapo: s.tapel, NamaTapel: nama})
+	}
+}
+
+func (g *generator) genSatpen() {
+	provIdx := g.pickWeighted(len(provinsiData), func(i int) int { return provinsiData[i].Weight })
+	kab := &g.kabs[g.kabByProv[provIdx][g.rng.Intn(len(g.kabByProv[provIdx]))]]
+	jenjangIdx := g.pickWeighted(len(jenjangData), func(i int) int { return jenjangData[i].Weight })
+	jenjang := jenjangData[jenjangIdx]
+	kec := kab.kecamatan[g.rng.Intn(len(kab.kecamatan))]
+	kelurahan := kec.kelurahan[g.rng.Intn(len(kec.kelurahan))]
+	status := statusWeights[g.pickWeighted(len(statusWeights), func(i int) int { return statusWeights[i].Weight })].Status
+
+	name := jenjang.Nama + " " + satpenNames[g.rng.Intn(len(satpenNames))]
+	if g.rng.Intn(2) == 0 {
+		name += fmt.Sprintf(" %02d", 1+g.rng.Intn(12))
+	}
+	name += " " + kelurahan
+
+	s := models.Satpen{
+		IDProv:     kab.idProv,
+		IDKab:      kab.id,
+		IDPC:       kab.id,
+		IDJenjang:  uint(jenjangIdx + 1),
+		NPSN:       g.npsn(provIdx),
+		NmSatpen:   name,
+		Yayasan:    yayasanNames[g.rng.Intn(len(yayasanNames))],
+		Kepsek:     g.personName(),
+		Telpon:     g.phone(),
+		Email:      "",
+		ThnBerdiri: 1950 + g.rng.Intn(g.opts.Until.Year()-1950+1),
+		Kecamatan:  g.variant(kec.nama, "Kec. "),
+		Kelurahan:  g.variant(kelurahan, "Desa "),
+		Alamat:     g.alamat(),
+		AsetTanah:  []string{"Milik Sendiri", "Wakaf", "Sewa"}[g.rng.Intn(3)],
+		Status:     status,
+	}
+	if g.rng.Float64() < 0.85 {
+		id := uint(g.pickWeighted(len(kategoriData), func(i int) int { return kategoriData[i].Weight }) + 1)
+		s.IDKategori = &id
+	}
+	if g.rng.Float64() < 0.8 {
+		s.Email = strings.ToLower(jenjang.Nama) + "." + s.NPSN + "@example.sch.id"
+	}
+	s.NmPemilik = s.Yayasan
+	g.registrationDates(&s)
+	if g.rng.Float64() < 0.7 {
+		lat := kab.lat + g.rng.NormFloat64()*0.12
+		lng := kab.lng + g.rng.NormFloat64()*0.12
+		s.Lintang, s.Bujur = &lat, &lng
+	}
+	if g.rng.Float64() < g.opts.DirtyRate {
+		g.dirty(&s)
+	}
+
+	g.addSatpen(s, kab)
+	if status == "setujui" || status == "perpanjangan" || status == "expired" {
+		g.genPDPTK(&g.data.satpen[len(g.data.satpen)-1], jenjang.SiswaMin, jenjang.SiswaMax)
+	}
+}
+
+// genDuplicate registers base a second time under a new NPSN and
+// no_registrasi, with the spelling drift seen in real double registrations
+func (g *generator) genDuplicate(base *models.Satpen) {
+	s := *base
+	s.IDSatpen, s.PDPTK = 0, nil
+	s.Lintang, s.Bujur = nil, nil
+
+	name := s.NmSatpen
+	switch g.rng.Intn(3) {
+	case 0:
+		jenjang := jenjangData[s.IDJenjang-1]
+		name = strings.Replace(name, jenjang.Nama+" ", jenjang.Keterangan+" ", 1)
+	case 1:
+		name = strings.Replace(name, "Ma'arif", "Maarif", 1)
+	default:
+		name = strings.Replace(name, " 0", " ", 1)
+	}
+	s.NmSatpen = name
+	s.Alamat = strings.Replace(s.Alamat, "Jl. ", "Jalan ", 1)
+	s.Kelurahan = strings.ToUpper(s.Kelurahan)
+	s.NPSN = g.npsn(int(s.IDProv - 1))
+	if s.Email != "" {
+		s.Email = strings.ToLower(jenjangData[s.IDJenjang-1].Nama) + "." + s.NPSN + "@example.sch.id"
+	}
+	s.Status = "permohonan"
+	g.registrationDates(&s)
+
+	g.addSatpen(s, &g.kabs[s.IDKab-1])
+}
+
+// addSatpen assigns the IDs and unique numbers of s, adds it with its user
+// and registration timeline
+func (g *generator) addSatpen(s models.Satpen, kab *kabInfo) {
+	id := uint(len(g.data.satpen) + 1)
+	s.IDSatpen = id
+	s.IDUser = id
+	s.NoRegistrasi = fmt.Sprintf("MNU-%s-%d-%05d", kab.kode, s.TglRegistrasi.Year(), id)
+	s.NoUrut = fmt.Sprintf("%06d", id)
+	g.data.satpen = append(g.data.satpen, s)
+
+	g.data.users = append(g.data.users, userRow{
+		IDUser:       id,
+		Name:         truncate(s.NmSatpen, 100),
+		Username:     "operator" + s.NPSN,
+		Password:     passwordPlaceholder,
+		Role:         "operator",
+		StatusActive: "active",
+		ProvID:       strconv.Itoa(int(s.IDProv)),
+		CabangID:     strconv.Itoa(int(s.IDPC)),
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.CreatedAt,
+	})
+
+	g.addTimeline(id, "permohonan", s.TglRegistrasi, "Pengajuan registrasi")
+	if s.Status == "permohonan" {
+		return
+	}
+	verified := s.TglRegistrasi.AddDate(0, 0, 3+g.rng.Intn(10))
+	if s.ActivedDate != nil && verified.After(*s.ActivedDate) {
+		verified = s.ActivedDate.AddDate(0, 0, -1)
+	}
+	if s.Status == "revisi" {
+		g.addTimeline(id, "revisi", verified, "Dokumen perlu diperbaiki")
+		return
+	}
+	g.addTimeline(id, "proses dokumen", verified, "Dokumen lengkap")
+	if s.ActivedDate == nil {
+		return
+	}
+	g.addTimeline(id, "setujui", *s.ActivedDate, "Piagam diterbitkan")
+	if s.Status == "expired" || s.Status == "perpanjangan" {
+		g.addTimeline(id, "expired", s.ActivedDate.AddDate(g.opts.ValidYears, 0, 0), "Masa berlaku piagam habis")
+	}
+	if s.Status == "perpanjangan" {
+		g.addTimeline(id, "perpanjangan", s.UpdatedAt, "Pengajuan perpanjangan")
+	}
+}
+
+func (g *generator) addTimeline(idSatpen uint, status string, at time.Time, keterangan string) {
+	g.data.timeline = append(g.data.timeline, timelineRow{
+		IDTimeline:       uint(len(g.data.timeline) + 1),
+		IDSatpen:         idSatpen,
+		StatusVerifikasi: status,
+		TglStatus:        at,
+		Keterangan:       keterangan,
+		CreatedAt:        at,
+		UpdatedAt:        at,
+	})
+}
+
+// registrationDates sets tgl_registrasi and actived_date consistent with the
+// status: pending registrations are recent, approved ones are within the
+// validity, expired ones beyond it
+func (g *generator) registrationDates(s *models.Satpen) {
+	until := g.opts.Until
+	midnight := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, until.Location())
+	valid := g.opts.ValidYears * 365
+	// day returns office hours n days before until
+	day := func(n int) time.Time {
+		return midnight.AddDate(0, 0, -n).Add(time.Duration(8+g.rng.Intn(8)) * time.Hour)
+	}
+
+	var actived time.Time
+	switch s.Status {
+	case "permohonan", "revisi", "proses dokumen":
+		s.TglRegistrasi = day(g.rng.Intn(180))
+		s.ActivedDate = nil
+		s.CreatedAt, s.UpdatedAt = s.TglRegistrasi, s.TglRegistrasi
+		return
+	case "setujui":
+		actived = day(g.rng.Intn(valid - 30))
+	case "perpanjangan":
+		actived = day(valid + g.rng.Intn(180))
+	default: // expired
+		actived = day(valid + 1 + g.rng.Intn(5*365))
+	}
+	s.ActivedDate = &actived
+	s.TglRegistrasi = actived.AddDate(0, 0, -(7 + g.rng.Intn(60)))
+	s.CreatedAt = s.TglRegistrasi
+	s.UpdatedAt = actived
+	if s.Status == "perpanjangan" {
+		s.UpdatedAt = day(g.rng.Intn(60))
+	}
+}
+
+// dirty breaks one field of s the way the data quality rules look for
+func (g *generator) dirty(s *models.Satpen) {
+	switch g.rng.Intn(4) {
+	case 0:
+		s.Kepsek = ""
+	case 1:
+		s.Telpon = []string{"-", "0812", "08xx-1234"}[g.rng.Intn(3)]
+	case 2:
+		s.Email = strings.TrimSuffix(s.Email, ".sch.id")
+		if s.Email == "" {
+			s.Email = "belum ada"
+		}
+	default:
+		s.ThnBerdiri = g.opts.Until.Year() + 1 + g.rng.Intn(3)
+	}
+}
+
+// genPDPTK adds the PDPTK of every semester. Expired satpen stopped syncing
+// a year ago; a few active ones miss the current semester.
+func (g *generator) genPDPTK(s *models.Satpen, siswaMin, siswaMax int) {
+	semesters := g.semesters
+	if s.Status == "expired" {
+		if len(semesters) <= 2 {
+			return
+		}
+		semesters = semesters[:len(semesters)-2]
+	} else if g.rng.Float64() < 0.1 {
+		semesters = semesters[:len(semesters)-1]
+	}
+
+	siswa := siswaMin + g.rng.Intn(siswaMax-siswaMin+1)
+	ratio := 8 + g.rng.Float64()*17 // siswa per guru
+	persenPR := 0.4 + g.rng.Float64()*0.35
+	for _, sem := range semesters {
+		if sem.start.Before(s.TglRegistrasi.AddDate(0, -6, 0)) {
+			continue
+		}
+		siswa = int(math.Max(10, float64(siswa)*(0.95+g.rng.Float64()*0.1)))
+		guru := int(math.Max(3, math.Round(float64(siswa)/ratio)))
+		tendik := guru/5 + g.rng.Intn(3)
+		pdPR := int(float64(siswa) * (0.45 + g.rng.Float64()*0.1))
+		guruPR := int(math.Round(float64(guru) * persenPR))
+		tendikPR := g.rng.Intn(tendik + 1)
+
+		synced := sem.start.AddDate(0, 0, g.rng.Intn(60))
+		if synced.After(g.opts.Until) {
+			synced = g.opts.Until
+		}
+		g.data.pdptk = append(g.data.pdptk, pdptkRow{
+			ID:            len(g.data.pdptk) + 1,
+			IDSatpen:      s.IDSatpen,
+			Tapel:         sem.tapel,
+			PDLK:          siswa - pdPR,
+			PDPR:          pdPR,
+			JmlPD:         siswa,
+			GuruLK:        guru - guruPR,
+			GuruPR:        guruPR,
+			JmlGuru:       guru,
+			TendikLK:      tendik - tendikPR,
+			TendikPR:      tendikPR,
+			JmlTendik:     tendik,
+			LastSinkron:   synced,
+			StatusSinkron: models.StatusSinkronBerhasil,
+		})
+	}
+
+	for i, n := 0, g.rng.Intn(2*g.opts.PTKPerSatpen+1); i < n; i++ {
+		g.genPTK(s)
+	}
+}
+
+func (g *generator) genPTK(s *models.Satpen) {
+	id := uint(len(g.data.ptk) + 1)
+	kab := g.kabs[s.IDKab-1]
+	female := g.rng.Intn(2) == 0
+	jenisKelamin := "Laki-Laki"
+	if female {
+		jenisKelamin = "Perempuan"
+	}
+	born := time.Date(1965+g.rng.Intn(35), time.Month(1+g.rng.Intn(12)), 1+g.rng.Intn(28), 0, 0, 0, 0, time.Local)
+	tmt := born.AddDate(22+g.rng.Intn(10), g.rng.Intn(12), 0)
+	if tmt.After(g.opts.Until) {
+		tmt = g.opts.Until.AddDate(0, -1, 0)
+	}
+	name := g.personName()
+	jenisPTK := []string{"Guru Kelas", "Guru Mapel", "Guru BK", "Tenaga Administrasi Sekolah", "Kepala Sekolah"}[g.rng.Intn(5)]
+	statusAjuan := []string{"verifikasi", "proses", "approve", "dikeluarkan"}[g.rng.Intn(4)]
+
+	g.data.ptk = append(g.data.ptk, ptkRow{
+		ID:                id,
+		IDSatpen:          s.IDSatpen,
+		NIK:               fmt.Sprintf("%s%012d", kab.kode, id),
+		NamaPTK:           name,
+		TempatLahir:       strings.TrimPrefix(strings.TrimPrefix(kab.nama, "Kab. "), "Kota "),
+		TanggalLahir:      born,
+		JenisKelamin:      jenisKelamin,
+		NamaIbu:           g.personName(),
+		Agama:             "Islam",
+		StatusPerkawinan:  []string{"Menikah", "Belum Menikah"}[g.rng.Intn(2)],
+		Email:             fmt.Sprintf("ptk%d@example.com", id),
+		KabupatenKota:     kab.nama,
+		Kecamatan:         s.Kecamatan,
+		DesaKelurahan:     s.Kelurahan,
+		Alamat:            g.alamat(),
+		KodePos:           fmt.Sprintf("%05d", 10000+g.rng.Intn(89999)),
+		JenisPTK:          jenisPTK,
+		StatusKepegawaian: []string{"GTY/PTY", "Guru Honor Sekolah", "PNS Depag", "PPPK"}[g.rng.Intn(4)],
+		LembagaPengangkat: []string{"Ketua Yayasan", "Kepala Sekolah", "Pemerintah Pusat"}[g.rng.Intn(3)],
+		NoSKPengangkatan:  fmt.Sprintf("%d/SK/YYS/%d", id, tmt.Year()),
+		TMTPengangkatan:   tmt,
+		SumberGaji:        []string{"Yayasan", "Sekolah", "APBN"}[g.rng.Intn(3)],
+		NomorSuratTugas:   fmt.Sprintf("%d/ST/%d", id, tmt.Year()),
+		TanggalSuratTugas: tmt,
+		TMTTugas:          tmt,
+		UploadSK:          fmt.Sprintf("sk/ptk-%d.pdf", id),
+		StatusAjuan:       statusAjuan,
+		CreatedAt:         s.CreatedAt,
+		UpdatedAt:         s.UpdatedAt,
+	})
+}
+
+// npsn returns the next 8 digit NPSN of a provinsi: its code and a sequence
+func (g *generator) npsn(provIdx int) string {
+	g.npsnSeq[provIdx]++
+	return fmt.Sprintf("%s%06d", provinsiData[provIdx].Kode, g.npsnSeq[provIdx])
+}
+
+// pickWeighted returns an index in [0, n) with probability weight(i)
+func (g *generator) pickWeighted(n int, weight func(i int) int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += weight(i)
+	}
+	r := g.rng.Intn(total)
+	for i := 0; i < n; i++ {
+		if r < weight(i) {
+			return i
+		}
+		r -= weight(i)
+	}
+	return n - 1
+}
+
+func (g *generator) placeName() string {
+	return placePrefixes[g.rng.Intn(len(placePrefixes))] + placeSuffixes[g.rng.Intn(len(placeSuffixes))]
+}
+
+// uniquePlaceName returns a place name not returned before, for kabupaten
+func (g *generator) uniquePlaceName() string {
+	directions := []string{"Utara", "Selatan", "Timur", "Barat", "Tengah"}
+	for {
+		name := g.placeName()
+		if g.usedNames[name] {
+			name += " " + directions[g.rng.Intn(len(directions))]
+		}
+		if !g.usedNames[name] {
+			g.usedNames[name] = true
+			return name
+		}
+	}
+}
+
+// variant writes name the way operators do: mostly as is, sometimes with an
+// administrative prefix or in capitals
+func (g *generator) variant(name, prefix string) string {
+	switch r := g.rng.Intn(10); {
+	case r == 0:
+		return prefix + name
+	case r == 1:
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+func (g *generator) personName() string {
+	return firstNames[g.rng.Intn(len(firstNames))] + " " + lastNames[g.rng.Intn(len(lastNames))]
+}
+
+func (g *generator) phone() string {
+	return fmt.Sprintf("08%d%08d", 1+g.rng.Intn(9), g.rng.Intn(100000000))
+}
+
+func (g *generator) alamat() string {
+	return fmt.Sprintf("Jl. %s No. %d RT %02d/RW %02d", streetNames[g.rng.Intn(len(streetNames))], 1+g.rng.Intn(150), 1+g.rng.Intn(10), 1+g.rng.Intn(8))
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
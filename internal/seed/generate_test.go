@@ -0,0 +1,110 @@
+package seed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testOptions(seed int64) Options {
+	opts := DefaultOptions(time.Date(2024, 9, 30, 12, 0, 0, 0, time.Local))
+	opts.Seed = seed
+	opts.Satpen = 400
+	// High enough that dirty rows and duplicates are part of the sample
+	opts.DirtyRate = 0.2
+	opts.DuplicateRate = 0.1
+	return opts
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	first := generate(testOptions(7))
+	if !reflect.DeepEqual(first, generate(testOptions(7))) {
+		t.Error("the same Options generated two different datasets")
+	}
+
+	other := generate(testOptions(8))
+	if reflect.DeepEqual(first.satpen, other.satpen) {
+		t.Error("a different Seed generated the same satpen")
+	}
+}
+
+func TestGenerateReferences(t *testing.T) {
+	data := generate(testOptions(7))
+	if len(data.satpen) != 400 {
+		t.Fatalf("%d satpen, want 400", len(data.satpen))
+	}
+
+	provinsi := make(map[uint]bool)
+	for _, p := range data.provinsi {
+		provinsi[p.IDProv] = true
+	}
+	for _, p := range data.profilePW {
+		if !provinsi[p.IDPW] {
+			t.Errorf("profile_pengurus_wilayah %d: unknown provinsi %d", p.ID, p.IDPW)
+		}
+	}
+	kabProv := make(map[uint]uint)
+	for _, k := range data.kabupaten {
+		if !provinsi[k.IDProv] {
+			t.Errorf("kabupaten %d: unknown provinsi %d", k.IDKab, k.IDProv)
+		}
+		kabProv[k.IDKab] = k.IDProv
+	}
+	pcProv := make(map[uint]uint)
+	for _, pc := range data.pc {
+		pcProv[pc.IDPC] = pc.IDProv
+	}
+	for _, p := range data.profilePC {
+		if _, ok := pcProv[p.IDPC]; !ok {
+			t.Errorf("profile_pengurus_cabang %d: unknown pengurus cabang %d", p.ID, p.IDPC)
+		}
+	}
+	tapel := make(map[string]bool)
+	for _, tp := range data.tapel {
+		tapel[tp.TapelDapo] = true
+	}
+
+	satpen := make(map[uint]bool)
+	npsn := make(map[string]uint)
+	for _, s := range data.satpen {
+		satpen[s.IDSatpen] = true
+		if prov, ok := kabProv[s.IDKab]; !ok || prov != s.IDProv {
+			t.Errorf("satpen %d: kabupaten %d is not in provinsi %d", s.IDSatpen, s.IDKab, s.IDProv)
+		}
+		// One pengurus cabang per kabupaten, sharing its ID
+		if prov, ok := pcProv[s.IDPC]; !ok || s.IDPC != s.IDKab || prov != s.IDProv {
+			t.Errorf("satpen %d: pengurus cabang %d does not match kabupaten %d", s.IDSatpen, s.IDPC, s.IDKab)
+		}
+		if s.IDJenjang < 1 || int(s.IDJenjang) > len(data.jenjang) {
+			t.Errorf("satpen %d: unknown jenjang %d", s.IDSatpen, s.IDJenjang)
+		}
+		if s.IDKategori != nil && (*s.IDKategori < 1 || int(*s.IDKategori) > len(data.kategori)) {
+			t.Errorf("satpen %d: unknown kategori %d", s.IDSatpen, *s.IDKategori)
+		}
+		if other, ok := npsn[s.NPSN]; ok {
+			t.Errorf("satpen %d and %d share NPSN %s", other, s.IDSatpen, s.NPSN)
+		}
+		npsn[s.NPSN] = s.IDSatpen
+	}
+
+	for i, u := range data.users {
+		if u.IDUser != data.satpen[i].IDUser {
+			t.Errorf("user %d is not the user of satpen %d", u.IDUser, data.satpen[i].IDSatpen)
+		}
+	}
+	for _, tl := range data.timeline {
+		if !satpen[tl.IDSatpen] {
+			t.Errorf("timeline_reg %d: unknown satpen %d", tl.IDTimeline, tl.IDSatpen)
+		}
+	}
+	for _, p := range data.pdptk {
+		if !satpen[p.IDSatpen] || !tapel[p.Tapel] {
+			t.Errorf("pdptk %d: unknown satpen %d or tapel %s", p.ID, p.IDSatpen, p.Tapel)
+		}
+	}
+	for _, p := range data.ptk {
+		if !satpen[p.IDSatpen] {
+			t.Errorf("ptk %d: unknown satpen %d", p.ID, p.IDSatpen)
+		}
+	}
+}
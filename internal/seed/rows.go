@@ -0,0 +1,94 @@
+package seed
+
+import "time"
+
+// Rows of the tables that have no model in internal/models, or whose model
+// would turn zero counts into NULL (models.PDPTK has default:0 tags)
+
+type userRow struct {
+	IDUser       uint      `gorm:"column:id_user;primaryKey"`
+	Name         string    `gorm:"column:name"`
+	Username     string    `gorm:"column:username"`
+	Password     string    `gorm:"column:password"`
+	Role         string    `gorm:"column:role"`
+	StatusActive string    `gorm:"column:status_active"`
+	ProvID       string    `gorm:"column:provId"`
+	CabangID     string    `gorm:"column:cabangId"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+func (userRow) TableName() string {
+	return "users"
+}
+
+type timelineRow struct {
+	IDTimeline       uint      `gorm:"column:id_timeline;primaryKey"`
+	IDSatpen         uint      `gorm:"column:id_satpen"`
+	StatusVerifikasi string    `gorm:"column:status_verifikasi"`
+	TglStatus        time.Time `gorm:"column:tgl_status"`
+	Keterangan       string    `gorm:"column:keterangan"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at"`
+}
+
+func (timelineRow) TableName() string {
+	return "timeline_reg"
+}
+
+type pdptkRow struct {
+	ID            int       `gorm:"column:id;primaryKey"`
+	IDSatpen      uint      `gorm:"column:id_satpen"`
+	Tapel         string    `gorm:"column:tapel"`
+	PDLK          int       `gorm:"column:pd_lk"`
+	PDPR          int       `gorm:"column:pd_pr"`
+	JmlPD         int       `gorm:"column:jml_pd"`
+	GuruLK        int       `gorm:"column:guru_lk"`
+	GuruPR        int       `gorm:"column:guru_pr"`
+	JmlGuru       int       `gorm:"column:jml_guru"`
+	TendikLK      int       `gorm:"column:tendik_lk"`
+	TendikPR      int       `gorm:"column:tendik_pr"`
+	JmlTendik     int       `gorm:"column:jml_tendik"`
+	LastSinkron   time.Time `gorm:"column:last_sinkron"`
+	StatusSinkron int       `gorm:"column:status_sinkron"`
+}
+
+func (pdptkRow) TableName() string {
+	return "pdptk"
+}
+
+type ptkRow struct {
+	ID                uint      `gorm:"column:id;primaryKey"`
+	IDSatpen          uint      `gorm:"column:id_satpen"`
+	NIK               string    `gorm:"column:nik"`
+	NamaPTK           string    `gorm:"column:nama_ptk"`
+	TempatLahir       string    `gorm:"column:tempat_lahir"`
+	TanggalLahir      time.Time `gorm:"column:tanggal_lahir;type:date"`
+	JenisKelamin      string    `gorm:"column:jenis_kelamin"`
+	NamaIbu           string    `gorm:"column:nama_ibu"`
+	Agama             string    `gorm:"column:agama"`
+	StatusPerkawinan  string    `gorm:"column:status_perkawinan"`
+	Email             string    `gorm:"column:email"`
+	KabupatenKota     string    `gorm:"column:kabupaten_kota"`
+	Kecamatan         string    `gorm:"column:kecamatan"`
+	DesaKelurahan     string    `gorm:"column:desa_kelurahan"`
+	Alamat            string    `gorm:"column:alamat"`
+	KodePos           string    `gorm:"column:kode_pos"`
+	JenisPTK          string    `gorm:"column:jenis_ptk"`
+	StatusKepegawaian string    `gorm:"column:status_kepegawaian"`
+	LembagaPengangkat string    `gorm:"column:lembaga_pengangkat"`
+	NoSKPengangkatan  string    `gorm:"column:no_sk_pengangkatan"`
+	TMTPengangkatan   time.Time `gorm:"column:tmt_pengangkatan;type:date"`
+	SumberGaji        string    `gorm:"column:sumber_gaji"`
+	NomorSuratTugas   string    `gorm:"column:nomor_surat_tugas"`
+	TanggalSuratTugas time.Time `gorm:"column:tanggal_surat_tugas;type:date"`
+	TMTTugas          time.Time `gorm:"column:tmt_tugas;type:date"`
+	UploadSK          string    `gorm:"column:upload_sk"`
+	StatusAjuan       string    `gorm:"column:status_ajuan"`
+	CreatedAt         time.Time `gorm:"column:created_at"`
+	UpdatedAt         time.Time `gorm:"column:updated_at"`
+}
+
+func (ptkRow) TableName() string {
+	return "ptk"
+}
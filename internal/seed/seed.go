@@ -0,0 +1,121 @@
+// Package seed fills an empty database with synthetic provinsi, kabupaten,
+// pengurus cabang, jenjang, kategori, satpen, PDPTK and PTK rows for local
+// development and load tests. The same Options always produce the same rows,
+// IDs included.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tables are the tables written by Run, in insert order. Reset empties them.
+var Tables = []string{
+	"provinsi", "profile_pengurus_wilayah", "kabupaten", "pengurus_cabang",
+	"profile_pengurus_cabang", "jenjang_pendidikan", "kategori_satpen",
+	"tahun_pelajaran", "users", "satpen", "timeline_reg", "pdptk", "ptk",
+}
+
+// ErrNotEmpty is returned by Run when the database already has satpen
+var ErrNotEmpty = errors.New("database already has satpen; reset it first")
+
+// Options controls the volume and shape of the generated data
+type Options struct {
+	Seed         int64     // random seed
+	Satpen       int       // number of satpen
+	PTKPerSatpen int       // average PTK rows per satpen
+	Semesters    int       // tahun pelajaran semesters with PDPTK, up to Until
+	Until        time.Time // last date of registrations and syncs
+	ValidYears   int       // registration validity, expired satpen are older
+	BatchSize    int       // rows per INSERT
+
+	// Rates (0-1) of deliberately bad data, so the data quality and
+	// duplicate reports have something to find
+	DirtyRate     float64
+	DuplicateRate float64
+}
+
+// DefaultOptions are 15.000 satpen with 2 PTK each and 4 semesters of PDPTK
+// up to until
+func DefaultOptions(until time.Time) Options {
+	return Options{
+		Seed:          1,
+		Satpen:        15000,
+		PTKPerSatpen:  2,
+		Semesters:     4,
+		Until:         until,
+		ValidYears:    4,
+		BatchSize:     1000,
+		DirtyRate:     0.03,
+		DuplicateRate: 0.01,
+	}
+}
+
+// TableCount is the number of rows written to a table
+type TableCount struct {
+	Table string
+	Rows  int
+}
+
+// Run generates the data described by opts and inserts it. The tables must
+// be empty (see Reset) since the rows carry fixed IDs.
+func Run(db *gorm.DB, opts Options) ([]TableCount, error) {
+	if opts.Satpen < 1 {
+		return nil, fmt.Errorf("satpen must be at least 1")
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1000
+	}
+	if opts.Semesters < 1 {
+		opts.Semesters = 1
+	}
+
+	var existing int64
+	if err := db.Table("satpen").Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrNotEmpty
+	}
+
+	data := generate(opts)
+
+	var counts []TableCount
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range data.tables() {
+			if table.rows == 0 {
+				continue
+			}
+			if err := tx.CreateInBatches(table.value, opts.BatchSize).Error; err != nil {
+				return fmt.Errorf("failed to insert %s: %w", table.name, err)
+			}
+			counts = append(counts, TableCount{Table: table.name, Rows: table.rows})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// Reset empties Tables. Foreign key checks are off while truncating, on the
+// same connection.
+func Reset(db *gorm.DB) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+
+		for i := len(Tables) - 1; i >= 0; i-- {
+			if err := conn.Exec("TRUNCATE TABLE " + Tables[i]).Error; err != nil {
+				return fmt.Errorf("failed to truncate %s: %w", Tables[i], err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"satpen-api/internal/config"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+)
+
+type AuditService interface {
+	GetAuditLog(filter *models.AuditFilter, page, limit int) ([]models.AuditLog, *PaginationMeta, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+	cfg  *config.Config
+}
+
+func NewAuditService(repo repository.AuditRepository, cfg *config.Config) AuditService {
+	return &auditService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// GetAuditLog returns one page of the audit log matching filter, newest first
+func (s *auditService) GetAuditLog(filter *models.AuditFilter, page, limit int) ([]models.AuditLog, *PaginationMeta, error) {
+	if page < 1 {
+		page = s.cfg.Pagination.DefaultPage
+	}
+	if limit < 1 {
+		limit = s.cfg.Pagination.DefaultLimit
+	}
+	if limit > s.cfg.Pagination.MaxLimit {
+		limit = s.cfg.Pagination.MaxLimit
+	}
+
+	entries, total, err := s.repo.FindAll(filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   total,
+		ItemsPerPage: limit,
+		HasNext:      page < totalPages,
+		HasPrev:      page > 1,
+	}
+	return entries, pagination, nil
+}
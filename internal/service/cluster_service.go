@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"satpen-api/internal/cluster"
+	"satpen-api/internal/config"
+	"satpen-api/internal/repository"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ClusterService interface {
+	// Refresh rebuilds the index from the database
+	Refresh() error
+	// Start rebuilds the index every cfg.Cluster.RefreshInterval until ctx is done
+	Start(ctx context.Context)
+	// Clusters returns the clusters and single satpen in bbox at zoom,
+	// optionally only of the given jenjang
+	Clusters(bbox cluster.BBox, zoom int, jenjang []string) (*cluster.Result, error)
+}
+
+type clusterService struct {
+	repo  repository.SatpenRepository
+	cfg   *config.Config
+	log   *logrus.Logger
+	index atomic.Pointer[cluster.Index]
+}
+
+func NewClusterService(repo repository.SatpenRepository, cfg *config.Config, log *logrus.Logger) ClusterService {
+	return &clusterService{
+		repo: repo,
+		cfg:  cfg,
+		log:  log,
+	}
+}
+
+func (s *clusterService) Refresh() error {
+	points, err := s.repo.FindClusterPoints()
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+	index := cluster.Build(points)
+	s.index.Store(index)
+	s.log.WithFields(logrus.Fields{
+		"points":  index.Len(),
+		"took_ms": time.Since(started).Milliseconds(),
+	}).Info("Cluster index rebuilt")
+	return nil
+}
+
+func (s *clusterService) Start(ctx context.Context) {
+	interval := time.Duration(s.cfg.Cluster.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Refresh(); err != nil {
+				// Keep serving the previous index
+				s.log.WithError(err).Error("Failed to refresh cluster index")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *clusterService) Clusters(bbox cluster.BBox, zoom int, jenjang []string) (*cluster.Result, error) {
+	index := s.index.Load()
+	if index == nil {
+		return nil, errors.New("cluster index not ready")
+	}
+	if err := bbox.Validate(); err != nil {
+		return nil, err
+	}
+
+	pointZoom := s.cfg.Cluster.PointZoom
+	if pointZoom <= 0 {
+		pointZoom = 14
+	}
+	maxPoints := s.cfg.Cluster.MaxPoints
+	if maxPoints <= 0 {
+		maxPoints = 2000
+	}
+
+	var keep func(*cluster.Point) bool
+	if len(jenjang) > 0 {
+		// Case-insensitive like the jenjang filter in SQL
+		wanted := make(map[string]bool, len(jenjang))
+		for _, j := range jenjang {
+			wanted[strings.ToLower(j)] = true
+		}
+		keep = func(p *cluster.Point) bool { return wanted[strings.ToLower(p.Jenjang)] }
+	}
+
+	result := index.Query(bbox, zoom, pointZoom, maxPoints, keep)
+	return &result, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+)
+
+var (
+	// ErrInvalidCursor is returned for cursors that can't be decoded or were
+	// issued for a different sort
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorSort is returned when the sort can't be paginated by cursor
+	ErrCursorSort = errors.New("sort not supported for cursor pagination (use nama, tahun_berdiri, created_at or updated_at)")
+)
+
+// cursorToken is the JSON payload of an opaque cursor
+type cursorToken struct {
+	Sort     string `json:"s"`
+	Value    string `json:"v"`
+	ID       uint   `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// encodeCursor returns a cursor pointing after (or before, when backward) s
+func encodeCursor(sort string, s *models.Satpen, backward bool) (string, error) {
+	value, err := repository.KeysetValue(sort, s)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.Marshal(cursorToken{Sort: sort, Value: value, ID: s.IDSatpen, Backward: backward})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeCursor parses cursor and checks it was issued for sort
+func decodeCursor(cursor, sort string) (*repository.Keyset, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if token.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+
+	return &repository.Keyset{Value: token.Value, ID: token.ID, Backward: token.Backward}, nil
+}
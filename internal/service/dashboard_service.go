@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"satpen-api/internal/config"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultExpiringWithinDays is used when dashboard.expiring_within_days is 0
+const defaultExpiringWithinDays = 90
+
+// ptkAwaitingVerification is the ptk.status_ajuan of new submissions
+const ptkAwaitingVerification = "verifikasi"
+
+type DashboardService interface {
+	// GetPengurusCabangDashboard returns the aggregates of the satpen of one
+	// pengurus cabang
+	GetPengurusCabangDashboard(idPC uint) (*models.PengurusCabangDashboard, error)
+}
+
+type dashboardService struct {
+	satpenRepo repository.SatpenRepository
+	masterRepo repository.MasterRepository
+	cfg        *config.Config
+}
+
+func NewDashboardService(satpenRepo repository.SatpenRepository, masterRepo repository.MasterRepository, cfg *config.Config) DashboardService {
+	return &dashboardService{
+		satpenRepo: satpenRepo,
+		masterRepo: masterRepo,
+		cfg:        cfg,
+	}
+}
+
+func (s *dashboardService) GetPengurusCabangDashboard(idPC uint) (*models.PengurusCabangDashboard, error) {
+	pc, err := s.masterRepo.GetPengurusCabangByID(idPC)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pengurus cabang not found")
+		}
+		return nil, err
+	}
+
+	dashboard := &models.PengurusCabangDashboard{
+		PengurusCabang: pc,
+		ByJenjang:      make(map[string]models.DashboardJenjang),
+		ByStatus:       make(map[string]int64),
+		ByAkreditasi:   make(map[string]int64),
+		PendingRegistrations: models.PendingRegistrations{
+			ByStatus: make(map[string]int64),
+		},
+	}
+
+	// Every status, so that pending and expired registrations are counted too
+	everyStatus := &models.SatpenFilter{PengurusCabangID: idPC, Status: models.SatpenStatuses}
+
+	counts, err := s.satpenRepo.CountByJenjangStatus(everyStatus)
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		dashboard.TotalSatpen += count.Count
+		dashboard.ByStatus[count.Status] += count.Count
+
+		jenjang, ok := dashboard.ByJenjang[count.Jenjang]
+		if !ok {
+			jenjang.ByStatus = make(map[string]int64)
+		}
+		jenjang.Count += count.Count
+		jenjang.ByStatus[count.Status] += count.Count
+
+		if containsString(models.ListedSatpenStatuses, count.Status) {
+			jenjang.Siswa += count.Siswa
+			jenjang.Guru += count.Guru
+			jenjang.Tendik += count.Tendik
+			dashboard.TotalSiswa += count.Siswa
+			dashboard.TotalGuru += count.Guru
+			dashboard.TotalTendik += count.Tendik
+		}
+		if containsString(models.PendingSatpenStatuses, count.Status) {
+			dashboard.PendingRegistrations.Total += count.Count
+			dashboard.PendingRegistrations.ByStatus[count.Status] += count.Count
+		}
+		dashboard.ByJenjang[count.Jenjang] = jenjang
+	}
+
+	akreditasi, err := s.satpenRepo.CountByAkreditasi(&models.SatpenFilter{PengurusCabangID: idPC})
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range akreditasi {
+		dashboard.ByAkreditasi[count.Label()] = count.Count
+	}
+
+	// Without a validity period registrations never expire
+	if years := s.cfg.Dashboard.RegistrationValidYears; years > 0 {
+		withinDays := s.cfg.Dashboard.ExpiringWithinDays
+		if withinDays <= 0 {
+			withinDays = defaultExpiringWithinDays
+		}
+		now := time.Now()
+		expiring, err := s.satpenRepo.FindExpiring(
+			&models.SatpenFilter{PengurusCabangID: idPC, Status: []string{"setujui"}},
+			years, now, now.AddDate(0, 0, withinDays))
+		if err != nil {
+			return nil, err
+		}
+		if expiring == nil {
+			expiring = []models.ExpiringSatpen{}
+		}
+		dashboard.ExpiringSoon = &models.ExpiringSoon{
+			WithinDays: withinDays,
+			Total:      len(expiring),
+			Satpen:     expiring,
+		}
+	}
+
+	dashboard.PTKPendingVerification, err = s.satpenRepo.CountPTK(everyStatus, ptkAwaitingVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
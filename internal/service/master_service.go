@@ -18,6 +18,14 @@ type MasterService interface {
 	GetAllPengurusCabang(filters map[string]interface{}, page, limit int) ([]models.PengurusCabang, int64, error)
 	GetPengurusCabangByID(id uint) (*models.PengurusCabang, error)
 
+	// Pengurus Wilayah
+	GetAllPengurusWilayah(search string) ([]models.PengurusWilayah, error)
+	GetPengurusWilayahByProvinsi(idProv uint) (*models.PengurusWilayah, error)
+
+	// Kategori Satpen
+	GetAllKategoriSatpen(search string) ([]models.KategoriSatpenWithCount, error)
+	GetKategoriSatpenByID(id uint) (*models.KategoriSatpenWithCount, error)
+
 	// Jenjang Pendidikan
 	GetAllJenjangPendidikan(search string) ([]models.JenjangPendidikan, error)
 	GetJenjangPendidikanByID(id uint) (*models.JenjangPendidikan, error)
@@ -58,6 +66,24 @@ func (s *masterService) GetPengurusCabangByID(id uint) (*models.PengurusCabang,
 	return s.repo.GetPengurusCabangByID(id)
 }
 
+// Pengurus Wilayah Methods
+func (s *masterService) GetAllPengurusWilayah(search string) ([]models.PengurusWilayah, error) {
+	return s.repo.GetAllPengurusWilayah(search)
+}
+
+func (s *masterService) GetPengurusWilayahByProvinsi(idProv uint) (*models.PengurusWilayah, error) {
+	return s.repo.GetPengurusWilayahByProvinsi(idProv)
+}
+
+// Kategori Satpen Methods
+func (s *masterService) GetAllKategoriSatpen(search string) ([]models.KategoriSatpenWithCount, error) {
+	return s.repo.GetAllKategoriSatpen(search)
+}
+
+func (s *masterService) GetKategoriSatpenByID(id uint) (*models.KategoriSatpenWithCount, error) {
+	return s.repo.GetKategoriSatpenByID(id)
+}
+
 // Jenjang Pendidikan Methods
 func (s *masterService) GetAllJenjangPendidikan(search string) ([]models.JenjangPendidikan, error) {
 	return s.repo.GetAllJenjangPendidikan(search)
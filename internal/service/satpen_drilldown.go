@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"satpen-api/internal/models"
+	"satpen-api/internal/region"
+	"satpen-api/internal/repository"
+	"sort"
+	"strings"
+)
+
+// ErrDrillDownPath is returned for a kecamatan without its kabupaten
+var ErrDrillDownPath = errors.New("kecamatan_key requires kabupaten_id")
+
+// unknownPlace names the node of satpen without kecamatan/kelurahan
+const unknownPlace = "Tidak diketahui"
+
+// DrillDownPath selects the node whose children are returned. The root
+// (zero value) returns provinsi, provinsi returns kabupaten, kabupaten
+// returns kecamatan and kabupaten plus kecamatan returns kelurahan.
+type DrillDownPath struct {
+	ProvinsiID  uint
+	KabupatenID uint
+	// Kecamatan is any spelling of the kecamatan, matched by its key
+	Kecamatan string
+}
+
+// GetDrillDown returns the satpen counts and learner totals of the children
+// of path, restricted to filter. Kecamatan and kelurahan names are free text,
+// so spelling variants with the same region.Normalize key are merged.
+func (s *satpenService) GetDrillDown(path DrillDownPath, filter *models.SatpenFilter) (*models.DrillDown, error) {
+	if path.Kecamatan != "" && path.KabupatenID == 0 {
+		return nil, ErrDrillDownPath
+	}
+	if filter == nil {
+		filter = &models.SatpenFilter{}
+	}
+	s.applySearchDefaults(filter)
+	filter.ProvinsiID = path.ProvinsiID
+	filter.KabupatenID = path.KabupatenID
+
+	drillDown := &models.DrillDown{}
+	switch {
+	case path.Kecamatan != "":
+		places, err := s.repo.CountByPlace(repository.RegionKelurahan, filter)
+		if err != nil {
+			return nil, err
+		}
+		key := region.Normalize(path.Kecamatan)
+		inKecamatan := places[:0]
+		for _, place := range places {
+			if region.Normalize(place.Kecamatan) == key {
+				inKecamatan = append(inKecamatan, place)
+			}
+		}
+		drillDown.Level = repository.RegionKelurahan
+		drillDown.Nodes = mergePlaces(inKecamatan, func(p models.PlaceCount) string { return p.Kelurahan })
+
+	case path.KabupatenID > 0:
+		places, err := s.repo.CountByPlace(repository.RegionKecamatan, filter)
+		if err != nil {
+			return nil, err
+		}
+		drillDown.Level = repository.RegionKecamatan
+		drillDown.Nodes = mergePlaces(places, func(p models.PlaceCount) string { return p.Kecamatan })
+
+	default:
+		level := repository.RegionProvinsi
+		if path.ProvinsiID > 0 {
+			level = repository.RegionKabupaten
+		}
+		regions, err := s.repo.CountByRegion(level, filter)
+		if err != nil {
+			return nil, err
+		}
+		drillDown.Level = level
+		drillDown.Nodes = make([]models.DrillDownNode, 0, len(regions))
+		for _, r := range regions {
+			drillDown.Nodes = append(drillDown.Nodes, models.DrillDownNode{
+				ID:    r.ID,
+				Nama:  r.Nama,
+				Count: r.Count,
+				Siswa: r.Siswa,
+				Guru:  r.Guru,
+			})
+		}
+		sortDrillDownNodes(drillDown.Nodes)
+	}
+
+	for _, node := range drillDown.Nodes {
+		drillDown.TotalSatpen += node.Count
+		drillDown.TotalSiswa += node.Siswa
+		drillDown.TotalGuru += node.Guru
+	}
+	return drillDown, nil
+}
+
+// mergePlaces groups places by the normalised key of name(place), keeping
+// the raw spellings as variants
+func mergePlaces(places []models.PlaceCount, name func(models.PlaceCount) string) []models.DrillDownNode {
+	index := make(map[string]int)
+	nodes := []models.DrillDownNode{}
+	for _, place := range places {
+		raw := strings.TrimSpace(name(place))
+		key := region.Normalize(raw)
+
+		i, ok := index[key]
+		if !ok {
+			nama := region.Title(key)
+			if key == "" {
+				nama = unknownPlace
+			}
+			i = len(nodes)
+			index[key] = i
+			nodes = append(nodes, models.DrillDownNode{Key: key, Nama: nama})
+		}
+
+		node := &nodes[i]
+		node.Count += place.Count
+		node.Siswa += place.Siswa
+		node.Guru += place.Guru
+		if raw != "" && !containsString(node.Variants, raw) {
+			node.Variants = append(node.Variants, raw)
+		}
+	}
+
+	for i := range nodes {
+		sort.Strings(nodes[i].Variants)
+	}
+	sortDrillDownNodes(nodes)
+	return nodes
+}
+
+// sortDrillDownNodes orders nodes by count, largest first, then by name
+func sortDrillDownNodes(nodes []models.DrillDownNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Count != nodes[j].Count {
+			return nodes[i].Count > nodes[j].Count
+		}
+		return nodes[i].Nama < nodes[j].Nama
+	})
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"satpen-api/internal/models"
+)
+
+func TestMergePlaces(t *testing.T) {
+	kecamatan := func(p models.PlaceCount) string { return p.Kecamatan }
+	tests := []struct {
+		name   string
+		places []models.PlaceCount
+		want   []models.DrillDownNode
+	}{
+		{
+			name: "spelling variants share one node",
+			places: []models.PlaceCount{
+				{Kecamatan: "Kec. Sukamaju", Count: 2, Siswa: 300, Guru: 20},
+				{Kecamatan: "KECAMATAN SUKAMAJU", Count: 1, Siswa: 120, Guru: 8},
+				{Kecamatan: " Sukamaju ", Count: 3, Siswa: 410, Guru: 31},
+			},
+			want: []models.DrillDownNode{
+				{Key: "sukamaju", Nama: "Sukamaju", Variants: []string{"KECAMATAN SUKAMAJU", "Kec. Sukamaju", "Sukamaju"}, Count: 6, Siswa: 830, Guru: 59},
+			},
+		},
+		{
+			name: "blank names are unknown, ordered by count then name",
+			places: []models.PlaceCount{
+				{Kecamatan: "Lawang", Count: 2, Siswa: 50, Guru: 4},
+				{Kecamatan: "", Count: 1},
+				{Kecamatan: " - ", Count: 1, Siswa: 10, Guru: 1},
+				{Kecamatan: "kec. suka-maju", Count: 2, Siswa: 70, Guru: 6},
+				{Kecamatan: "Wonokromo", Count: 5, Siswa: 900, Guru: 60},
+			},
+			want: []models.DrillDownNode{
+				{Key: "wonokromo", Nama: "Wonokromo", Variants: []string{"Wonokromo"}, Count: 5, Siswa: 900, Guru: 60},
+				{Key: "lawang", Nama: "Lawang", Variants: []string{"Lawang"}, Count: 2, Siswa: 50, Guru: 4},
+				{Key: "suka maju", Nama: "Suka Maju", Variants: []string{"kec. suka-maju"}, Count: 2, Siswa: 70, Guru: 6},
+				{Nama: unknownPlace, Variants: []string{"-"}, Count: 2, Siswa: 10, Guru: 1},
+			},
+		},
+		{
+			name:   "no places",
+			places: nil,
+			want:   []models.DrillDownNode{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergePlaces(tt.places, kecamatan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergePlaces =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePlacesKelurahan(t *testing.T) {
+	places := []models.PlaceCount{
+		{Kecamatan: "Singosari", Kelurahan: "Desa Pagentan", Count: 1},
+		{Kecamatan: "Singosari", Kelurahan: "Kel. Pagentan", Count: 2},
+	}
+	got := mergePlaces(places, func(p models.PlaceCount) string { return p.Kelurahan })
+	if len(got) != 1 || got[0].Key != "pagentan" || got[0].Nama != "Pagentan" || got[0].Count != 3 {
+		t.Errorf("mergePlaces by kelurahan = %+v, want one Pagentan node of 3", got)
+	}
+}
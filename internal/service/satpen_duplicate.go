@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"satpen-api/internal/duplicate"
+	"satpen-api/internal/models"
+)
+
+// Defaults used when duplicates.min_score / duplicates.max_pairs are not set
+const (
+	defaultDuplicateMinScore = 0.8
+	defaultDuplicateMaxPairs = 500
+)
+
+// ErrMinScore is returned for a min_score outside 0-1
+var ErrMinScore = errors.New("min_score must be between 0 and 1")
+
+// DuplicateReport is the outcome of FindDuplicates
+type DuplicateReport struct {
+	MinScore float64          `json:"min_score"`
+	Checked  int              `json:"checked"`
+	Total    int              `json:"total"` // pairs found, before limit
+	Pairs    []duplicate.Pair `json:"pairs"`
+}
+
+// FindDuplicates compares the satpen matching filter within each kabupaten
+// and returns the candidate duplicate pairs scoring at least minScore
+// (duplicates.min_score when 0), up to limit (duplicates.max_pairs when 0 or
+// above it). Without a status filter satpen of every status are compared, as
+// a pending registration may duplicate an approved one.
+func (s *satpenService) FindDuplicates(filter *models.SatpenFilter, minScore float64, limit int) (*DuplicateReport, error) {
+	if minScore < 0 || minScore > 1 {
+		return nil, ErrMinScore
+	}
+	if minScore == 0 {
+		minScore = s.cfg.Duplicates.MinScore
+		if minScore == 0 {
+			minScore = defaultDuplicateMinScore
+		}
+	}
+	maxPairs := s.cfg.Duplicates.MaxPairs
+	if maxPairs < 1 {
+		maxPairs = defaultDuplicateMaxPairs
+	}
+	if limit < 1 || limit > maxPairs {
+		limit = maxPairs
+	}
+
+	if len(filter.Status) == 0 {
+		filter.Status = models.SatpenStatuses
+	}
+	records, err := s.repo.FindDuplicateRecords(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := duplicate.Detect(records, minScore)
+	report := &DuplicateReport{MinScore: minScore, Checked: len(records), Total: len(pairs), Pairs: pairs}
+	if len(pairs) > limit {
+		report.Pairs = pairs[:limit]
+	}
+	return report, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"encoding/json"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+	"sort"
+	"strings"
+)
+
+// FieldSetError lists the unknown names per query parameter (fields, include)
+type FieldSetError struct {
+	Invalid map[string][]string
+}
+
+func (e *FieldSetError) Error() string {
+	parts := make([]string, 0, len(e.Invalid))
+	for param, names := range e.Invalid {
+		parts = append(parts, param+": "+strings.Join(names, ", "))
+	}
+	sort.Strings(parts)
+	return "invalid field names (" + strings.Join(parts, "; ") + ")"
+}
+
+// SatpenFieldSet is a parsed fields=/include= pair. The zero value returns
+// every field and relation.
+type SatpenFieldSet struct {
+	fields   []string // requested JSON fields, empty for all
+	includes []string // relation JSON names to return
+	// Projection drives the SELECT column list and the preloads
+	Projection *repository.Projection
+}
+
+// ParseSatpenFieldSet validates the comma separated fields and include query
+// values against the JSON fields of models.Satpen. includeSet tells an absent
+// include (default relations) from an empty one (no relations).
+//
+// Without include, all relations are returned when fields is empty. Relations
+// named in fields are always returned.
+func ParseSatpenFieldSet(fields, include string, includeSet bool) (*SatpenFieldSet, error) {
+	allowed := models.SatpenFields()
+	invalid := make(map[string][]string)
+
+	fs := &SatpenFieldSet{}
+	for _, name := range splitList(fields) {
+		if _, ok := allowed[name]; !ok {
+			invalid["fields"] = append(invalid["fields"], name)
+			continue
+		}
+		fs.fields = append(fs.fields, name)
+	}
+	for _, name := range splitList(include) {
+		if field, ok := allowed[name]; !ok || field.Relation == "" {
+			invalid["include"] = append(invalid["include"], name)
+			continue
+		}
+		fs.includes = append(fs.includes, name)
+	}
+	if len(invalid) > 0 {
+		return nil, &FieldSetError{Invalid: invalid}
+	}
+
+	if len(fs.fields) == 0 && !includeSet {
+		// Everything, as before fields/include existed
+		return fs, nil
+	}
+
+	for _, name := range fs.fields {
+		if allowed[name].Relation != "" {
+			fs.includes = append(fs.includes, name)
+		}
+	}
+
+	proj := &repository.Projection{Preloads: []string{}}
+	seenColumn := make(map[string]bool)
+	seenPreload := make(map[string]bool)
+	addPreload := func(relation string) {
+		if !seenPreload[relation] {
+			seenPreload[relation] = true
+			proj.Preloads = append(proj.Preloads, relation)
+		}
+	}
+	addColumn := func(column string) {
+		if column != "" && !seenColumn[column] {
+			seenColumn[column] = true
+			proj.Columns = append(proj.Columns, column)
+		}
+	}
+
+	for _, name := range fs.includes {
+		field := allowed[name]
+		addPreload(field.Relation)
+		for _, column := range field.Columns {
+			addColumn(column)
+		}
+	}
+
+	// Computed fields need their source columns and relations even when those
+	// are not returned themselves
+	computed := fs.fields
+	if len(computed) == 0 {
+		computed = make([]string, 0, len(allowed))
+		for name := range allowed {
+			computed = append(computed, name)
+		}
+		sort.Strings(computed)
+	}
+	for _, name := range computed {
+		field := allowed[name]
+		for _, relation := range field.Preloads {
+			addPreload(relation)
+		}
+		if len(fs.fields) == 0 {
+			continue
+		}
+		addColumn(field.Column)
+		for _, column := range field.Columns {
+			addColumn(column)
+		}
+	}
+	if len(fs.fields) == 0 {
+		// All columns
+		proj.Columns = nil
+	}
+
+	fs.Projection = proj
+	return fs, nil
+}
+
+// projection is nil-safe so callers may pass a nil field set
+func (fs *SatpenFieldSet) projection() *repository.Projection {
+	if fs == nil {
+		return nil
+	}
+	return fs.Projection
+}
+
+// wants reports whether the JSON field name is returned; nil-safe
+func (fs *SatpenFieldSet) wants(name string) bool {
+	if fs == nil || len(fs.fields) == 0 {
+		return true
+	}
+	for _, field := range fs.fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Shape drops the fields and relations that were not requested from a
+// *models.Satpen or []models.Satpen. It returns v unchanged for the full set.
+func (fs *SatpenFieldSet) Shape(v interface{}) (interface{}, error) {
+	if fs == nil || fs.Projection == nil {
+		return v, nil
+	}
+
+	keep := make(map[string]bool)
+	keep["id"] = true
+	if len(fs.fields) > 0 {
+		for _, name := range fs.fields {
+			keep[name] = true
+		}
+	} else {
+		for name, field := range models.SatpenFields() {
+			if field.Relation == "" {
+				keep[name] = true
+			}
+		}
+	}
+	for _, name := range fs.includes {
+		keep[name] = true
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.(type) {
+	case []models.Satpen:
+		rows := []map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			pick(row, keep)
+		}
+		return rows, nil
+	default:
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(data, &row); err != nil {
+			return nil, err
+		}
+		pick(row, keep)
+		return row, nil
+	}
+}
+
+func pick(row map[string]json.RawMessage, keep map[string]bool) {
+	for key := range row {
+		if !keep[key] {
+			delete(row, key)
+		}
+	}
+}
+
+// splitList splits a comma separated query value, trimming blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"satpen-api/internal/geojson"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+	"time"
+)
+
+// ErrRegionLevel is returned for an unknown aggregation level
+var ErrRegionLevel = repository.ErrRegionLevel
+
+// defaultGeoFields are the feature properties when fields is not given
+const defaultGeoFields = "id,npsn,nama,jenjang,kabupaten,akreditasi,status"
+
+// ParseSatpenGeoFieldSet is ParseSatpenFieldSet for GeoJSON output: fields
+// defaults to a compact property set and coordinates are always loaded for
+// the geometry
+func ParseSatpenGeoFieldSet(fields, include string, includeSet bool) (*SatpenFieldSet, error) {
+	if fields == "" {
+		fields = defaultGeoFields
+	}
+	return ParseSatpenFieldSet(fields+",coordinates", include, includeSet)
+}
+
+// SatpenFeatureCollection converts satpen to Point features. Properties are the
+// fields of fieldSet except coordinates, with relations reduced to their nama.
+func SatpenFeatureCollection(satpen []models.Satpen, fieldSet *SatpenFieldSet) (*geojson.FeatureCollection, error) {
+	shaped, err := fieldSet.Shape(satpen)
+	if err != nil {
+		return nil, err
+	}
+	rows, ok := shaped.([]map[string]json.RawMessage)
+	if !ok {
+		return nil, errors.New("geojson output needs a field set")
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for i, row := range rows {
+		var geometry *geojson.Point
+		if c := satpen[i].Coordinates; c != nil {
+			geometry = geojson.NewPoint(c.Latitude, c.Longitude)
+		}
+
+		properties := make(map[string]interface{}, len(row))
+		for key, raw := range row {
+			if key == "id" || key == "coordinates" {
+				continue
+			}
+			var value interface{}
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, err
+			}
+			if relation, ok := value.(map[string]interface{}); ok {
+				if nama, ok := relation["nama"]; ok {
+					value = nama
+				}
+			}
+			properties[key] = value
+		}
+		fc.Add(satpen[i].IDSatpen, geometry, properties)
+	}
+	return fc, nil
+}
+
+// GetSatpenRegions returns one feature per provinsi or kabupaten with the
+// satpen count matching filter, placed at the mean location of its satpen
+func (s *satpenService) GetSatpenRegions(level string, filter *models.SatpenFilter) (*geojson.FeatureCollection, error) {
+	s.applySearchDefaults(filter)
+
+	regions, err := s.repo.CountByRegion(level, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := geojson.NewFeatureCollection()
+	for _, region := range regions {
+		var geometry *geojson.Point
+		if region.Latitude != nil && region.Longitude != nil {
+			geometry = geojson.NewPoint(*region.Latitude, *region.Longitude)
+		}
+		properties := map[string]interface{}{
+			"nama":    region.Nama,
+			"level":   level,
+			"count":   region.Count,
+			"located": region.Located,
+			"siswa":   region.Siswa,
+			"guru":    region.Guru,
+		}
+		if level == repository.RegionProvinsi {
+			properties["kode"] = region.Kode
+			properties["map"] = region.Map
+		} else {
+			properties["id_prov"] = region.IDProv
+		}
+		fc.Add(region.ID, geometry, properties)
+	}
+	fc.Meta = map[string]interface{}{"level": level, "regions": len(regions)}
+	return fc, nil
+}
+
+// ExportSatpenGeoJSON returns every located satpen matching filter as a
+// FeatureCollection, with a download file name
+func (s *satpenService) ExportSatpenGeoJSON(filter *models.SatpenFilter, sort string, fieldSet *SatpenFieldSet) (*geojson.FeatureCollection, string, error) {
+	s.applySearchDefaults(filter)
+
+	satpen, err := s.repo.FindAllForExport(filter, sort, fieldSet.projection())
+	if err != nil {
+		return nil, "", err
+	}
+
+	fc, err := SatpenFeatureCollection(satpen, fieldSet)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("data-satpen-%s.geojson", time.Now().Format("20060102-150405"))
+	return fc, filename, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+	"sort"
+)
+
+var (
+	// ErrIndikator is returned for an indicator name other than models.Indikators
+	ErrIndikator = errors.New("indikator must be rasio_siswa_guru, persen_guru_perempuan or tendik_per_100_siswa")
+	// ErrIndikatorGroup is returned for a group_by other than provinsi, kabupaten or jenjang
+	ErrIndikatorGroup = repository.ErrIndikatorGroup
+)
+
+// Flag values of an indicator outside its thresholds
+const (
+	flagBelow = "below"
+	flagAbove = "above"
+)
+
+// indikatorFlag returns below or above when value is outside the configured
+// thresholds of the indicator called name, empty otherwise
+func (s *satpenService) indikatorFlag(name string, value float64) string {
+	threshold := s.cfg.Indicators.Thresholds[name]
+	switch {
+	case threshold.Min != nil && value < *threshold.Min:
+		return flagBelow
+	case threshold.Max != nil && value > *threshold.Max:
+		return flagAbove
+	}
+	return ""
+}
+
+// flagIndikator fills ind.Flags with the indicators outside their thresholds
+func (s *satpenService) flagIndikator(ind *models.Indikator) {
+	if ind == nil {
+		return
+	}
+	ind.Flags = nil
+	for _, name := range models.Indikators {
+		if v := ind.Value(name); v != nil {
+			if flag := s.indikatorFlag(name, *v); flag != "" {
+				ind.Flags = append(ind.Flags, name+"_"+flag)
+			}
+		}
+	}
+}
+
+// GetIndikatorRanking ranks the provinsi, kabupaten or jenjang of the satpen
+// matching filter by the median of indikator, highest first unless asc. With
+// withFlagged the satpen outside the thresholds are listed too, furthest out
+// first, up to pagination.max_limit.
+func (s *satpenService) GetIndikatorRanking(indikator, groupBy string, asc, withFlagged bool, filter *models.SatpenFilter) (*models.IndikatorRanking, error) {
+	if !models.IsValidIndikator(indikator) {
+		return nil, ErrIndikator
+	}
+
+	rows, err := s.repo.FindIndikatorRows(groupBy, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := s.cfg.Indicators.Thresholds[indikator]
+	ranking := &models.IndikatorRanking{
+		Indikator: indikator,
+		GroupBy:   groupBy,
+		Min:       threshold.Min,
+		Max:       threshold.Max,
+		Groups:    []models.IndikatorGroup{},
+	}
+
+	var all []float64
+	values := make(map[uint][]float64)
+	names := make(map[uint]string)
+	var order []uint
+	type flagged struct {
+		models.IndikatorFlagged
+		distance float64
+	}
+	var flags []flagged
+	for _, row := range rows {
+		v := models.NewIndikator(row.JmlPD, row.JmlGuru, row.GuruPR, row.JmlTendik).Value(indikator)
+		if v == nil {
+			continue
+		}
+		if _, ok := values[row.GroupID]; !ok {
+			order = append(order, row.GroupID)
+			names[row.GroupID] = row.GroupName
+		}
+		values[row.GroupID] = append(values[row.GroupID], *v)
+		all = append(all, *v)
+
+		if !withFlagged {
+			continue
+		}
+		switch s.indikatorFlag(indikator, *v) {
+		case flagBelow:
+			flags = append(flags, flagged{models.IndikatorFlagged{ID: row.IDSatpen, NPSN: row.NPSN, Nama: row.Nama, Group: row.GroupName, Value: *v, Flag: flagBelow}, *threshold.Min - *v})
+		case flagAbove:
+			flags = append(flags, flagged{models.IndikatorFlagged{ID: row.IDSatpen, NPSN: row.NPSN, Nama: row.Nama, Group: row.GroupName, Value: *v, Flag: flagAbove}, *v - *threshold.Max})
+		}
+	}
+
+	ranking.Overall = s.indikatorStats(indikator, all)
+	for _, id := range order {
+		ranking.Groups = append(ranking.Groups, models.IndikatorGroup{
+			ID:             id,
+			Nama:           names[id],
+			IndikatorStats: s.indikatorStats(indikator, values[id]),
+		})
+	}
+	sort.SliceStable(ranking.Groups, func(i, j int) bool {
+		a, b := *ranking.Groups[i].Median, *ranking.Groups[j].Median
+		if a == b {
+			return ranking.Groups[i].Nama < ranking.Groups[j].Nama
+		}
+		if asc {
+			return a < b
+		}
+		return a > b
+	})
+	for i := range ranking.Groups {
+		ranking.Groups[i].Rank = i + 1
+	}
+
+	if withFlagged {
+		sort.SliceStable(flags, func(i, j int) bool { return flags[i].distance > flags[j].distance })
+		if limit := s.cfg.Pagination.MaxLimit; limit > 0 && len(flags) > limit {
+			flags = flags[:limit]
+		}
+		ranking.Flagged = make([]models.IndikatorFlagged, len(flags))
+		for i, f := range flags {
+			ranking.Flagged[i] = f.IndikatorFlagged
+		}
+	}
+
+	return ranking, nil
+}
+
+// indikatorStats summarises values of the indicator called name. values is
+// sorted in place.
+func (s *satpenService) indikatorStats(name string, values []float64) models.IndikatorStats {
+	stats := models.IndikatorStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+		switch s.indikatorFlag(name, v) {
+		case flagBelow:
+			stats.BelowMin++
+		case flagAbove:
+			stats.AboveMax++
+		}
+	}
+
+	stats.Mean = roundedPtr(sum / float64(len(values)))
+	stats.Min = roundedPtr(values[0])
+	stats.P25 = roundedPtr(percentile(values, 25))
+	stats.Median = roundedPtr(percentile(values, 50))
+	stats.P75 = roundedPtr(percentile(values, 75))
+	stats.P90 = roundedPtr(percentile(values, 90))
+	stats.Max = roundedPtr(values[len(values)-1])
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted, interpolating linearly
+// between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	pos := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+func roundedPtr(v float64) *float64 {
+	v = models.Round2(v)
+	return &v
+}
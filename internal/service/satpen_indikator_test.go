@@ -0,0 +1,216 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"satpen-api/internal/config"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+)
+
+// fakeIndikatorRepository serves fixed FindIndikatorRows results; every other
+// method panics on the nil embedded interface
+type fakeIndikatorRepository struct {
+	repository.SatpenRepository
+	rows []models.IndikatorRow
+}
+
+func (r *fakeIndikatorRepository) FindIndikatorRows(groupBy string, filter *models.SatpenFilter) ([]models.IndikatorRow, error) {
+	return r.rows, nil
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+// newIndikatorTestService flags rasio_siswa_guru outside 10..30
+func newIndikatorTestService(rows []models.IndikatorRow, maxLimit int) *satpenService {
+	cfg := &config.Config{}
+	cfg.Pagination.MaxLimit = maxLimit
+	cfg.Indicators.Thresholds = map[string]config.IndicatorThreshold{
+		models.IndikatorRasioSiswaGuru: {Min: floatPtr(10), Max: floatPtr(30)},
+	}
+	return &satpenService{repo: &fakeIndikatorRepository{rows: rows}, cfg: cfg}
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{[]float64{1, 2, 3, 4}, 0, 1},
+		{[]float64{1, 2, 3, 4}, 25, 1.75},
+		{[]float64{1, 2, 3, 4}, 50, 2.5},
+		{[]float64{1, 2, 3, 4}, 75, 3.25},
+		{[]float64{1, 2, 3, 4}, 90, 3.7},
+		{[]float64{1, 2, 3, 4}, 100, 4},
+		{[]float64{1, 2, 3}, 50, 2},
+		{[]float64{10, 20}, 90, 19},
+		{[]float64{5}, 75, 5},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.sorted, tt.p); !nearly(got, tt.want) {
+			t.Errorf("percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestIndikatorStats(t *testing.T) {
+	svc := newIndikatorTestService(nil, 0)
+	tests := []struct {
+		name      string
+		indikator string
+		values    []float64
+		want      models.IndikatorStats
+	}{
+		{
+			name:      "unsorted, outside both thresholds",
+			indikator: models.IndikatorRasioSiswaGuru,
+			values:    []float64{35, 5, 28, 40, 12, 20},
+			want: models.IndikatorStats{
+				Count: 6, Mean: floatPtr(23.33), Min: floatPtr(5), P25: floatPtr(14), Median: floatPtr(24),
+				P75: floatPtr(33.25), P90: floatPtr(37.5), Max: floatPtr(40), BelowMin: 1, AboveMax: 2,
+			},
+		},
+		{
+			name:      "bounds are inclusive",
+			indikator: models.IndikatorRasioSiswaGuru,
+			values:    []float64{30, 10},
+			want: models.IndikatorStats{
+				Count: 2, Mean: floatPtr(20), Min: floatPtr(10), P25: floatPtr(15), Median: floatPtr(20),
+				P75: floatPtr(25), P90: floatPtr(28), Max: floatPtr(30),
+			},
+		},
+		{
+			name:      "no thresholds configured",
+			indikator: models.IndikatorTendikPer100Siswa,
+			values:    []float64{0.5, 50},
+			want: models.IndikatorStats{
+				Count: 2, Mean: floatPtr(25.25), Min: floatPtr(0.5), P25: floatPtr(12.88), Median: floatPtr(25.25),
+				P75: floatPtr(37.63), P90: floatPtr(45.05), Max: floatPtr(50),
+			},
+		},
+		{
+			name:      "empty",
+			indikator: models.IndikatorRasioSiswaGuru,
+			values:    nil,
+			want:      models.IndikatorStats{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.indikatorStats(tt.indikator, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("indikatorStats =\n%s\nwant\n%s", statsString(got), statsString(tt.want))
+			}
+		})
+	}
+}
+
+// indikatorRows have rasio_siswa_guru 20 and 40 in Jawa Timur, 5 and 55 in
+// Jawa Tengah (both medians 30) and 25 in Bali. The row without guru has no
+// ratio and is left out.
+var indikatorRows = []models.IndikatorRow{
+	{IDSatpen: 1, NPSN: "1", Nama: "MI 1", GroupID: 1, GroupName: "Jawa Timur", JmlPD: 200, JmlGuru: 10},
+	{IDSatpen: 2, NPSN: "2", Nama: "MTs 2", GroupID: 1, GroupName: "Jawa Timur", JmlPD: 400, JmlGuru: 10},
+	{IDSatpen: 3, NPSN: "3", Nama: "MI 3", GroupID: 2, GroupName: "Jawa Tengah", JmlPD: 50, JmlGuru: 10},
+	{IDSatpen: 4, NPSN: "4", Nama: "MA 4", GroupID: 2, GroupName: "Jawa Tengah", JmlPD: 550, JmlGuru: 10},
+	{IDSatpen: 5, NPSN: "5", Nama: "SMK 5", GroupID: 3, GroupName: "Bali", JmlPD: 250, JmlGuru: 10},
+	{IDSatpen: 6, NPSN: "6", Nama: "MI 6", GroupID: 3, GroupName: "Bali", JmlPD: 80},
+}
+
+func TestGetIndikatorRankingOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		asc  bool
+		want []string
+	}{
+		{"highest first, ties by name", false, []string{"Jawa Tengah", "Jawa Timur", "Bali"}},
+		{"ascending, ties by name", true, []string{"Bali", "Jawa Tengah", "Jawa Timur"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newIndikatorTestService(indikatorRows, 100)
+			ranking, err := svc.GetIndikatorRanking(models.IndikatorRasioSiswaGuru, repository.RegionProvinsi, tt.asc, false, &models.SatpenFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for i, group := range ranking.Groups {
+				names = append(names, group.Nama)
+				if group.Rank != i+1 {
+					t.Errorf("%s: rank %d, want %d", group.Nama, group.Rank, i+1)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("groups = %v, want %v", names, tt.want)
+			}
+
+			overall := ranking.Overall
+			if overall.Count != 5 || *overall.Median != 25 || overall.BelowMin != 1 || overall.AboveMax != 2 {
+				t.Errorf("overall = %s", statsString(overall))
+			}
+			if *ranking.Min != 10 || *ranking.Max != 30 || ranking.Flagged != nil {
+				t.Errorf("thresholds %v..%v, flagged %v", *ranking.Min, *ranking.Max, ranking.Flagged)
+			}
+		})
+	}
+}
+
+func TestGetIndikatorRankingFlagged(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxLimit int
+		want     []uint
+	}{
+		// Furthest out first: 55 (25 above), 40 (10 above), 5 (5 below)
+		{"all", 0, []uint{4, 2, 3}},
+		{"truncated at max_limit", 2, []uint{4, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newIndikatorTestService(indikatorRows, tt.maxLimit)
+			ranking, err := svc.GetIndikatorRanking(models.IndikatorRasioSiswaGuru, repository.RegionProvinsi, false, true, &models.SatpenFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []uint
+			for _, f := range ranking.Flagged {
+				ids = append(ids, f.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Fatalf("flagged = %v, want %v", ids, tt.want)
+			}
+			want := models.IndikatorFlagged{ID: 4, NPSN: "4", Nama: "MA 4", Group: "Jawa Tengah", Value: 55, Flag: flagAbove}
+			if ranking.Flagged[0] != want {
+				t.Errorf("flagged[0] = %+v, want %+v", ranking.Flagged[0], want)
+			}
+		})
+	}
+}
+
+func TestGetIndikatorRankingInvalid(t *testing.T) {
+	svc := newIndikatorTestService(indikatorRows, 100)
+	if _, err := svc.GetIndikatorRanking("rasio", repository.RegionProvinsi, false, false, &models.SatpenFilter{}); err != ErrIndikator {
+		t.Errorf("err = %v, want ErrIndikator", err)
+	}
+}
+
+func nearly(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
+
+// statsString prints stats with its pointers dereferenced
+func statsString(s models.IndikatorStats) string {
+	values := []*float64{s.Mean, s.Min, s.P25, s.Median, s.P75, s.P90, s.Max}
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		if v != nil {
+			out[i] = *v
+		}
+	}
+	return fmt.Sprintf("count %d, mean/min/p25/median/p75/p90/max %v, below %d, above %d", s.Count, out, s.BelowMin, s.AboveMax)
+}
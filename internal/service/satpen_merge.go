@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"satpen-api/internal/audit"
+	"satpen-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrMergeSame is returned when the surviving and losing satpen are the same
+var ErrMergeSame = errors.New("survivor_id and loser_id must differ")
+
+// MergeSatpen consolidates the duplicate loserID into survivorID: its PDPTK,
+// PTK and registration timeline move to the survivor and the loser is
+// deleted, recorded in the audit log. With preview it only reports what would
+// move.
+func (s *satpenService) MergeSatpen(ctx context.Context, survivorID, loserID uint, preview bool) (*models.MergeResult, error) {
+	if survivorID == loserID {
+		return nil, ErrMergeSame
+	}
+
+	entry := audit.NewEntry(ctx, models.AuditActionMerge, "satpen", survivorID)
+	result, err := s.repo.MergeSatpen(survivorID, loserID, preview, entry)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("satuan pendidikan not found")
+		}
+		return nil, err
+	}
+	return result, nil
+}
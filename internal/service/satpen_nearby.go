@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"satpen-api/internal/audit"
+	"satpen-api/internal/geo"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRadius is returned for a radius_km outside (0, nearby.max_radius_km]
+	ErrRadius = errors.New("radius_km out of range")
+	// ErrPartialLocation is returned when only one coordinate is given
+	ErrPartialLocation = errors.New("latitude and longitude must be given together")
+)
+
+// GetNearbySatpen returns satpen within radiusKm of (lat, lng), nearest first.
+// A zero radius or limit falls back to config; the radius used is returned.
+func (s *satpenService) GetNearbySatpen(lat, lng, radiusKm float64, limit int, filter *models.SatpenFilter, fieldSet *SatpenFieldSet) ([]models.Satpen, float64, error) {
+	if err := geo.Validate(lat, lng); err != nil {
+		return nil, 0, err
+	}
+
+	if radiusKm == 0 {
+		radiusKm = s.cfg.Nearby.DefaultRadiusKm
+	}
+	// Written so that NaN fails as well
+	if !(radiusKm > 0) || math.IsInf(radiusKm, 0) || (s.cfg.Nearby.MaxRadiusKm > 0 && radiusKm > s.cfg.Nearby.MaxRadiusKm) {
+		return nil, 0, fmt.Errorf("%w: must be greater than 0 and at most %g", ErrRadius, s.cfg.Nearby.MaxRadiusKm)
+	}
+
+	if limit < 1 {
+		limit = s.cfg.Pagination.DefaultLimit
+	}
+	if limit > s.cfg.Pagination.MaxLimit {
+		limit = s.cfg.Pagination.MaxLimit
+	}
+
+	s.applySearchDefaults(filter)
+
+	satpen, err := s.repo.FindNearby(lat, lng, radiusKm, limit, filter, fieldSet.projection())
+	if err != nil {
+		return nil, 0, err
+	}
+	return satpen, radiusKm, nil
+}
+
+// UpdateSatpenLocation sets the coordinates of a satpen. Both must be given to
+// set a location; both nil clears it.
+func (s *satpenService) UpdateSatpenLocation(ctx context.Context, id uint, lat, lng *float64) (*models.Satpen, error) {
+	if (lat == nil) != (lng == nil) {
+		return nil, ErrPartialLocation
+	}
+	if lat != nil {
+		if err := geo.Validate(*lat, *lng); err != nil {
+			return nil, err
+		}
+	}
+
+	idOnly := &repository.Projection{Columns: []string{"id_satpen"}, Preloads: []string{}}
+	if _, err := s.repo.FindByID(id, idOnly); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("satuan pendidikan not found")
+		}
+		return nil, err
+	}
+
+	entry := audit.NewEntry(ctx, models.AuditActionUpdateLocation, "satpen", id)
+	if err := s.repo.UpdateLocation(id, lat, lng, entry); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(id, nil)
+}
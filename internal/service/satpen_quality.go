@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"satpen-api/internal/models"
+	"satpen-api/internal/quality"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GetDataQualityIssues runs the data quality rules over the satpen matching
+// filter and returns those failing any of rules (every rule when empty),
+// lowest score first, with a summary over all checked satpen
+func (s *satpenService) GetDataQualityIssues(filter *models.SatpenFilter, rules []string, page, limit int) ([]models.DataQualityItem, *PaginationMeta, *models.DataQualitySummary, error) {
+	if page < 1 {
+		page = s.cfg.Pagination.DefaultPage
+	}
+	if limit < 1 {
+		limit = s.cfg.Pagination.DefaultLimit
+	}
+	if limit > s.cfg.Pagination.MaxLimit {
+		limit = s.cfg.Pagination.MaxLimit
+	}
+
+	tapel, err := s.currentTapel()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The rules run in SQL to count and page; the page is checked again in
+	// Go for the issue messages
+	now := time.Now()
+	summary, err := s.repo.CountQualityIssues(filter, tapel, now, rules)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	records, err := s.repo.FindQualityIssues(filter, tapel, now, rules, page, limit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	selected := make(map[string]bool)
+	for _, rule := range rules {
+		selected[rule] = true
+	}
+
+	items := make([]models.DataQualityItem, 0, len(records))
+	for i := range records {
+		result := quality.Check(&records[i], now)
+
+		var matched []models.DataQualityIssue
+		for _, issue := range result.Issues {
+			if len(selected) == 0 || selected[issue.Rule] {
+				matched = append(matched, issue)
+			}
+		}
+
+		satpen := records[i].Satpen
+		item := models.DataQualityItem{
+			ID:          satpen.IDSatpen,
+			NPSN:        satpen.NPSN,
+			Nama:        satpen.NmSatpen,
+			DataQuality: models.DataQuality{Score: result.Score, Issues: matched},
+		}
+		if satpen.Provinsi != nil {
+			item.Provinsi = satpen.Provinsi.NmProv
+		}
+		if satpen.Kabupaten != nil {
+			item.Kabupaten = satpen.Kabupaten.NamaKab
+		}
+		items = append(items, item)
+	}
+
+	total := int64(summary.WithIssues)
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &PaginationMeta{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalItems:   total,
+		ItemsPerPage: limit,
+		HasNext:      page < totalPages,
+		HasPrev:      page > 1,
+	}
+
+	return items, pagination, summary, nil
+}
+
+// checkDataQuality fills satpen.DataQuality from the data quality rules
+func (s *satpenService) checkDataQuality(satpen *models.Satpen) error {
+	tapel, err := s.currentTapel()
+	if err != nil {
+		return err
+	}
+	record, err := s.repo.FindQualityRecord(satpen.IDSatpen, tapel)
+	if err != nil {
+		return err
+	}
+
+	result := quality.Check(record, time.Now())
+	satpen.DataQuality = &result
+	return nil
+}
+
+// currentTapel is dapodik.tapel or else the latest tahun pelajaran. It is
+// empty when neither is known, which skips the pdptk_missing rule.
+func (s *satpenService) currentTapel() (string, error) {
+	if s.cfg.Dapodik.Tapel != "" {
+		return s.cfg.Dapodik.Tapel, nil
+	}
+	tapel, err := s.repo.GetCurrentTapel()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current tapel: %w", err)
+	}
+	return tapel, nil
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"satpen-api/internal/config"
+	"satpen-api/internal/geojson"
 	"satpen-api/internal/models"
 	"satpen-api/internal/repository"
 	"strconv"
@@ -15,12 +17,26 @@ import (
 )
 
 type SatpenService interface {
-	GetAllSatpen(filters map[string]interface{}, page, limit int, sort string, includeStats bool) ([]models.Satpen, *PaginationMeta, *models.SatpenStatistics, error)
-	GetSatpenByID(id string) (*models.Satpen, error)
-	GetStatistics(filters map[string]interface{}) (*models.SatpenStatistics, error)
-	ExportSatpen(filters map[string]interface{}, sort string) (*bytes.Buffer, string, error)
+	GetAllSatpen(filter *models.SatpenFilter, page, limit int, sort string, includeStats bool, fieldSet *SatpenFieldSet) ([]models.Satpen, *PaginationMeta, *models.SatpenStatistics, error)
+	GetAllSatpenCursor(filter *models.SatpenFilter, cursor string, limit int, sort string, withCount, includeStats bool, fieldSet *SatpenFieldSet) ([]models.Satpen, *CursorPaginationMeta, *models.SatpenStatistics, error)
+	GetSatpenByID(id string, fieldSet *SatpenFieldSet) (*models.Satpen, error)
+	GetNearbySatpen(lat, lng, radiusKm float64, limit int, filter *models.SatpenFilter, fieldSet *SatpenFieldSet) ([]models.Satpen, float64, error)
+	UpdateSatpenLocation(ctx context.Context, id uint, lat, lng *float64) (*models.Satpen, error)
+	GetStatistics(filter *models.SatpenFilter, topN int) (*models.SatpenStatistics, error)
+	ExportSatpen(filter *models.SatpenFilter, sort string) (*bytes.Buffer, string, error)
+	ExportSatpenGeoJSON(filter *models.SatpenFilter, sort string, fieldSet *SatpenFieldSet) (*geojson.FeatureCollection, string, error)
+	GetSatpenRegions(level string, filter *models.SatpenFilter) (*geojson.FeatureCollection, error)
+	GetDrillDown(path DrillDownPath, filter *models.SatpenFilter) (*models.DrillDown, error)
+	GetTrend(interval string, from, to *time.Time, filter *models.SatpenFilter) (*models.Trend, error)
+	GetIndikatorRanking(indikator, groupBy string, asc, withFlagged bool, filter *models.SatpenFilter) (*models.IndikatorRanking, error)
+	GetDataQualityIssues(filter *models.SatpenFilter, rules []string, page, limit int) ([]models.DataQualityItem, *PaginationMeta, *models.DataQualitySummary, error)
+	FindDuplicates(filter *models.SatpenFilter, minScore float64, limit int) (*DuplicateReport, error)
+	MergeSatpen(ctx context.Context, survivorID, loserID uint, preview bool) (*models.MergeResult, error)
 }
 
+// defaultStatisticsTopN is used when statistics.top_n is not set
+const defaultStatisticsTopN = 5
+
 type satpenService struct {
 	repo repository.SatpenRepository
 	cfg  *config.Config
@@ -35,6 +51,16 @@ type PaginationMeta struct {
 	HasPrev      bool  `json:"has_prev"`
 }
 
+// CursorPaginationMeta is returned instead of PaginationMeta for cursor pagination
+type CursorPaginationMeta struct {
+	ItemsPerPage int    `json:"items_per_page"`
+	TotalItems   *int64 `json:"total_items,omitempty"`
+	HasNext      bool   `json:"has_next"`
+	HasPrev      bool   `json:"has_prev"`
+	NextCursor   string `json:"next_cursor,omitempty"`
+	PrevCursor   string `json:"prev_cursor,omitempty"`
+}
+
 func NewSatpenService(repo repository.SatpenRepository, cfg *config.Config) SatpenService {
 	return &satpenService{
 		repo: repo,
@@ -42,7 +68,7 @@ func NewSatpenService(repo repository.SatpenRepository, cfg *config.Config) Satp
 	}
 }
 
-func (s *satpenService) GetAllSatpen(filters map[string]interface{}, page, limit int, sort string, includeStats bool) ([]models.Satpen, *PaginationMeta, *models.SatpenStatistics, error) {
+func (s *satpenService) GetAllSatpen(filter *models.SatpenFilter, page, limit int, sort string, includeStats bool, fieldSet *SatpenFieldSet) ([]models.Satpen, *PaginationMeta, *models.SatpenStatistics, error) {
 	// Validate and set defaults for pagination
 	if page < 1 {
 		page = s.cfg.Pagination.DefaultPage
@@ -54,8 +80,10 @@ func (s *satpenService) GetAllSatpen(filters map[string]interface{}, page, limit
 		limit = s.cfg.Pagination.MaxLimit
 	}
 
+	s.applySearchDefaults(filter)
+
 	// Get data from repository
-	satpen, total, err := s.repo.FindAll(filters, page, limit, sort)
+	satpen, total, err := s.repo.FindAll(filter, page, limit, sort, fieldSet.projection())
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -78,7 +106,7 @@ func (s *satpenService) GetAllSatpen(filters map[string]interface{}, page, limit
 	// Get statistics only if requested (performance optimization)
 	var stats *models.SatpenStatistics
 	if includeStats {
-		stats, err = s.repo.GetStatistics(filters)
+		stats, err = s.repo.GetStatistics(filter, s.statisticsTopN(0))
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -87,10 +115,106 @@ func (s *satpenService) GetAllSatpen(filters map[string]interface{}, page, limit
 	return satpen, pagination, stats, nil
 }
 
-func (s *satpenService) GetSatpenByID(id string) (*models.Satpen, error) {
+func (s *satpenService) GetAllSatpenCursor(filter *models.SatpenFilter, cursor string, limit int, sort string, withCount, includeStats bool, fieldSet *SatpenFieldSet) ([]models.Satpen, *CursorPaginationMeta, *models.SatpenStatistics, error) {
+	if limit < 1 {
+		limit = s.cfg.Pagination.DefaultLimit
+	}
+	if limit > s.cfg.Pagination.MaxLimit {
+		limit = s.cfg.Pagination.MaxLimit
+	}
+	if _, _, err := repository.ParseKeysetSort(sort); err != nil {
+		return nil, nil, nil, ErrCursorSort
+	}
+
+	var keyset *repository.Keyset
+	if cursor != "" {
+		var err error
+		keyset, err = decodeCursor(cursor, sort)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	s.applySearchDefaults(filter)
+
+	// One extra row tells whether another page exists in the scan direction
+	satpen, err := s.repo.FindAllKeyset(filter, limit+1, sort, keyset, fieldSet.projection())
+	if err != nil {
+		if errors.Is(err, repository.ErrKeysetValue) {
+			return nil, nil, nil, ErrInvalidCursor
+		}
+		return nil, nil, nil, err
+	}
+
+	backward := keyset != nil && keyset.Backward
+	more := len(satpen) > limit
+	if more {
+		if backward {
+			satpen = satpen[1:]
+		} else {
+			satpen = satpen[:limit]
+		}
+	}
+
+	pagination := &CursorPaginationMeta{
+		ItemsPerPage: limit,
+		HasNext:      (!backward && more) || backward,
+		HasPrev:      (backward && more) || (!backward && keyset != nil),
+	}
+	if len(satpen) > 0 {
+		if pagination.HasNext {
+			pagination.NextCursor, err = encodeCursor(sort, &satpen[len(satpen)-1], false)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if pagination.HasPrev {
+			pagination.PrevCursor, err = encodeCursor(sort, &satpen[0], true)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+
+	if withCount {
+		total, err := s.repo.Count(filter)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pagination.TotalItems = &total
+	}
+
+	var stats *models.SatpenStatistics
+	if includeStats {
+		stats, err = s.repo.GetStatistics(filter, s.statisticsTopN(0))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return satpen, pagination, stats, nil
+}
+
+func (s *satpenService) GetSatpenByID(id string, fieldSet *SatpenFieldSet) (*models.Satpen, error) {
+	satpen, err := s.findSatpen(id, fieldSet)
+	if err != nil {
+		return nil, err
+	}
+
+	s.flagIndikator(satpen.Indikator)
+	if fieldSet.wants("data_quality") {
+		if err := s.checkDataQuality(satpen); err != nil {
+			return nil, err
+		}
+	}
+	return satpen, nil
+}
+
+// findSatpen loads a satpen by numeric ID or else by NPSN
+func (s *satpenService) findSatpen(id string, fieldSet *SatpenFieldSet) (*models.Satpen, error) {
 	// Try to parse as numeric ID first
 	if numericID, err := strconv.ParseUint(id, 10, 64); err == nil {
-		satpen, err := s.repo.FindByID(uint(numericID))
+		satpen, err := s.repo.FindByID(uint(numericID), fieldSet.projection())
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, errors.New("satuan pendidikan not found")
@@ -101,7 +225,7 @@ func (s *satpenService) GetSatpenByID(id string) (*models.Satpen, error) {
 	}
 
 	// Try to find by NPSN
-	satpen, err := s.repo.FindByNPSN(id)
+	satpen, err := s.repo.FindByNPSN(id, fieldSet.projection())
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("satuan pendidikan not found")
@@ -111,12 +235,53 @@ func (s *satpenService) GetSatpenByID(id string) (*models.Satpen, error) {
 	return satpen, nil
 }
 
-func (s *satpenService) GetStatistics(filters map[string]interface{}) (*models.SatpenStatistics, error) {
-	return s.repo.GetStatistics(filters)
+// GetStatistics summarises the satpen matching filter, with top lists of
+// topN entries (0: statistics.top_n, capped at statistics.max_top_n)
+func (s *satpenService) GetStatistics(filter *models.SatpenFilter, topN int) (*models.SatpenStatistics, error) {
+	s.applySearchDefaults(filter)
+	return s.repo.GetStatistics(filter, s.statisticsTopN(topN))
+}
+
+// statisticsTopN applies the statistics.top_n default and max_top_n cap
+func (s *satpenService) statisticsTopN(topN int) int {
+	if topN < 1 {
+		topN = s.cfg.Statistics.TopN
+	}
+	if topN < 1 {
+		topN = defaultStatisticsTopN
+	}
+	if s.cfg.Statistics.MaxTopN > 0 && topN > s.cfg.Statistics.MaxTopN {
+		topN = s.cfg.Statistics.MaxTopN
+	}
+	return topN
+}
+
+// IsValidSearchMode reports whether mode is accepted as search_mode (like, natural, boolean)
+func IsValidSearchMode(mode string) bool {
+	return repository.IsValidSearchMode(mode)
 }
 
-func (s *satpenService) ExportSatpen(filters map[string]interface{}, sort string) (*bytes.Buffer, string, error) {
-	satpenList, err := s.repo.FindAllForExport(filters, sort)
+// ValidateSort checks a comma separated sort against the sort allowlist
+func ValidateSort(sort string) error {
+	_, err := repository.ParseSort(sort)
+	return err
+}
+
+// applySearchDefaults fills the search mode from config when the client didn't pick one
+func (s *satpenService) applySearchDefaults(filter *models.SatpenFilter) {
+	if filter.Search == "" {
+		return
+	}
+	if filter.SearchMode == "" {
+		filter.SearchMode = s.cfg.Search.DefaultMode
+	}
+	filter.SearchMinTokenSize = s.cfg.Search.MinTokenSize
+}
+
+func (s *satpenService) ExportSatpen(filter *models.SatpenFilter, sort string) (*bytes.Buffer, string, error) {
+	s.applySearchDefaults(filter)
+
+	satpenList, err := s.repo.FindAllForExport(filter, sort, nil)
 	if err != nil {
 		return nil, "", err
 	}
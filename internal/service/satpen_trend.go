@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+	"time"
+)
+
+// maxTrendPeriods bounds the number of points of one trend
+const maxTrendPeriods = 240
+
+var (
+	// ErrInterval is returned for an interval other than month, quarter or year
+	ErrInterval = repository.ErrInterval
+	// ErrTrendRange is returned for a from/to range of too many periods
+	ErrTrendRange = fmt.Errorf("range must not span more than %d periods", maxTrendPeriods)
+	// ErrTrendFrom is returned for a from after to (or after today without to)
+	ErrTrendFrom = errors.New("from must not be after to")
+)
+
+// trendMonths is the length of each interval in months
+var trendMonths = map[string]int{
+	repository.IntervalMonth:   1,
+	repository.IntervalQuarter: 3,
+	repository.IntervalYear:    12,
+}
+
+// defaultTrendPeriods is how many periods back from to the trend starts
+// without from
+var defaultTrendPeriods = map[string]int{
+	repository.IntervalMonth:   12,
+	repository.IntervalQuarter: 8,
+	repository.IntervalYear:    10,
+}
+
+// IsValidInterval reports whether interval is month, quarter or year
+func IsValidInterval(interval string) bool {
+	_, ok := trendMonths[interval]
+	return ok
+}
+
+// GetTrend returns new registrations (tgl_registrasi), approvals
+// (actived_date) and expiries (actived_date + dashboard.registration_valid_years)
+// per interval between from and to, and the registrations in force at the end
+// of every period. Without a status filter satpen of every status count.
+func (s *satpenService) GetTrend(interval string, from, to *time.Time, filter *models.SatpenFilter) (*models.Trend, error) {
+	if !IsValidInterval(interval) {
+		return nil, ErrInterval
+	}
+
+	now := time.Now()
+	end := now
+	if to != nil {
+		end = *to
+	}
+	last := trendPeriodStart(end, interval)
+	first := addTrendPeriods(last, interval, 1-defaultTrendPeriods[interval])
+	if from != nil {
+		first = trendPeriodStart(*from, interval)
+	}
+	if first.After(last) {
+		return nil, ErrTrendFrom
+	}
+	n := trendPeriodIndex(first, last, interval) + 1
+	if n > maxTrendPeriods {
+		return nil, ErrTrendRange
+	}
+	until := addTrendPeriods(last, interval, 1)
+
+	if filter == nil {
+		filter = &models.SatpenFilter{}
+	}
+	s.applySearchDefaults(filter)
+	if len(filter.Status) == 0 {
+		filter.Status = models.SatpenStatuses
+	}
+
+	registered, err := s.repo.CountByPeriod(repository.EventRegistered, interval, filter, until, 0)
+	if err != nil {
+		return nil, err
+	}
+	approved, err := s.repo.CountByPeriod(repository.EventApproved, interval, filter, until, 0)
+	if err != nil {
+		return nil, err
+	}
+	// Without a validity period registrations never expire
+	var expired []models.PeriodCount
+	if years := s.cfg.Dashboard.RegistrationValidYears; years > 0 {
+		expiredUntil := until
+		if now.Before(expiredUntil) {
+			expiredUntil = now
+		}
+		expired, err = s.repo.CountByPeriod(repository.EventExpired, interval, filter, expiredUntil, years)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	trend := &models.Trend{
+		Interval: interval,
+		From:     first.Format("2006-01-02"),
+		To:       until.AddDate(0, 0, -1).Format("2006-01-02"),
+		Points:   make([]models.TrendPoint, n),
+	}
+	for i, start := 0, first; i < n; i, start = i+1, addTrendPeriods(start, interval, 1) {
+		trend.Points[i] = models.TrendPoint{
+			Period: trendPeriodLabel(start, interval),
+			Start:  start.Format("2006-01-02"),
+		}
+	}
+
+	// Approvals and expiries before the first period give the active baseline
+	var active int64
+	add := func(counts []models.PeriodCount, sign int64, set func(p *models.TrendPoint, count int64)) {
+		for _, count := range counts {
+			start := periodCountStart(count, interval)
+			if start.Before(first) {
+				active += sign * count.Count
+				continue
+			}
+			if i := trendPeriodIndex(first, start, interval); i < n {
+				set(&trend.Points[i], count.Count)
+			}
+		}
+	}
+	add(registered, 0, func(p *models.TrendPoint, count int64) { p.Registrations = count })
+	add(approved, 1, func(p *models.TrendPoint, count int64) { p.Approvals = count })
+	add(expired, -1, func(p *models.TrendPoint, count int64) { p.Expiries = count })
+
+	for i := range trend.Points {
+		point := &trend.Points[i]
+		active += point.Approvals - point.Expiries
+		point.Active = active
+		trend.Registrations += point.Registrations
+		trend.Approvals += point.Approvals
+		trend.Expiries += point.Expiries
+	}
+	return trend, nil
+}
+
+// trendPeriodStart returns the first day of the period containing t
+func trendPeriodStart(t time.Time, interval string) time.Time {
+	months := trendMonths[interval]
+	month := (int(t.Month())-1)/months*months + 1
+	return time.Date(t.Year(), time.Month(month), 1, 0, 0, 0, 0, time.Local)
+}
+
+func addTrendPeriods(start time.Time, interval string, k int) time.Time {
+	return start.AddDate(0, k*trendMonths[interval], 0)
+}
+
+// trendPeriodIndex returns the number of periods from first to start
+func trendPeriodIndex(first, start time.Time, interval string) int {
+	months := (start.Year()-first.Year())*12 + int(start.Month()) - int(first.Month())
+	return months / trendMonths[interval]
+}
+
+func periodCountStart(count models.PeriodCount, interval string) time.Time {
+	month := 1
+	switch interval {
+	case repository.IntervalMonth:
+		month = count.Part
+	case repository.IntervalQuarter:
+		month = (count.Part-1)*3 + 1
+	}
+	return time.Date(count.Year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+}
+
+func trendPeriodLabel(start time.Time, interval string) string {
+	switch interval {
+	case repository.IntervalMonth:
+		return start.Format("2006-01")
+	case repository.IntervalQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	default:
+		return start.Format("2006")
+	}
+}
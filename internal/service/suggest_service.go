@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"satpen-api/internal/config"
+	"satpen-api/internal/repository"
+	"satpen-api/internal/suggest"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type SuggestService interface {
+	// Refresh rebuilds the index from the database
+	Refresh() error
+	// Start rebuilds the index every cfg.Suggest.RefreshInterval until ctx is done
+	Start(ctx context.Context)
+	Suggest(query string, limit int) ([]suggest.Match, error)
+}
+
+type suggestService struct {
+	repo  repository.SatpenRepository
+	cfg   *config.Config
+	log   *logrus.Logger
+	index atomic.Pointer[suggest.Index]
+}
+
+func NewSuggestService(repo repository.SatpenRepository, cfg *config.Config, log *logrus.Logger) SuggestService {
+	return &suggestService{
+		repo: repo,
+		cfg:  cfg,
+		log:  log,
+	}
+}
+
+func (s *suggestService) Refresh() error {
+	docs, err := s.repo.FindSuggestDocuments()
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+	s.index.Store(suggest.Build(docs))
+	s.log.WithFields(logrus.Fields{
+		"documents": len(docs),
+		"took_ms":   time.Since(started).Milliseconds(),
+	}).Info("Suggest index rebuilt")
+	return nil
+}
+
+func (s *suggestService) Start(ctx context.Context) {
+	interval := time.Duration(s.cfg.Suggest.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Refresh(); err != nil {
+				// Keep serving the previous index
+				s.log.WithError(err).Error("Failed to refresh suggest index")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *suggestService) Suggest(query string, limit int) ([]suggest.Match, error) {
+	index := s.index.Load()
+	if index == nil {
+		return nil, errors.New("suggest index not ready")
+	}
+
+	if limit < 1 {
+		limit = s.cfg.Suggest.DefaultLimit
+	}
+	if s.cfg.Suggest.MaxLimit > 0 && limit > s.cfg.Suggest.MaxLimit {
+		limit = s.cfg.Suggest.MaxLimit
+	}
+
+	return index.Search(query, limit), nil
+}
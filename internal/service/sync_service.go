@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"satpen-api/internal/audit"
+	"satpen-api/internal/config"
+	"satpen-api/internal/dapodik"
+	"satpen-api/internal/models"
+	"satpen-api/internal/repository"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type SyncService interface {
+	// Start runs scheduled pulls every cfg.Dapodik.Interval until ctx is done
+	Start(ctx context.Context)
+	// RunOnce pulls one batch of stale schools
+	RunOnce(ctx context.Context) (*SyncRunResult, error)
+	// SyncSatpen pulls a single school immediately, even while it is
+	// postponed after failed runs. It makes one attempt without retries,
+	// bounded by adminSyncTimeout, as it runs inside an HTTP request.
+	SyncSatpen(ctx context.Context, id uint) (*SyncResult, error)
+}
+
+type SyncResult struct {
+	IDSatpen uint          `json:"id_satpen"`
+	NPSN     string        `json:"npsn"`
+	Tapel    string        `json:"tapel"`
+	Success  bool          `json:"success"`
+	Attempts int           `json:"attempts"`
+	Error    string        `json:"error,omitempty"`
+	PDPTK    *models.PDPTK `json:"pdptk,omitempty"`
+}
+
+type SyncRunResult struct {
+	Tapel     string    `json:"tapel"`
+	StartedAt time.Time `json:"started_at"`
+	Processed int       `json:"processed"`
+	Succeeded int       `json:"succeeded"`
+	Failed    int       `json:"failed"`
+	Skipped   int       `json:"skipped"`
+}
+
+// syncBackoff tracks consecutive failed runs of one school
+type syncBackoff struct {
+	failures    int
+	nextAttempt time.Time
+}
+
+// syncActor is the audit actor of scheduled sync runs
+const syncActor = "dapodik-sync"
+
+// adminSyncTimeout bounds SyncSatpen below the 15s WriteTimeout of the HTTP
+// server, so the client gets the outcome instead of a cut connection
+const adminSyncTimeout = 10 * time.Second
+
+type syncService struct {
+	repo   repository.SyncRepository
+	client dapodik.Client
+	cfg    *config.Config
+	log    *logrus.Logger
+
+	running sync.Mutex
+	mu      sync.Mutex
+	backoff map[uint]*syncBackoff
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+func NewSyncService(repo repository.SyncRepository, client dapodik.Client, cfg *config.Config, log *logrus.Logger) SyncService {
+	return &syncService{
+		repo:    repo,
+		client:  client,
+		cfg:     cfg,
+		log:     log,
+		backoff: make(map[uint]*syncBackoff),
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+func (s *syncService) Start(ctx context.Context) {
+	ctx = audit.WithInfo(ctx, audit.Info{Actor: syncActor})
+	interval := s.seconds(s.cfg.Dapodik.Interval, time.Hour)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.RunOnce(ctx); err != nil {
+			s.log.WithError(err).Error("Dapodik sync run failed")
+		} else if result != nil {
+			s.log.WithFields(logrus.Fields{
+				"tapel":     result.Tapel,
+				"processed": result.Processed,
+				"succeeded": result.Succeeded,
+				"failed":    result.Failed,
+				"skipped":   result.Skipped,
+			}).Info("Dapodik sync run finished")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *syncService) RunOnce(ctx context.Context) (*SyncRunResult, error) {
+	// Skip this tick if the previous run is still going
+	if !s.running.TryLock() {
+		return nil, nil
+	}
+	defer s.running.Unlock()
+
+	tapel, err := s.tapel()
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := s.cfg.Dapodik.BatchSize
+	if batchSize < 1 {
+		batchSize = 50
+	}
+	staleBefore := s.now().Add(-s.seconds(s.cfg.Dapodik.StaleAfter, 7*24*time.Hour))
+
+	candidates, err := s.repo.FindSatpenForSync(tapel, staleBefore, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncRunResult{Tapel: tapel, StartedAt: s.now()}
+	for _, satpen := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		if !s.due(satpen.IDSatpen) {
+			result.Skipped++
+			continue
+		}
+
+		res, err := s.pull(ctx, satpen, tapel, s.maxRetries())
+		if errors.Is(err, dapodik.ErrUnauthorized) {
+			// Every following request would fail the same way
+			return result, err
+		}
+		result.Processed++
+		if res != nil && res.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+func (s *syncService) SyncSatpen(ctx context.Context, id uint) (*SyncResult, error) {
+	satpen, err := s.repo.FindSatpen(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("satuan pendidikan not found")
+		}
+		return nil, err
+	}
+	if satpen.NPSN == "" {
+		return nil, errors.New("satuan pendidikan has no NPSN")
+	}
+
+	tapel, err := s.tapel()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, adminSyncTimeout)
+	defer cancel()
+
+	// Upstream failures are reported in the result; only infrastructure errors are returned
+	res, err := s.pull(ctx, *satpen, tapel, 0)
+	if res == nil || errors.Is(err, dapodik.ErrUnauthorized) {
+		return nil, err
+	}
+	return res, nil
+}
+
+// pull fetches one school, retrying up to retries times with backoff, and
+// records the outcome
+func (s *syncService) pull(ctx context.Context, satpen models.Satpen, tapel string, retries int) (*SyncResult, error) {
+	result := &SyncResult{IDSatpen: satpen.IDSatpen, NPSN: satpen.NPSN, Tapel: tapel}
+	base := s.seconds(s.cfg.Dapodik.BackoffBase, 2*time.Second)
+	maxDelay := s.seconds(s.cfg.Dapodik.BackoffMax, time.Hour)
+
+	var data *dapodik.PDPTK
+	var err error
+	for attempt := 1; attempt <= retries+1; attempt++ {
+		result.Attempts = attempt
+		data, err = s.client.FetchPDPTK(ctx, satpen.NPSN, tapel)
+		if err == nil || !dapodik.IsRetryable(err) || attempt > retries {
+			break
+		}
+		if sleepErr := s.sleep(ctx, dapodik.Backoff(base, maxDelay, attempt)); sleepErr != nil {
+			err = sleepErr
+			break
+		}
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		entry := audit.NewEntry(ctx, models.AuditActionSyncFailed, "pdptk", 0)
+		if markErr := s.repo.MarkSyncFailed(satpen.IDSatpen, tapel, entry); markErr != nil {
+			s.log.WithError(markErr).WithField("id_satpen", satpen.IDSatpen).Error("Failed to record sync failure")
+		}
+		s.recordFailure(satpen.IDSatpen, base, maxDelay, s.maxRetries())
+		s.log.WithError(err).WithFields(logrus.Fields{
+			"id_satpen": satpen.IDSatpen,
+			"npsn":      satpen.NPSN,
+			"attempts":  result.Attempts,
+		}).Warn("Dapodik sync failed")
+		return result, err
+	}
+
+	now := s.now()
+	idSatpen := satpen.IDSatpen
+	pdptk := &models.PDPTK{
+		IDSatpen:      &idSatpen,
+		Tapel:         tapel,
+		PDLK:          data.PDLK,
+		PDPR:          data.PDPR,
+		JmlPD:         data.PDLK + data.PDPR,
+		GuruLK:        data.GuruLK,
+		GuruPR:        data.GuruPR,
+		JmlGuru:       data.GuruLK + data.GuruPR,
+		TendikLK:      data.TendikLK,
+		TendikPR:      data.TendikPR,
+		JmlTendik:     data.TendikLK + data.TendikPR,
+		LastSinkron:   &now,
+		StatusSinkron: models.StatusSinkronBerhasil,
+	}
+	if err := s.repo.SavePDPTK(pdptk, audit.NewEntry(ctx, models.AuditActionSync, "pdptk", 0)); err != nil {
+		s.log.WithError(err).WithField("id_satpen", satpen.IDSatpen).Error("Failed to save synced PDPTK")
+		return nil, fmt.Errorf("failed to save pdptk: %w", err)
+	}
+
+	s.clearFailure(satpen.IDSatpen)
+	result.Success = true
+	result.PDPTK = pdptk
+	return result, nil
+}
+
+// maxRetries is dapodik.max_retries, the retries per school within a run
+func (s *syncService) maxRetries() int {
+	if s.cfg.Dapodik.MaxRetries < 0 {
+		return 0
+	}
+	return s.cfg.Dapodik.MaxRetries
+}
+
+func (s *syncService) tapel() (string, error) {
+	if s.cfg.Dapodik.Tapel != "" {
+		return s.cfg.Dapodik.Tapel, nil
+	}
+	tapel, err := s.repo.GetCurrentTapel()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current tapel: %w", err)
+	}
+	return tapel, nil
+}
+
+func (s *syncService) due(id uint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.backoff[id]
+	return !ok || !s.now().Before(state.nextAttempt)
+}
+
+// recordFailure postpones the school's next scheduled pull, continuing the
+// exponential sequence where the in-run retries stopped
+func (s *syncService) recordFailure(id uint, base, maxDelay time.Duration, maxRetries int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.backoff[id]
+	if !ok {
+		state = &syncBackoff{}
+		s.backoff[id] = state
+	}
+	state.failures++
+	state.nextAttempt = s.now().Add(dapodik.Backoff(base, maxDelay, maxRetries+state.failures+1))
+}
+
+func (s *syncService) clearFailure(id uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.backoff, id)
+}
+
+func (s *syncService) seconds(value int, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+	return time.Duration(value) * time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"satpen-api/internal/config"
+	"satpen-api/internal/dapodik"
+	"satpen-api/internal/dapodik/dapodiktest"
+	"satpen-api/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// fakeSyncRepository keeps PDPTK rows in memory, keyed by id_satpen
+type fakeSyncRepository struct {
+	tapel       string
+	satpen      []models.Satpen
+	staleBefore time.Time
+	limit       int
+	pdptk       map[uint]*models.PDPTK
+	entries     []*models.AuditLog
+}
+
+func (r *fakeSyncRepository) GetCurrentTapel() (string, error) {
+	return r.tapel, nil
+}
+
+func (r *fakeSyncRepository) FindSatpen(id uint) (*models.Satpen, error) {
+	for _, s := range r.satpen {
+		if s.IDSatpen == id {
+			return &s, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSyncRepository) FindSatpenForSync(tapel string, staleBefore time.Time, limit int) ([]models.Satpen, error) {
+	r.staleBefore, r.limit = staleBefore, limit
+	return r.satpen, nil
+}
+
+func (r *fakeSyncRepository) SavePDPTK(pdptk *models.PDPTK, entry *models.AuditLog) error {
+	r.pdptk[*pdptk.IDSatpen] = pdptk
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *fakeSyncRepository) MarkSyncFailed(idSatpen uint, tapel string, entry *models.AuditLog) error {
+	if existing, ok := r.pdptk[idSatpen]; ok {
+		existing.StatusSinkron = models.StatusSinkronGagal
+	}
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// fakeClock stands in for time.Now and the retry sleep; sleeping advances it
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+const syncTestToken = "secret"
+
+// newTestSyncService wires a syncService to a dapodiktest upstream holding
+// 20241 data for NPSN 20507001 and 20507002
+func newTestSyncService(t *testing.T, dapodikCfg config.DapodikConfig) (*syncService, *fakeSyncRepository, *dapodiktest.Server, *fakeClock) {
+	t.Helper()
+
+	upstream := dapodiktest.New(syncTestToken)
+	upstream.Put(dapodik.PDPTK{NPSN: "20507001", Tapel: "20241", PDLK: 85, PDPR: 95, GuruLK: 5, GuruPR: 7, TendikLK: 2, TendikPR: 1})
+	upstream.Put(dapodik.PDPTK{NPSN: "20507002", Tapel: "20241", PDLK: 160, PDPR: 160, GuruLK: 9, GuruPR: 11})
+	server := upstream.Start()
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{Dapodik: dapodikCfg}
+	cfg.Dapodik.BaseURL = server.URL
+	if cfg.Dapodik.Token == "" {
+		cfg.Dapodik.Token = syncTestToken
+	}
+
+	repo := &fakeSyncRepository{
+		tapel: "20241",
+		satpen: []models.Satpen{
+			{IDSatpen: 1, NPSN: "20507001", Status: "setujui"},
+			{IDSatpen: 2, NPSN: "20507002", Status: "setujui"},
+		},
+		pdptk: make(map[uint]*models.PDPTK),
+	}
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	clock := &fakeClock{now: time.Date(2024, 9, 2, 8, 0, 0, 0, time.UTC)}
+	svc := NewSyncService(repo, dapodik.NewHTTPClient(cfg), cfg, log).(*syncService)
+	svc.now = clock.Now
+	svc.sleep = clock.Sleep
+	return svc, repo, upstream, clock
+}
+
+// deadlineClient records the deadline of each request it forwards
+type deadlineClient struct {
+	dapodik.Client
+	deadlines []time.Duration
+}
+
+func (c *deadlineClient) FetchPDPTK(ctx context.Context, npsn, tapel string) (*dapodik.PDPTK, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.deadlines = append(c.deadlines, time.Until(deadline))
+	}
+	return c.Client.FetchPDPTK(ctx, npsn, tapel)
+}
+
+func TestPullRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     []int
+		maxRetries   int
+		wantSuccess  bool
+		wantAttempts int
+		wantSleeps   []time.Duration
+	}{
+		{"first attempt", nil, 3, true, 1, nil},
+		{"retried until success", []int{503, 429}, 3, true, 3, []time.Duration{2 * time.Second, 4 * time.Second}},
+		{"backoff capped", []int{500, 500, 500}, 3, true, 4, []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second}},
+		{"max retries cut-off", []int{503, 503, 503, 503}, 2, false, 3, []time.Duration{2 * time.Second, 4 * time.Second}},
+		{"no retries configured", []int{503}, 0, false, 1, nil},
+		{"client error not retried", []int{400}, 3, false, 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, upstream, clock := newTestSyncService(t, config.DapodikConfig{MaxRetries: tt.maxRetries, BackoffBase: 2, BackoffMax: 5})
+			upstream.FailNext("20507001", tt.failures...)
+
+			// pull returns the upstream error next to the result it recorded
+			result, err := svc.pull(context.Background(), repo.satpen[0], "20241", svc.maxRetries())
+			if result == nil || (err == nil) != tt.wantSuccess {
+				t.Fatalf("result %+v, err %v", result, err)
+			}
+			if result.Success != tt.wantSuccess || result.Attempts != tt.wantAttempts {
+				t.Errorf("success %v after %d attempts, want %v after %d", result.Success, result.Attempts, tt.wantSuccess, tt.wantAttempts)
+			}
+			if got := upstream.Requests("20507001"); got != tt.wantAttempts {
+				t.Errorf("upstream saw %d requests, want %d", got, tt.wantAttempts)
+			}
+			if !reflect.DeepEqual(clock.sleeps, tt.wantSleeps) {
+				t.Errorf("slept %v, want %v", clock.sleeps, tt.wantSleeps)
+			}
+
+			stored, saved := repo.pdptk[1]
+			if saved != tt.wantSuccess {
+				t.Fatalf("pdptk saved = %v, want %v", saved, tt.wantSuccess)
+			}
+			if !tt.wantSuccess {
+				if result.Error == "" || len(repo.entries) != 1 || repo.entries[0].Action != models.AuditActionSyncFailed {
+					t.Errorf("error %q, audit entries %+v; want a recorded failure", result.Error, repo.entries)
+				}
+				return
+			}
+			if stored.StatusSinkron != models.StatusSinkronBerhasil || stored.Tapel != "20241" {
+				t.Errorf("stored status_sinkron %d, tapel %q", stored.StatusSinkron, stored.Tapel)
+			}
+			if stored.JmlPD != 180 || stored.JmlGuru != 12 || stored.JmlTendik != 3 {
+				t.Errorf("stored totals pd %d, guru %d, tendik %d; want 180, 12, 3", stored.JmlPD, stored.JmlGuru, stored.JmlTendik)
+			}
+			if stored.LastSinkron == nil || !stored.LastSinkron.Equal(clock.now) {
+				t.Errorf("last_sinkron = %v, want %v", stored.LastSinkron, clock.now)
+			}
+		})
+	}
+}
+
+func TestSyncSatpenSingleAttempt(t *testing.T) {
+	svc, repo, upstream, clock := newTestSyncService(t, config.DapodikConfig{MaxRetries: 3, BackoffBase: 2})
+	client := &deadlineClient{Client: svc.client}
+	svc.client = client
+	upstream.FailNext("20507001", 503)
+
+	result, err := svc.SyncSatpen(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success || result.Attempts != 1 {
+		t.Errorf("success %v after %d attempts, want a failure after 1", result.Success, result.Attempts)
+	}
+	if got := upstream.Requests("20507001"); got != 1 {
+		t.Errorf("upstream saw %d requests, want 1", got)
+	}
+	if len(clock.sleeps) != 0 {
+		t.Errorf("slept %v, want no retries", clock.sleeps)
+	}
+	if len(repo.entries) != 1 || repo.entries[0].Action != models.AuditActionSyncFailed {
+		t.Errorf("audit entries %+v, want one recorded failure", repo.entries)
+	}
+	if len(client.deadlines) != 1 || client.deadlines[0] <= 0 || client.deadlines[0] > adminSyncTimeout {
+		t.Errorf("request deadlines %v, want one within %v", client.deadlines, adminSyncTimeout)
+	}
+}
+
+func TestSyncSatpenMarksFailedRow(t *testing.T) {
+	svc, repo, upstream, _ := newTestSyncService(t, config.DapodikConfig{MaxRetries: 1, BackoffBase: 1})
+	if _, err := svc.SyncSatpen(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	upstream.FailNext("20507001", 503)
+
+	result, err := svc.SyncSatpen(context.Background(), 1)
+	if err != nil || result.Success {
+		t.Fatalf("result %+v, err %v; want a failed pull", result, err)
+	}
+	if got := repo.pdptk[1].StatusSinkron; got != models.StatusSinkronGagal {
+		t.Errorf("status_sinkron = %d, want %d", got, models.StatusSinkronGagal)
+	}
+}
+
+func TestSyncSatpenErrors(t *testing.T) {
+	svc, repo, _, _ := newTestSyncService(t, config.DapodikConfig{})
+	repo.satpen = append(repo.satpen, models.Satpen{IDSatpen: 3, Status: "setujui"})
+
+	if _, err := svc.SyncSatpen(context.Background(), 99); err == nil || err.Error() != "satuan pendidikan not found" {
+		t.Errorf("unknown id: err = %v", err)
+	}
+	if _, err := svc.SyncSatpen(context.Background(), 3); err == nil || err.Error() != "satuan pendidikan has no NPSN" {
+		t.Errorf("no NPSN: err = %v", err)
+	}
+
+	svc, _, _, _ = newTestSyncService(t, config.DapodikConfig{Token: "wrong", MaxRetries: 3})
+	if _, err := svc.SyncSatpen(context.Background(), 1); !errors.Is(err, dapodik.ErrUnauthorized) {
+		t.Errorf("bad token: err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRunOnceBackoff(t *testing.T) {
+	svc, repo, upstream, clock := newTestSyncService(t, config.DapodikConfig{MaxRetries: 1, BackoffBase: 2, BackoffMax: 3600, StaleAfter: 86400, BatchSize: 10})
+	upstream.FailNext("20507001", 503, 503)
+	start := clock.now
+
+	result, err := svc.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tapel != "20241" || result.Processed != 2 || result.Succeeded != 1 || result.Failed != 1 || result.Skipped != 0 {
+		t.Errorf("first run = %+v", result)
+	}
+	if repo.limit != 10 || !repo.staleBefore.Equal(start.Add(-24*time.Hour)) {
+		t.Errorf("FindSatpenForSync(staleBefore %v, limit %d)", repo.staleBefore, repo.limit)
+	}
+
+	// One failed run postpones satpen 1 by the next step of the sequence
+	// after the in-run retry: Backoff(2s, 1h, 3) = 8s from the failure
+	failedAt := clock.now
+	clock.now = failedAt.Add(7 * time.Second)
+	result, err = svc.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != 1 || result.Processed != 1 {
+		t.Errorf("run during backoff = %+v, want satpen 1 skipped", result)
+	}
+	if got := upstream.Requests("20507001"); got != 2 {
+		t.Errorf("upstream saw %d requests for satpen 1, want 2", got)
+	}
+
+	clock.now = failedAt.Add(8 * time.Second)
+	result, err = svc.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != 0 || result.Succeeded != 2 {
+		t.Errorf("run after backoff = %+v, want both synced", result)
+	}
+	if _, postponed := svc.backoff[1]; postponed {
+		t.Error("backoff of satpen 1 not cleared after a successful pull")
+	}
+}
+
+func TestRunOnceStopsOnUnauthorized(t *testing.T) {
+	svc, _, _, clock := newTestSyncService(t, config.DapodikConfig{Token: "wrong", MaxRetries: 3})
+
+	result, err := svc.RunOnce(context.Background())
+	if !errors.Is(err, dapodik.ErrUnauthorized) {
+		t.Fatalf("err = %v, want ErrUnauthorized", err)
+	}
+	if result.Processed != 0 || result.Failed != 0 {
+		t.Errorf("result %+v; the run must stop at the first rejected request", result)
+	}
+	// A rejected token is not retried
+	if len(clock.sleeps) != 0 {
+		t.Errorf("slept %v, want no retries", clock.sleeps)
+	}
+}
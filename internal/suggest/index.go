@@ -0,0 +1,177 @@
+// Package suggest implements an in-memory trigram index for typo-tolerant
+// autocomplete over satpen names.
+package suggest
+
+import (
+	"container/heap"
+	"strings"
+	"time"
+)
+
+// minScore drops weak matches that only share a couple of trigrams
+const minScore = 0.2
+
+// Document is one indexed satpen
+type Document struct {
+	ID        uint   `json:"id"`
+	NPSN      string `json:"npsn"`
+	Name      string `json:"nama"`
+	Jenjang   string `json:"jenjang,omitempty"`
+	Kabupaten string `json:"kabupaten,omitempty"`
+}
+
+// Match is a search hit with its similarity score in [0, 1.5]
+type Match struct {
+	Document
+	Score float64 `json:"score"`
+}
+
+// Index is immutable once built; rebuild and swap it to refresh
+type Index struct {
+	docs       []Document
+	normalized []string
+	gramCount  []int
+	postings   map[string][]int32
+	builtAt    time.Time
+}
+
+// Build indexes docs by the trigrams of their normalised names
+func Build(docs []Document) *Index {
+	idx := &Index{
+		docs:       docs,
+		normalized: make([]string, len(docs)),
+		gramCount:  make([]int, len(docs)),
+		postings:   make(map[string][]int32),
+		builtAt:    time.Now(),
+	}
+
+	for i, doc := range docs {
+		normalized := Normalize(doc.Name)
+		grams := trigrams(normalized)
+		idx.normalized[i] = normalized
+		idx.gramCount[i] = len(grams)
+		for _, gram := range grams {
+			idx.postings[gram] = append(idx.postings[gram], int32(i))
+		}
+	}
+	return idx
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	return len(idx.docs)
+}
+
+// BuiltAt returns when the index was built
+func (idx *Index) BuiltAt() time.Time {
+	return idx.builtAt
+}
+
+// Search returns up to limit documents most similar to query. Similarity is
+// the Dice coefficient over trigrams, boosted when the normalised name
+// contains the query or a word starting with the query's last word.
+func (idx *Index) Search(query string, limit int) []Match {
+	normalized := Normalize(query)
+	grams := trigrams(normalized)
+	if len(grams) == 0 || limit < 1 || len(idx.docs) == 0 {
+		return []Match{}
+	}
+
+	// Grams shared by many names ("maa", "mi ") would make nearly every name a
+	// candidate; treat those in more than a quarter of the index as common
+	common := len(idx.docs) / 4
+	rare := 0
+	for _, gram := range grams {
+		if n := len(idx.postings[gram]); n > 0 && n <= common {
+			rare++
+		}
+	}
+
+	// Rare grams collect candidates in the first pass; common grams only add to
+	// existing candidates in the second. Without rare grams every gram collects.
+	seedAll := rare == 0
+	shared := make([]uint16, len(idx.docs))
+	touched := make([]int32, 0, 256)
+	for pass := 0; pass < 2; pass++ {
+		for _, gram := range grams {
+			postings := idx.postings[gram]
+			isCommon := !seedAll && len(postings) > common
+			if isCommon != (pass == 1) {
+				continue
+			}
+			for _, docID := range postings {
+				if shared[docID] == 0 {
+					if isCommon {
+						continue
+					}
+					touched = append(touched, docID)
+				}
+				shared[docID]++
+			}
+		}
+	}
+
+	words := strings.Fields(normalized)
+	lastWord := words[len(words)-1]
+
+	top := make(matchHeap, 0, limit+1)
+	for _, docID := range touched {
+		score := 2 * float64(shared[docID]) / float64(len(grams)+idx.gramCount[docID])
+		if score+0.5 < minScore || len(top) == limit && score+0.5 <= top[0].Score {
+			continue
+		}
+		name := idx.normalized[docID]
+		if strings.Contains(name, normalized) {
+			score += 0.5
+		} else if hasWordPrefix(name, lastWord) {
+			score += 0.2
+		}
+		if score < minScore {
+			continue
+		}
+		match := Match{Document: idx.docs[docID], Score: score}
+		if len(top) < limit {
+			heap.Push(&top, match)
+		} else if top.less(top[0], match) {
+			top[0] = match
+			heap.Fix(&top, 0)
+		}
+	}
+
+	matches := make([]Match, len(top))
+	for i := len(top) - 1; i >= 0; i-- {
+		matches[i] = heap.Pop(&top).(Match)
+	}
+	return matches
+}
+
+// matchHeap is a min-heap keeping the best limit matches seen so far
+type matchHeap []Match
+
+// less orders a before b when a ranks lower: smaller score, then later name
+func (h matchHeap) less(a, b Match) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Name > b.Name
+}
+
+func (h matchHeap) Len() int            { return len(h) }
+func (h matchHeap) Less(i, j int) bool  { return h.less(h[i], h[j]) }
+func (h matchHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *matchHeap) Push(x interface{}) { *h = append(*h, x.(Match)) }
+func (h *matchHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func hasWordPrefix(normalized, prefix string) bool {
+	for _, word := range strings.Fields(normalized) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package suggest
+
+import (
+	"fmt"
+	"testing"
+)
+
+func testIndex() *Index {
+	return Build([]Document{
+		{ID: 1, NPSN: "20507001", Name: "MI Ma'arif NU 01 Singosari", Jenjang: "MI"},
+		{ID: 2, NPSN: "20507002", Name: "MTs NU Lawang", Jenjang: "MTs"},
+		{ID: 3, NPSN: "20507003", Name: "SMK Ma'arif Surabaya", Jenjang: "SMK"},
+		{ID: 4, NPSN: "20507004", Name: "Madrasah Ibtidaiyah Nurul Huda", Jenjang: "MI"},
+		{ID: 5, NPSN: "20507005", Name: "MTs Ma'arif Kudus", Jenjang: "MTs"},
+	})
+}
+
+func TestSearch(t *testing.T) {
+	idx := testIndex()
+
+	tests := []struct {
+		query string
+		want  uint // best match
+	}{
+		{"maarif singosari", 1},
+		{"Ma'arif Singosari", 1},
+		{"mi nurul huda", 4},                    // "Madrasah Ibtidaiyah" indexed as mi
+		{"Madrasah Tsanawiyah Maarif Kudus", 5}, // and the other way round
+		{"smk maarif surbaya", 3},               // typo
+		{"lawang", 2},
+		{"MTs NÜ Lawang", 2},
+	}
+	for _, tt := range tests {
+		matches := idx.Search(tt.query, 3)
+		if len(matches) == 0 || matches[0].ID != tt.want {
+			t.Errorf("Search(%q) = %+v, want %d first", tt.query, matches, tt.want)
+		}
+	}
+}
+
+func TestSearchLimitAndOrder(t *testing.T) {
+	idx := testIndex()
+
+	matches := idx.Search("maarif", 2)
+	if len(matches) != 2 {
+		t.Fatalf("Search(maarif, 2) returned %d matches", len(matches))
+	}
+	if matches[0].Score < matches[1].Score {
+		t.Errorf("matches not ordered by score: %+v", matches)
+	}
+
+	for _, query := range []string{"", "  ", "'"} {
+		if got := idx.Search(query, 5); len(got) != 0 {
+			t.Errorf("Search(%q) = %+v, want none", query, got)
+		}
+	}
+	if got := idx.Search("maarif", 0); len(got) != 0 {
+		t.Errorf("limit 0 returned %+v", got)
+	}
+	if got := idx.Search("xyzzy qwerty", 5); len(got) != 0 {
+		t.Errorf("unrelated query returned %+v", got)
+	}
+}
+
+// benchmarkNames returns n distinct satpen-like names
+func benchmarkNames(n int) []Document {
+	jenjang := []string{"MI", "MTs", "MA", "SMP", "SMA", "SMK", "RA", "Madrasah Ibtidaiyah"}
+	names := []string{"Ma'arif NU", "Nurul Huda", "Darul Ulum", "Al-Hikmah", "Islamiyah", "Sabilul Huda", "Miftahul Ulum", "Bustanul Ulum", "Raudlatul Ulum", "Hidayatul Mubtadiin"}
+	places := []string{"Singosari", "Lawang", "Kudus", "Jepara", "Sleman", "Surabaya", "Gresik", "Tuban", "Lamongan", "Jombang", "Kediri", "Blitar", "Pati", "Demak", "Rembang"}
+
+	docs := make([]Document, 0, n)
+	for i := 0; len(docs) < n; i++ {
+		name := fmt.Sprintf("%s %s %02d %s",
+			jenjang[i%len(jenjang)], names[(i/len(jenjang))%len(names)], i%40+1, places[(i/7)%len(places)])
+		docs = append(docs, Document{ID: uint(i + 1), NPSN: fmt.Sprintf("%08d", 20500000+i), Name: name})
+	}
+	return docs
+}
+
+// BenchmarkSearch measures a query against an index of 15k names, the size
+// of the full satpen table; a search should stay within a few milliseconds
+func BenchmarkSearch(b *testing.B) {
+	idx := Build(benchmarkNames(15000))
+	queries := []string{"maarif singosari", "mi nurul huda 12", "darul ulm kudus", "smk", "madrasah ibtidaiyah al hikmah jombang"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Search(queries[i%len(queries)], 10)
+	}
+}
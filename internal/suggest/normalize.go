@@ -0,0 +1,92 @@
+package suggest
+
+import (
+	"strings"
+
+	"satpen-api/internal/textnorm"
+)
+
+// abbreviations maps long forms to the short form used in the index, so
+// "Madrasah Ibtidaiyah" and "MI" normalise to the same tokens. Longer phrases
+// come first so they win over their prefixes.
+var abbreviations = []struct {
+	long  []string
+	short string
+}{
+	{[]string{"sekolah", "menengah", "kejuruan"}, "smk"},
+	{[]string{"sekolah", "menengah", "atas"}, "sma"},
+	{[]string{"sekolah", "menengah", "pertama"}, "smp"},
+	{[]string{"sekolah", "dasar"}, "sd"},
+	{[]string{"madrasah", "ibtidaiyah"}, "mi"},
+	{[]string{"madrasah", "ibtidaiah"}, "mi"},
+	{[]string{"madrasah", "tsanawiyah"}, "mts"},
+	{[]string{"madrasah", "tsanawiyyah"}, "mts"},
+	{[]string{"madrasah", "aliyah"}, "ma"},
+	{[]string{"madrasah", "diniyah"}, "madin"},
+	{[]string{"raudhatul", "athfal"}, "ra"},
+	{[]string{"raudlatul", "athfal"}, "ra"},
+	{[]string{"taman", "kanak", "kanak"}, "tk"},
+	{[]string{"kelompok", "bermain"}, "kb"},
+	{[]string{"pondok", "pesantren"}, "ponpes"},
+	{[]string{"nahdlatul", "ulama"}, "nu"},
+	{[]string{"nahdatul", "ulama"}, "nu"},
+}
+
+// Normalize lowercases s, strips diacritics and apostrophes, collapses
+// punctuation to single spaces and shortens known jenjang phrases:
+//
+//	"Madrasah Ibtidaiyah Ma'arif NU" -> "mi maarif nu"
+func Normalize(s string) string {
+	return strings.Join(abbreviate(textnorm.Words(s)), " ")
+}
+
+func abbreviate(words []string) []string {
+	out := make([]string, 0, len(words))
+	for i := 0; i < len(words); {
+		matched := false
+		for _, abbr := range abbreviations {
+			if hasPhrase(words[i:], abbr.long) {
+				out = append(out, abbr.short)
+				i += len(abbr.long)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out = append(out, words[i])
+			i++
+		}
+	}
+	return out
+}
+
+func hasPhrase(words, phrase []string) bool {
+	if len(words) < len(phrase) {
+		return false
+	}
+	for i, w := range phrase {
+		if words[i] != w {
+			return false
+		}
+	}
+	return true
+}
+
+// trigrams returns the distinct trigrams of each word in normalized, padded
+// with spaces so short words and word starts still produce grams
+func trigrams(normalized string) []string {
+	seen := make(map[string]struct{})
+	grams := make([]string, 0, len(normalized)+2)
+	for _, word := range strings.Fields(normalized) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			gram := string(runes[i : i+3])
+			if _, ok := seen[gram]; ok {
+				continue
+			}
+			seen[gram] = struct{}{}
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
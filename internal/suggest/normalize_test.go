@@ -0,0 +1,47 @@
+package suggest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Madrasah Ibtidaiyah Ma'arif NU", "mi maarif nu"},
+		{"MI Maarif NU", "mi maarif nu"},
+		{"MI Ma’arif NU", "mi maarif nu"},
+		{"MI Ma`arif NU", "mi maarif nu"},
+		{"Madrasah Tsanawiyah Al-Hikmah", "mts al hikmah"},
+		{"MADRASAH  ALIYAH   Nahdlatul Ulama", "ma nu"},
+		{"Sekolah Menengah Kejuruan NU 1", "smk nu 1"},
+		{"Sekolah Menengah", "sekolah menengah"}, // incomplete phrase stays
+		{"Raudlatul Athfal Muslimat", "ra muslimat"},
+		{"Taman Kanak-Kanak Dharma Wanita", "tk dharma wanita"},
+		{"Pondok Pesantren Tebuireng", "ponpes tebuireng"},
+		{"Madrasah Diniyah", "madin"},
+		{"MI Café Bérsama", "mi cafe bersama"},
+		{"SD Négeri Ünggul", "sd negeri unggul"},
+		{"MI NU 01/02 (Kota)", "mi nu 01 02 kota"},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTrigrams(t *testing.T) {
+	got := trigrams("mi nu")
+	want := []string{"  m", " mi", "mi ", "  n", " nu", "nu "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trigrams(mi nu) = %q, want %q", got, want)
+	}
+	// Repeated grams are returned once
+	if got := trigrams("aa aa"); len(got) != 3 {
+		t.Errorf("trigrams(aa aa) = %q, want 3 distinct grams", got)
+	}
+}
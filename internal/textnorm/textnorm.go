@@ -0,0 +1,34 @@
+// Package textnorm folds free-text names to comparable words, shared by the
+// satpen name suggestions and the region name keys.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Words lowercases s, strips diacritics and apostrophes and splits it on
+// everything that is not a letter or digit:
+//
+//	"Ma'árif  NU-01" -> ["maarif" "nu" "01"]
+func Words(s string) []string {
+	decomposed := norm.NFD.String(s)
+
+	var b strings.Builder
+	b.Grow(len(decomposed))
+	for _, r := range decomposed {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left over from NFD: é -> e
+		case r == '\'' || r == '`' || r == '’' || r == '‘':
+			// Ma'arif -> maarif
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
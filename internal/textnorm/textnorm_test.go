@@ -0,0 +1,25 @@
+package textnorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Ma'arif NU", []string{"maarif", "nu"}},
+		{"Ma’árif  NU-01", []string{"maarif", "nu", "01"}},
+		{"Ma`arif", []string{"maarif"}},
+		{"Kec.  Suka-Maju", []string{"kec", "suka", "maju"}},
+		{"Ünggul Café", []string{"unggul", "cafe"}},
+		{" (., ) ", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}